@@ -0,0 +1,61 @@
+package common
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// GatewayWorkloadNamespaces returns the sorted namespaces of the workloads selected by a gateway of a VirtualService.
+// The gateway is given as in the VirtualService spec, i.e. <gateway namespace>/<gateway name>.
+func GatewayWorkloadNamespaces(virtualService kubernetes.IstioObject, gateway string, gatewaysPerNamespace [][]kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) []string {
+	gwHost := kubernetes.ParseGatewayAsHost(gateway, virtualService.GetObjectMeta().Namespace, virtualService.GetObjectMeta().ClusterName)
+
+	namespaces := map[string]bool{}
+	for _, gwsNamespace := range gatewaysPerNamespace {
+		for _, gw := range gwsNamespace {
+			if gw.GetObjectMeta().Name == gwHost.Service && gw.GetObjectMeta().Namespace == gwHost.Namespace {
+				for _, ns := range selectedNamespaces(gw, workloadsPerNamespace) {
+					namespaces[ns] = true
+				}
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(namespaces))
+	for ns := range namespaces {
+		sorted = append(sorted, ns)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// selectedNamespaces returns the namespaces of the workloads selected by the gateway
+func selectedNamespaces(gateway kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) []string {
+	namespaces := make([]string, 0)
+
+	gwSelector, ok := gateway.GetSpec()["selector"].(map[string]interface{})
+	if !ok || len(gwSelector) == 0 {
+		return namespaces
+	}
+	selectorLabels := labels.Set{}
+	for k, v := range gwSelector {
+		if value, ok := v.(string); ok {
+			selectorLabels[k] = value
+		}
+	}
+	selector := labels.SelectorFromSet(selectorLabels)
+
+	for ns, workloadList := range workloadsPerNamespace {
+		for _, wl := range workloadList.Workloads {
+			if selector.Matches(labels.Set(wl.Labels)) {
+				namespaces = append(namespaces, ns)
+				break
+			}
+		}
+	}
+	return namespaces
+}
@@ -9,10 +9,13 @@ import (
 const DestinationRuleCheckerType = "destinationrule"
 
 type DestinationRulesChecker struct {
-	DestinationRules []kubernetes.IstioObject
-	MTLSDetails      kubernetes.MTLSDetails
-	ServiceEntries   []kubernetes.IstioObject
-	Namespaces       []models.Namespace
+	DestinationRules      []kubernetes.IstioObject
+	MTLSDetails           kubernetes.MTLSDetails
+	ServiceEntries        []kubernetes.IstioObject
+	Namespaces            []models.Namespace
+	VirtualServices       []kubernetes.IstioObject // the VirtualServices of every namespace
+	GatewaysPerNamespace  [][]kubernetes.IstioObject
+	WorkloadsPerNamespace map[string]models.WorkloadList
 }
 
 func (in DestinationRulesChecker) Check() models.IstioValidations {
@@ -62,6 +65,7 @@ func (in DestinationRulesChecker) runChecks(destinationRule kubernetes.IstioObje
 	enabledCheckers := []Checker{
 		destinationrules.DisabledNamespaceWideMTLSChecker{DestinationRule: destinationRule, MTLSDetails: in.MTLSDetails},
 		destinationrules.DisabledMeshWideMTLSChecker{DestinationRule: destinationRule, MeshPeerAuthns: in.MTLSDetails.MeshPeerAuthentications},
+		destinationrules.ExportToChecker{DestinationRule: destinationRule, VirtualServices: in.VirtualServices, GatewaysPerNamespace: in.GatewaysPerNamespace, WorkloadsPerNamespace: in.WorkloadsPerNamespace, Namespaces: in.Namespaces},
 	}
 
 	// Appending validations that only applies to non-autoMTLS meshes
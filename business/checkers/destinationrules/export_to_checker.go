@@ -0,0 +1,145 @@
+package destinationrules
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ExportToChecker validates that a DestinationRule with a restricted exportTo is visible from the namespaces of the
+// clients routing to its host: the sidecars of the namespaces of the VirtualServices bound to the mesh, and the
+// gateway workloads of the VirtualServices bound to gateways. A proxy ignores the DestinationRules not exported to
+// its namespace, so the traffic policy and subsets don't apply to its traffic. The VirtualServices are those of
+// every namespace, the clients of a host are often in other namespaces.
+type ExportToChecker struct {
+	DestinationRule       kubernetes.IstioObject
+	VirtualServices       []kubernetes.IstioObject
+	GatewaysPerNamespace  [][]kubernetes.IstioObject
+	WorkloadsPerNamespace map[string]models.WorkloadList
+	Namespaces            models.Namespaces
+}
+
+func (e ExportToChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	exportTo := kubernetes.GetExportTo(e.DestinationRule)
+	if len(exportTo) == 0 {
+		return checks, true
+	}
+	for _, export := range exportTo {
+		if export == "*" {
+			return checks, true
+		}
+	}
+
+	host, ok := e.DestinationRule.GetSpec()["host"].(string)
+	if !ok {
+		return checks, true
+	}
+	drNamespace := e.DestinationRule.GetObjectMeta().Namespace
+	drHost := kubernetes.GetHost(host, drNamespace, e.DestinationRule.GetObjectMeta().ClusterName, e.Namespaces.GetNames())
+
+	for _, clientNamespace := range e.clientNamespaces(drHost) {
+		if !kubernetes.IsExportedTo(exportTo, drNamespace, clientNamespace) {
+			check := models.Build("destinationrules.exportto.clientnotexported", "spec/exportTo")
+			checks = append(checks, &check)
+			break
+		}
+	}
+
+	return checks, true
+}
+
+// clientNamespaces returns the namespaces of the proxies the VirtualServices bind to a route to the host: the
+// VirtualService namespace for the mesh, the namespaces of the selected gateway workloads for the gateways. A gateway
+// workload ignores a VirtualService not exported to its namespace.
+func (e ExportToChecker) clientNamespaces(host kubernetes.Host) []string {
+	clientNamespaces := map[string]bool{}
+
+	for _, vs := range e.VirtualServices {
+		if !e.routesToHost(vs, host) {
+			continue
+		}
+		gateways, ok := vs.GetSpec()["gateways"].([]interface{})
+		if !ok || len(gateways) == 0 {
+			// a VirtualService without gateways is bound to the mesh
+			gateways = []interface{}{"mesh"}
+		}
+		for _, g := range gateways {
+			gateway, ok := g.(string)
+			if !ok {
+				continue
+			}
+			if gateway == "mesh" {
+				clientNamespaces[vs.GetObjectMeta().Namespace] = true
+				continue
+			}
+			for _, ns := range common.GatewayWorkloadNamespaces(vs, gateway, e.GatewaysPerNamespace, e.WorkloadsPerNamespace) {
+				if kubernetes.IsExportedTo(kubernetes.GetExportTo(vs), vs.GetObjectMeta().Namespace, ns) {
+					clientNamespaces[ns] = true
+				}
+			}
+		}
+	}
+
+	namespaces := make([]string, 0, len(clientNamespaces))
+	for ns := range clientNamespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func (e ExportToChecker) routesToHost(virtualService kubernetes.IstioObject, host kubernetes.Host) bool {
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, ok := virtualService.GetSpec()[protocol].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			destinations, ok := route["route"].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, d := range destinations {
+				destinationHost := destinationHost(d)
+				if destinationHost == "" {
+					continue
+				}
+				routeHost := kubernetes.GetHost(destinationHost, virtualService.GetObjectMeta().Namespace, virtualService.GetObjectMeta().ClusterName, e.Namespaces.GetNames())
+				if sameHost(routeHost, host) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func destinationHost(destination interface{}) string {
+	if mDestination, ok := destination.(map[string]interface{}); ok {
+		if destinationW, ok := mDestination["destination"].(map[string]interface{}); ok {
+			if host, ok := destinationW["host"].(string); ok {
+				return host
+			}
+		}
+	}
+	return ""
+}
+
+func sameHost(routeHost, drHost kubernetes.Host) bool {
+	if strings.HasPrefix(drHost.Service, "*") {
+		return kubernetes.HostWithinWildcardHost(routeHost.String(), drHost.Service)
+	}
+	if !routeHost.CompleteInput || !drHost.CompleteInput {
+		return routeHost.Service == drHost.Service
+	}
+	return routeHost.Service == drHost.Service && routeHost.Namespace == drHost.Namespace
+}
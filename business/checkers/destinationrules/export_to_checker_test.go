@@ -0,0 +1,114 @@
+package destinationrules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestExportedToGatewayNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	for _, exportTo := range [][]interface{}{nil, {"*"}, {".", "istio-system"}} {
+		validations, valid := exportToChecker(exportTo, "istio-system/ingress").Check()
+
+		assert.Empty(validations)
+		assert.True(valid)
+	}
+}
+
+func TestNotExportedToGatewayNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := exportToChecker([]interface{}{"."}, "istio-system/ingress").Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("destinationrules.exportto.clientnotexported"), validations[0].Message)
+	assert.Equal("spec/exportTo", validations[0].Path)
+}
+
+func TestVirtualServiceNotExportedToGatewayNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// The gateway ignores the VirtualService, only the DestinationRule namespace routes to the host
+	checker := exportToChecker([]interface{}{"."}, "istio-system/ingress")
+	checker.VirtualServices[0].GetSpec()["exportTo"] = []interface{}{"."}
+
+	validations, valid := checker.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestNotExportedWithoutGatewayClients(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// Only the sidecars of the DestinationRule namespace route to the host
+	validations, valid := exportToChecker([]interface{}{"."}, "mesh").Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestNotExportedToMeshClientNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// The sidecars of the frontend namespace route to the host
+	checker := exportToChecker([]interface{}{"."}, "mesh")
+	vs := data.AddRoutesToVirtualService("http", data.CreateRoute("reviews.bookinfo.svc.cluster.local", "v1", -1),
+		data.CreateEmptyVirtualService("reviews", "frontend", []string{"reviews.bookinfo.svc.cluster.local"}))
+	checker.VirtualServices = append(checker.VirtualServices, vs)
+	checker.Namespaces = append(checker.Namespaces, models.Namespace{Name: "frontend"})
+
+	validations, valid := checker.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("destinationrules.exportto.clientnotexported"), validations[0].Message)
+	assert.Equal("spec/exportTo", validations[0].Path)
+
+	// Exported to the frontend namespace
+	checker.DestinationRule.GetSpec()["exportTo"] = []interface{}{".", "frontend"}
+	validations, valid = checker.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func exportToChecker(exportTo []interface{}, gateway string) ExportToChecker {
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+	if exportTo != nil {
+		dr.GetSpec()["exportTo"] = exportTo
+	}
+
+	vs := data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+		data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"}))
+	vs.GetSpec()["gateways"] = []interface{}{gateway}
+
+	return ExportToChecker{
+		DestinationRule: dr,
+		VirtualServices: []kubernetes.IstioObject{vs},
+		GatewaysPerNamespace: [][]kubernetes.IstioObject{
+			{data.CreateEmptyGateway("ingress", "istio-system", map[string]string{"istio": "ingressgateway"})},
+		},
+		WorkloadsPerNamespace: map[string]models.WorkloadList{
+			"istio-system": data.CreateWorkloadList("istio-system",
+				data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})),
+			"bookinfo": data.CreateWorkloadList("bookinfo",
+				data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})),
+		},
+		Namespaces: models.Namespaces{{Name: "bookinfo"}, {Name: "istio-system"}},
+	}
+}
@@ -31,7 +31,12 @@ func (n NoDestinationChecker) Check() ([]*models.IstioCheck, bool) {
 			fqdn := kubernetes.GetHost(dHost, n.DestinationRule.GetObjectMeta().Namespace, n.DestinationRule.GetObjectMeta().ClusterName, n.Namespaces.GetNames())
 			// Testing Kubernetes Services + Istio ServiceEntries + Istio Runtime Registry (cross namespace)
 			if !n.hasMatchingService(fqdn, n.DestinationRule.GetObjectMeta().Namespace) {
-				validation := models.Build("destinationrules.nodest.matchingregistry", "spec/host")
+				checkId := "destinationrules.nodest.matchingregistry"
+				if kubernetes.HasMatchingRegistryStatus(fqdn.String(), n.RegistryStatus) {
+					// The host exists but it is not visible from the DestinationRule namespace
+					checkId = "destinationrules.nodest.notexported"
+				}
+				validation := models.Build(checkId, "spec/host")
 				valid = false
 				validations = append(validations, &validation)
 			} else if subsets, ok := n.DestinationRule.GetSpec()["subsets"]; ok {
@@ -141,8 +146,8 @@ func (n NoDestinationChecker) hasMatchingService(host kubernetes.Host, itemNames
 	}
 
 	// Use RegistryStatus to check destinations that may not be covered with previous check
-	// i.e. Multi-cluster or Federation validations
-	if kubernetes.HasMatchingRegistryStatus(host.String(), n.RegistryStatus) {
+	// i.e. Multi-cluster or Federation validations. The host must be exported to the DestinationRule namespace.
+	if kubernetes.HasMatchingRegistryStatusExportedTo(host.String(), itemNamespace, n.RegistryStatus) {
 		return true
 	}
 	return false
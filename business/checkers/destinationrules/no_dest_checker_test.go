@@ -151,6 +151,37 @@ func TestValidServiceNamespaceCrossNamespace(t *testing.T) {
 	assert.Empty(validations)
 }
 
+func TestCrossNamespaceHostNotExported(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	registryService := kubernetes.RegistryStatus{}
+	registryService.Hostname = "reviews.outside-ns.svc.cluster.local"
+	registryService.Attributes = map[string]interface{}{
+		"Namespace": "outside-ns",
+		"ExportTo":  map[string]interface{}{".": true},
+	}
+
+	validations, valid := NoDestinationChecker{
+		Namespace: "test-namespace",
+		Namespaces: models.Namespaces{
+			models.Namespace{Name: "test-namespace"},
+			models.Namespace{Name: "outside-ns"},
+		},
+		Services:        fakeServicesReview(),
+		DestinationRule: data.CreateTestDestinationRule("test-namespace", "name", "reviews.outside-ns.svc.cluster.local"),
+		RegistryStatus:  []*kubernetes.RegistryStatus{&registryService},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("destinationrules.nodest.notexported"), validations[0].Message)
+	assert.Equal("spec/host", validations[0].Path)
+}
+
 func TestNoValidHost(t *testing.T) {
 	conf := config.NewConfig()
 	config.Set(conf)
@@ -0,0 +1,72 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/envoyfilters"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const EnvoyFilterCheckerType = "envoyfilter"
+
+type EnvoyFilterChecker struct {
+	EnvoyFilters []kubernetes.IstioObject
+	ProxyStatus  []*kubernetes.ProxyStatus
+	WorkloadList models.WorkloadList
+}
+
+func (e EnvoyFilterChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	validations = validations.MergeValidations(e.runIndividualChecks())
+	validations = validations.MergeValidations(e.runGroupChecks())
+
+	return validations
+}
+
+func (e EnvoyFilterChecker) runGroupChecks() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	enabledCheckers := []GroupChecker{
+		envoyfilters.SamePriorityChecker{EnvoyFilters: e.EnvoyFilters},
+	}
+
+	for _, checker := range enabledCheckers {
+		validations = validations.MergeValidations(checker.Check())
+	}
+
+	return validations
+}
+
+func (e EnvoyFilterChecker) runIndividualChecks() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, envoyFilter := range e.EnvoyFilters {
+		validations.MergeValidations(e.runChecks(envoyFilter))
+	}
+
+	return validations
+}
+
+func (e EnvoyFilterChecker) runChecks(envoyFilter kubernetes.IstioObject) models.IstioValidations {
+	envoyFilterName := envoyFilter.GetObjectMeta().Name
+	key, rrValidation := EmptyValidValidation(envoyFilterName, envoyFilter.GetObjectMeta().Namespace, EnvoyFilterCheckerType)
+
+	enabledCheckers := []Checker{
+		envoyfilters.PatchChecker{EnvoyFilter: envoyFilter},
+		envoyfilters.ProxyVersionChecker{EnvoyFilter: envoyFilter, ProxyStatus: e.ProxyStatus},
+	}
+	// The workloads selected by a filter of the root namespace may be in any namespace
+	if !config.IsIstioNamespace(envoyFilter.GetObjectMeta().Namespace) {
+		enabledCheckers = append(enabledCheckers, common.WorkloadSelectorNoWorkloadFoundChecker(EnvoyFilterCheckerType, envoyFilter, e.WorkloadList))
+	}
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		rrValidation.Checks = append(rrValidation.Checks, checks...)
+		rrValidation.Valid = rrValidation.Valid && validChecker
+	}
+
+	return models.IstioValidations{key: rrValidation}
+}
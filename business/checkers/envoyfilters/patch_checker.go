@@ -0,0 +1,132 @@
+package envoyfilters
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// The objects patched by applyTo, by the match they accept
+var (
+	listenerObjects = map[string]bool{
+		"LISTENER":        true,
+		"FILTER_CHAIN":    true,
+		"NETWORK_FILTER":  true,
+		"HTTP_FILTER":     true,
+		"LISTENER_FILTER": true,
+	}
+	routeConfigurationObjects = map[string]bool{
+		"ROUTE_CONFIGURATION": true,
+		"VIRTUAL_HOST":        true,
+		"HTTP_ROUTE":          true,
+	}
+	clusterObjects = map[string]bool{
+		"CLUSTER": true,
+	}
+	otherObjects = map[string]bool{
+		"EXTENSION_CONFIG": true,
+		"BOOTSTRAP":        true,
+	}
+	// insertableObjects are the objects supporting the INSERT_BEFORE, INSERT_AFTER and INSERT_FIRST operations
+	insertableObjects = map[string]bool{
+		"NETWORK_FILTER":  true,
+		"HTTP_FILTER":     true,
+		"HTTP_ROUTE":      true,
+		"LISTENER_FILTER": true,
+	}
+	contexts = map[string]bool{
+		"ANY":              true,
+		"SIDECAR_INBOUND":  true,
+		"SIDECAR_OUTBOUND": true,
+		"GATEWAY":          true,
+	}
+	insertOperations = map[string]bool{
+		"INSERT_BEFORE": true,
+		"INSERT_AFTER":  true,
+		"INSERT_FIRST":  true,
+	}
+)
+
+// PatchChecker validates that each config patch applies to a known object, in a known context, and that its
+// match and operation are supported for that object
+type PatchChecker struct {
+	EnvoyFilter kubernetes.IstioObject
+}
+
+func (pc PatchChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	for i, patch := range configPatches(pc.EnvoyFilter) {
+		path := fmt.Sprintf("spec/configPatches[%d]", i)
+
+		applyTo, _ := patch["applyTo"].(string)
+		if !listenerObjects[applyTo] && !routeConfigurationObjects[applyTo] && !clusterObjects[applyTo] && !otherObjects[applyTo] {
+			check := models.Build("envoyfilter.patch.invalidapplyto", path+"/applyTo")
+			checks = append(checks, &check)
+			valid = false
+			continue
+		}
+
+		if match, ok := patch["match"].(map[string]interface{}); ok {
+			if context, found := match["context"]; found {
+				if c, ok := context.(string); !ok || !contexts[c] {
+					check := models.Build("envoyfilter.patch.invalidcontext", path+"/match/context")
+					checks = append(checks, &check)
+					valid = false
+				}
+			}
+
+			if !matchApplies(applyTo, match) {
+				check := models.Build("envoyfilter.patch.invalidmatch", path+"/match")
+				checks = append(checks, &check)
+				valid = false
+			}
+		}
+
+		if p, ok := patch["patch"].(map[string]interface{}); ok {
+			if operation, ok := p["operation"].(string); ok && insertOperations[operation] && !insertableObjects[applyTo] {
+				check := models.Build("envoyfilter.patch.invalidoperation", path+"/patch/operation")
+				checks = append(checks, &check)
+				valid = false
+			}
+		}
+	}
+
+	return checks, valid
+}
+
+// matchApplies returns false if the match selects a different kind of object than the applyTo object
+func matchApplies(applyTo string, match map[string]interface{}) bool {
+	_, listener := match["listener"]
+	_, routeConfiguration := match["routeConfiguration"]
+	_, cluster := match["cluster"]
+
+	switch {
+	case listenerObjects[applyTo]:
+		return !routeConfiguration && !cluster
+	case routeConfigurationObjects[applyTo]:
+		return !listener && !cluster
+	case clusterObjects[applyTo]:
+		return !listener && !routeConfiguration
+	}
+	return true
+}
+
+// configPatches returns the config patches of the EnvoyFilter, an invalid patch is returned empty
+func configPatches(envoyFilter kubernetes.IstioObject) []map[string]interface{} {
+	patches, ok := envoyFilter.GetSpec()["configPatches"].([]interface{})
+	if !ok {
+		return []map[string]interface{}{}
+	}
+
+	result := make([]map[string]interface{}, 0, len(patches))
+	for _, p := range patches {
+		patch, ok := p.(map[string]interface{})
+		if !ok {
+			patch = map[string]interface{}{}
+		}
+		result = append(result, patch)
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package envoyfilters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestValidPatches(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ef := data.CreateEnvoyFilter("ef1", "bookinfo")
+	ef = data.AddConfigPatchToEnvoyFilter("HTTP_FILTER", map[string]interface{}{
+		"context": "SIDECAR_INBOUND",
+		"listener": map[string]interface{}{
+			"portNumber": int64(8080),
+		},
+	}, "INSERT_BEFORE", ef)
+	ef = data.AddConfigPatchToEnvoyFilter("CLUSTER", map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"service": "reviews.bookinfo.svc.cluster.local",
+		},
+	}, "MERGE", ef)
+	ef = data.AddConfigPatchToEnvoyFilter("BOOTSTRAP", nil, "MERGE", ef)
+
+	validations, valid := PatchChecker{EnvoyFilter: ef}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestInvalidApplyTo(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ef := data.AddConfigPatchToEnvoyFilter("HTTP_FILTERS", nil, "MERGE", data.CreateEnvoyFilter("ef1", "bookinfo"))
+
+	validations, valid := PatchChecker{EnvoyFilter: ef}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("envoyfilter.patch.invalidapplyto"), validations[0].Message)
+	assert.Equal("spec/configPatches[0]/applyTo", validations[0].Path)
+}
+
+func TestInvalidContext(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ef := data.AddConfigPatchToEnvoyFilter("LISTENER", map[string]interface{}{
+		"context": "SIDECAR",
+	}, "MERGE", data.CreateEnvoyFilter("ef1", "bookinfo"))
+
+	validations, valid := PatchChecker{EnvoyFilter: ef}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("envoyfilter.patch.invalidcontext"), validations[0].Message)
+	assert.Equal("spec/configPatches[0]/match/context", validations[0].Path)
+}
+
+func TestInvalidMatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ef := data.CreateEnvoyFilter("ef1", "bookinfo")
+	ef = data.AddConfigPatchToEnvoyFilter("CLUSTER", map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"service": "reviews.bookinfo.svc.cluster.local",
+		},
+	}, "MERGE", ef)
+	ef = data.AddConfigPatchToEnvoyFilter("HTTP_ROUTE", map[string]interface{}{
+		"listener": map[string]interface{}{
+			"portNumber": int64(8080),
+		},
+	}, "MERGE", ef)
+
+	validations, valid := PatchChecker{EnvoyFilter: ef}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("envoyfilter.patch.invalidmatch"), validations[0].Message)
+	assert.Equal("spec/configPatches[1]/match", validations[0].Path)
+}
+
+func TestInvalidOperation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	ef := data.AddConfigPatchToEnvoyFilter("CLUSTER", nil, "INSERT_FIRST", data.CreateEnvoyFilter("ef1", "bookinfo"))
+
+	validations, valid := PatchChecker{EnvoyFilter: ef}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("envoyfilter.patch.invalidoperation"), validations[0].Message)
+	assert.Equal("spec/configPatches[0]/patch/operation", validations[0].Path)
+}
@@ -0,0 +1,67 @@
+package envoyfilters
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ProxyVersionChecker validates that the proxyVersion of each config patch matches the Istio version of a proxy
+// running in the mesh. The check is skipped when the proxy status is not available.
+type ProxyVersionChecker struct {
+	EnvoyFilter kubernetes.IstioObject
+	ProxyStatus []*kubernetes.ProxyStatus
+}
+
+func (pvc ProxyVersionChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	versions := map[string]bool{}
+	for _, ps := range pvc.ProxyStatus {
+		if ps != nil && ps.IstioVersion != "" {
+			versions[ps.IstioVersion] = true
+		}
+	}
+	if len(versions) == 0 {
+		return checks, true
+	}
+
+	for i, patch := range configPatches(pvc.EnvoyFilter) {
+		match, ok := patch["match"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		proxy, ok := match["proxy"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		proxyVersion, ok := proxy["proxyVersion"].(string)
+		if !ok || proxyVersion == "" {
+			continue
+		}
+
+		if !matchesAnyVersion(proxyVersion, versions) {
+			check := models.Build("envoyfilter.proxy.versionnotfound", fmt.Sprintf("spec/configPatches[%d]/match/proxy/proxyVersion", i))
+			checks = append(checks, &check)
+		}
+	}
+
+	return checks, true
+}
+
+// matchesAnyVersion returns true if the proxyVersion regex matches one of the versions, as Istio does an invalid
+// regex matches no version
+func matchesAnyVersion(proxyVersion string, versions map[string]bool) bool {
+	versionRegex, err := regexp.Compile(proxyVersion)
+	if err != nil {
+		return false
+	}
+	for version := range versions {
+		if versionRegex.MatchString(version) {
+			return true
+		}
+	}
+	return false
+}
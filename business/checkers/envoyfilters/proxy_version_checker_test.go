@@ -0,0 +1,69 @@
+package envoyfilters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestProxyVersionFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ProxyVersionChecker{
+		EnvoyFilter: proxyVersionEnvoyFilter("^1\\.8.*"),
+		ProxyStatus: proxyStatus("1.7.4", "1.8.1"),
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestProxyVersionNotFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ProxyVersionChecker{
+		EnvoyFilter: proxyVersionEnvoyFilter("^1\\.6.*"),
+		ProxyStatus: proxyStatus("1.7.4", "1.8.1"),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("envoyfilter.proxy.versionnotfound"), validations[0].Message)
+	assert.Equal("spec/configPatches[0]/match/proxy/proxyVersion", validations[0].Path)
+}
+
+func TestProxyVersionWithoutProxyStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ProxyVersionChecker{
+		EnvoyFilter: proxyVersionEnvoyFilter("^1\\.6.*"),
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func proxyVersionEnvoyFilter(proxyVersion string) kubernetes.IstioObject {
+	return data.AddConfigPatchToEnvoyFilter("CLUSTER", map[string]interface{}{
+		"proxy": map[string]interface{}{
+			"proxyVersion": proxyVersion,
+		},
+	}, "MERGE", data.CreateEnvoyFilter("ef1", "bookinfo"))
+}
+
+func proxyStatus(versions ...string) []*kubernetes.ProxyStatus {
+	proxyStatus := make([]*kubernetes.ProxyStatus, 0, len(versions))
+	for _, v := range versions {
+		proxyStatus = append(proxyStatus, &kubernetes.ProxyStatus{SyncStatus: kubernetes.SyncStatus{IstioVersion: v}})
+	}
+	return proxyStatus
+}
@@ -0,0 +1,118 @@
+package envoyfilters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util/intutil"
+)
+
+// SamePriorityChecker validates that the listener patches of different EnvoyFilters, applied to the same
+// workloads in the same context, don't share the same priority. Istio applies them in creation order.
+type SamePriorityChecker struct {
+	EnvoyFilters []kubernetes.IstioObject
+}
+
+type listenerPatch struct {
+	envoyFilter kubernetes.IstioObject
+	index       int
+}
+
+func (spc SamePriorityChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+	patchesByKey := map[string][]listenerPatch{}
+
+	for _, ef := range spc.EnvoyFilters {
+		for i, patch := range configPatches(ef) {
+			if key, ok := listenerPatchKey(ef, patch); ok {
+				patchesByKey[key] = append(patchesByKey[key], listenerPatch{envoyFilter: ef, index: i})
+			}
+		}
+	}
+
+	for _, patches := range patchesByKey {
+		for _, patch := range patches {
+			references := otherEnvoyFilters(patch.envoyFilter, patches)
+			if len(references) == 0 {
+				continue
+			}
+
+			key := models.IstioValidationKey{ObjectType: "envoyfilter", Name: patch.envoyFilter.GetObjectMeta().Name, Namespace: patch.envoyFilter.GetObjectMeta().Namespace}
+			check := models.Build("envoyfilter.listener.samepriority", fmt.Sprintf("spec/configPatches[%d]/match/listener", patch.index))
+			validations.MergeValidations(models.IstioValidations{key: &models.IstioValidation{
+				Name:       key.Name,
+				ObjectType: key.ObjectType,
+				Valid:      true,
+				Checks:     []*models.IstioCheck{&check},
+				References: references,
+			}})
+		}
+	}
+
+	return validations
+}
+
+// listenerPatchKey returns the key identifying the workloads, context, listener and priority of a listener patch.
+// Patches without an explicit listener match are ignored.
+func listenerPatchKey(envoyFilter kubernetes.IstioObject, patch map[string]interface{}) (string, bool) {
+	applyTo, _ := patch["applyTo"].(string)
+	if !listenerObjects[applyTo] {
+		return "", false
+	}
+	match, ok := patch["match"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	listener, ok := match["listener"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	name, _ := listener["name"].(string)
+	port, _ := intutil.Convert(listener["portNumber"])
+	if name == "" && port == 0 {
+		return "", false
+	}
+
+	context, ok := match["context"].(string)
+	if !ok || context == "" {
+		context = "ANY"
+	}
+	priority, _ := intutil.Convert(envoyFilter.GetSpec()["priority"])
+
+	return strings.Join([]string{
+		envoyFilter.GetObjectMeta().Namespace,
+		selectorString(common.GetWorkloadSelectorLabels(envoyFilter)),
+		context,
+		name,
+		fmt.Sprintf("%d", port),
+		fmt.Sprintf("%d", priority),
+	}, "|"), true
+}
+
+func selectorString(labels map[string]string) string {
+	selector := make([]string, 0, len(labels))
+	for k, v := range labels {
+		selector = append(selector, k+"="+v)
+	}
+	sort.Strings(selector)
+	return strings.Join(selector, ",")
+}
+
+// otherEnvoyFilters returns the keys of the patches EnvoyFilters other than envoyFilter
+func otherEnvoyFilters(envoyFilter kubernetes.IstioObject, patches []listenerPatch) []models.IstioValidationKey {
+	references := make([]models.IstioValidationKey, 0, len(patches))
+	seen := map[models.IstioValidationKey]bool{}
+	for _, p := range patches {
+		key := models.IstioValidationKey{ObjectType: "envoyfilter", Name: p.envoyFilter.GetObjectMeta().Name, Namespace: p.envoyFilter.GetObjectMeta().Namespace}
+		if key.Name == envoyFilter.GetObjectMeta().Name || seen[key] {
+			continue
+		}
+		seen[key] = true
+		references = append(references, key)
+	}
+	return references
+}
@@ -0,0 +1,77 @@
+package envoyfilters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestSamePriorityListenerPatches(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := SamePriorityChecker{
+		EnvoyFilters: []kubernetes.IstioObject{
+			listenerEnvoyFilter("ef1", 0),
+			listenerEnvoyFilter("ef2", 0),
+		},
+	}.Check()
+
+	assert.Len(validations, 2)
+	for _, name := range []string{"ef1", "ef2"} {
+		validation, ok := validations[models.IstioValidationKey{ObjectType: "envoyfilter", Name: name, Namespace: "bookinfo"}]
+		assert.True(ok)
+		assert.True(validation.Valid)
+		assert.Len(validation.Checks, 1)
+		assert.Equal(models.CheckMessage("envoyfilter.listener.samepriority"), validation.Checks[0].Message)
+		assert.Equal("spec/configPatches[0]/match/listener", validation.Checks[0].Path)
+		assert.Len(validation.References, 1)
+	}
+}
+
+func TestDifferentPriorityListenerPatches(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := SamePriorityChecker{
+		EnvoyFilters: []kubernetes.IstioObject{
+			listenerEnvoyFilter("ef1", 0),
+			listenerEnvoyFilter("ef2", 10),
+		},
+	}.Check()
+
+	assert.Empty(validations)
+}
+
+func TestSamePriorityDifferentWorkloads(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := SamePriorityChecker{
+		EnvoyFilters: []kubernetes.IstioObject{
+			listenerEnvoyFilter("ef1", 0),
+			data.AddSelectorToEnvoyFilter(map[string]interface{}{
+				"labels": map[string]interface{}{
+					"app": "reviews",
+				},
+			}, listenerEnvoyFilter("ef2", 0)),
+		},
+	}.Check()
+
+	assert.Empty(validations)
+}
+
+func listenerEnvoyFilter(name string, priority int64) kubernetes.IstioObject {
+	ef := data.AddConfigPatchToEnvoyFilter("HTTP_FILTER", map[string]interface{}{
+		"context": "SIDECAR_INBOUND",
+		"listener": map[string]interface{}{
+			"portNumber": int64(8080),
+		},
+	}, "INSERT_BEFORE", data.CreateEnvoyFilter(name, "bookinfo"))
+	return data.AddPriorityToEnvoyFilter(priority, ef)
+}
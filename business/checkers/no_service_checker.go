@@ -34,7 +34,7 @@ func (in NoServiceChecker) Check() models.IstioValidations {
 	gatewayNames := kubernetes.GatewayNames(in.GatewaysPerNamespace)
 
 	for _, virtualService := range in.IstioDetails.VirtualServices {
-		validations.MergeValidations(runVirtualServiceCheck(virtualService, in.Namespace, serviceNames, serviceHosts, in.Namespaces, in.RegistryStatus))
+		validations.MergeValidations(runVirtualServiceCheck(virtualService, in.Namespace, serviceNames, in.IstioDetails.ServiceEntries, in.Namespaces, in.RegistryStatus))
 		validations.MergeValidations(runGatewayCheck(virtualService, gatewayNames))
 	}
 	for _, destinationRule := range in.IstioDetails.DestinationRules {
@@ -43,16 +43,16 @@ func (in NoServiceChecker) Check() models.IstioValidations {
 	return validations
 }
 
-func runVirtualServiceCheck(virtualService kubernetes.IstioObject, namespace string, serviceNames []string, serviceHosts map[string][]string, clusterNamespaces models.Namespaces, registryStatus []*kubernetes.RegistryStatus) models.IstioValidations {
+func runVirtualServiceCheck(virtualService kubernetes.IstioObject, namespace string, serviceNames []string, serviceEntries []kubernetes.IstioObject, clusterNamespaces models.Namespaces, registryStatus []*kubernetes.RegistryStatus) models.IstioValidations {
 	key, validations := EmptyValidValidation(virtualService.GetObjectMeta().Name, virtualService.GetObjectMeta().Namespace, VirtualCheckerType)
 
 	result, valid := virtual_services.NoHostChecker{
-		Namespace:         namespace,
-		Namespaces:        clusterNamespaces,
-		ServiceNames:      serviceNames,
-		VirtualService:    virtualService,
-		ServiceEntryHosts: serviceHosts,
-		RegistryStatus:    registryStatus,
+		Namespace:      namespace,
+		Namespaces:     clusterNamespaces,
+		ServiceNames:   serviceNames,
+		VirtualService: virtualService,
+		ServiceEntries: serviceEntries,
+		RegistryStatus: registryStatus,
 	}.Check()
 
 	validations.Valid = valid
@@ -9,10 +9,12 @@ import (
 const VirtualCheckerType = "virtualservice"
 
 type VirtualServiceChecker struct {
-	Namespace        string
-	Namespaces       models.Namespaces
-	DestinationRules []kubernetes.IstioObject
-	VirtualServices  []kubernetes.IstioObject
+	Namespace             string
+	Namespaces            models.Namespaces
+	DestinationRules      []kubernetes.IstioObject
+	VirtualServices       []kubernetes.IstioObject
+	GatewaysPerNamespace  [][]kubernetes.IstioObject
+	WorkloadsPerNamespace map[string]models.WorkloadList
 }
 
 // An Object Checker runs all checkers for an specific object type (i.e.: pod, route rule,...)
@@ -61,7 +63,9 @@ func (in VirtualServiceChecker) runChecks(virtualService kubernetes.IstioObject)
 
 	enabledCheckers := []Checker{
 		virtual_services.RouteChecker{Route: virtualService},
+		virtual_services.ShadowedRouteChecker{VirtualService: virtualService},
 		virtual_services.SubsetPresenceChecker{Namespace: in.Namespace, Namespaces: in.Namespaces.GetNames(), DestinationRules: in.DestinationRules, VirtualService: virtualService},
+		virtual_services.ExportToChecker{VirtualService: virtualService, GatewaysPerNamespace: in.GatewaysPerNamespace, WorkloadsPerNamespace: in.WorkloadsPerNamespace},
 	}
 
 	for _, checker := range enabledCheckers {
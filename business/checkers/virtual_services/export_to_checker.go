@@ -0,0 +1,48 @@
+package virtual_services
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ExportToChecker validates that a VirtualService with a restricted exportTo is visible from the namespaces of the
+// workloads of its gateways. A gateway workload ignores the VirtualServices not exported to its namespace, e.g. a
+// VirtualService exported to "." is not applied by a gateway deployed in another namespace.
+type ExportToChecker struct {
+	VirtualService        kubernetes.IstioObject
+	GatewaysPerNamespace  [][]kubernetes.IstioObject
+	WorkloadsPerNamespace map[string]models.WorkloadList
+}
+
+func (e ExportToChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	exportTo := kubernetes.GetExportTo(e.VirtualService)
+	if len(exportTo) == 0 {
+		return checks, true
+	}
+
+	gateways, ok := e.VirtualService.GetSpec()["gateways"].([]interface{})
+	if !ok {
+		return checks, true
+	}
+	vsNamespace := e.VirtualService.GetObjectMeta().Namespace
+	for index, g := range gateways {
+		gateway, ok := g.(string)
+		if !ok || gateway == "mesh" {
+			continue
+		}
+		for _, ns := range common.GatewayWorkloadNamespaces(e.VirtualService, gateway, e.GatewaysPerNamespace, e.WorkloadsPerNamespace) {
+			if !kubernetes.IsExportedTo(exportTo, vsNamespace, ns) {
+				check := models.Build("virtualservices.exportto.gatewaynotexported", fmt.Sprintf("spec/gateways[%d]", index))
+				checks = append(checks, &check)
+				break
+			}
+		}
+	}
+
+	return checks, true
+}
@@ -0,0 +1,70 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestExportedToGatewayNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	for _, exportTo := range [][]interface{}{nil, {"*"}, {".", "istio-system"}} {
+		validations, valid := exportToChecker(exportTo, "mesh", "istio-system/ingress").Check()
+
+		assert.Empty(validations)
+		assert.True(valid)
+	}
+}
+
+func TestNotExportedToGatewayNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := exportToChecker([]interface{}{"."}, "mesh", "istio-system/ingress").Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("virtualservices.exportto.gatewaynotexported"), validations[0].Message)
+	assert.Equal("spec/gateways[1]", validations[0].Path)
+}
+
+func TestNotExportedToMeshOnly(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// The sidecars of the VirtualService namespace apply it, an unknown gateway is reported by the NoGatewayChecker
+	validations, valid := exportToChecker([]interface{}{"."}, "mesh", "istio-system/missing").Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func exportToChecker(exportTo []interface{}, gateways ...interface{}) ExportToChecker {
+	vs := data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+		data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"}))
+	vs.GetSpec()["gateways"] = gateways
+	if exportTo != nil {
+		vs.GetSpec()["exportTo"] = exportTo
+	}
+
+	return ExportToChecker{
+		VirtualService: vs,
+		GatewaysPerNamespace: [][]kubernetes.IstioObject{
+			{data.CreateEmptyGateway("ingress", "istio-system", map[string]string{"istio": "ingressgateway"})},
+		},
+		WorkloadsPerNamespace: map[string]models.WorkloadList{
+			"istio-system": data.CreateWorkloadList("istio-system",
+				data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})),
+			"bookinfo": data.CreateWorkloadList("bookinfo",
+				data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"})),
+		},
+	}
+}
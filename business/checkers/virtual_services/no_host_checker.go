@@ -9,12 +9,12 @@ import (
 )
 
 type NoHostChecker struct {
-	Namespace         string
-	Namespaces        models.Namespaces
-	ServiceNames      []string
-	VirtualService    kubernetes.IstioObject
-	ServiceEntryHosts map[string][]string
-	RegistryStatus    []*kubernetes.RegistryStatus
+	Namespace      string
+	Namespaces     models.Namespaces
+	ServiceNames   []string
+	VirtualService kubernetes.IstioObject
+	ServiceEntries []kubernetes.IstioObject
+	RegistryStatus []*kubernetes.RegistryStatus
 }
 
 func (n NoHostChecker) Check() ([]*models.IstioCheck, bool) {
@@ -39,7 +39,11 @@ func (n NoHostChecker) Check() ([]*models.IstioCheck, bool) {
 									if !n.checkDestination(host) {
 										fqdn := kubernetes.GetHost(host, n.VirtualService.GetObjectMeta().Namespace, n.VirtualService.GetObjectMeta().ClusterName, n.Namespaces.GetNames())
 										path := fmt.Sprintf("spec/%s[%d]/route[%d]/destination/host", protocol, k, i)
-										if fqdn.Namespace != n.VirtualService.GetObjectMeta().Namespace && fqdn.CompleteInput {
+										if n.hasMatchingServiceEntry(host, false) || kubernetes.HasMatchingRegistryStatus(host, n.RegistryStatus) {
+											validation := models.Build("virtualservices.nohost.notexported", path)
+											validations = append(validations, &validation)
+											valid = false
+										} else if fqdn.Namespace != n.VirtualService.GetObjectMeta().Namespace && fqdn.CompleteInput {
 											validation := models.Build("validation.unable.cross-namespace", path)
 											validations = append(validations, &validation)
 										} else {
@@ -91,18 +95,33 @@ func (n NoHostChecker) checkDestination(sHost string) bool {
 			}
 		}
 	}
-	// Check ServiceEntries
-	for k := range n.ServiceEntryHosts {
-		hostKey := k
-		if i := strings.Index(k, "*"); i > -1 {
-			hostKey = k[i+1:]
-		}
-		if strings.HasSuffix(sHost, hostKey) {
-			return true
-		}
+	// Check ServiceEntries exported to the VirtualService namespace
+	if n.hasMatchingServiceEntry(sHost, true) {
+		return true
 	}
 
 	// Use RegistryStatus to check destinations that may not be covered with previous check
-	// i.e. Multi-cluster or Federation validations
-	return kubernetes.HasMatchingRegistryStatus(sHost, n.RegistryStatus)
+	// i.e. Multi-cluster or Federation validations. The destination must be exported to the VirtualService namespace.
+	return kubernetes.HasMatchingRegistryStatusExportedTo(sHost, n.VirtualService.GetObjectMeta().Namespace, n.RegistryStatus)
+}
+
+// hasMatchingServiceEntry returns true if a ServiceEntry declares the host, when exported only the ServiceEntries
+// exported to the VirtualService namespace are considered
+func (n NoHostChecker) hasMatchingServiceEntry(sHost string, exported bool) bool {
+	vsNamespace := n.VirtualService.GetObjectMeta().Namespace
+	for _, se := range n.ServiceEntries {
+		if exported && !kubernetes.IsExportedTo(kubernetes.GetExportTo(se), se.GetObjectMeta().Namespace, vsNamespace) {
+			continue
+		}
+		for k := range kubernetes.ServiceEntryHostnames([]kubernetes.IstioObject{se}) {
+			hostKey := k
+			if i := strings.Index(k, "*"); i > -1 {
+				hostKey = k[i+1:]
+			}
+			if strings.HasSuffix(sHost, hostKey) {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -124,10 +124,10 @@ func TestValidServiceEntryHost(t *testing.T) {
 	serviceEntry := data.CreateExternalServiceEntry()
 
 	validations, valid = NoHostChecker{
-		Namespace:         "wikipedia",
-		ServiceNames:      []string{"my-wiki-rule"},
-		VirtualService:    virtualService,
-		ServiceEntryHosts: kubernetes.ServiceEntryHostnames([]kubernetes.IstioObject{serviceEntry}),
+		Namespace:      "wikipedia",
+		ServiceNames:   []string{"my-wiki-rule"},
+		VirtualService: virtualService,
+		ServiceEntries: []kubernetes.IstioObject{serviceEntry},
 	}.Check()
 
 	assert.True(valid)
@@ -156,10 +156,10 @@ func TestValidWildcardServiceEntryHost(t *testing.T) {
 	serviceEntry := data.CreateEmptyMeshExternalServiceEntry("googlecard", "google", []string{"*.google.com"})
 
 	validations, valid = NoHostChecker{
-		Namespace:         "google",
-		ServiceNames:      []string{"duckduckgo"},
-		VirtualService:    virtualService,
-		ServiceEntryHosts: kubernetes.ServiceEntryHostnames([]kubernetes.IstioObject{serviceEntry}),
+		Namespace:      "google",
+		ServiceNames:   []string{"duckduckgo"},
+		VirtualService: virtualService,
+		ServiceEntries: []kubernetes.IstioObject{serviceEntry},
 	}.Check()
 
 	assert.True(valid)
@@ -210,3 +210,81 @@ func TestValidServiceRegistry(t *testing.T) {
 	assert.False(valid)
 	assert.NotEmpty(validations)
 }
+
+func TestServiceRegistryNotExported(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	virtualService := data.AddRoutesToVirtualService(
+		"http",
+		data.CreateRoute("ratings.mesh2-bookinfo.svc.mesh1-imports.local", "v1", -1),
+		data.CreateEmptyVirtualService("federation-vs", "bookinfo", []string{"*"}))
+
+	registryService := kubernetes.RegistryStatus{}
+	registryService.Hostname = "ratings.mesh2-bookinfo.svc.mesh1-imports.local"
+	registryService.Attributes = map[string]interface{}{
+		"Namespace": "mesh2-bookinfo",
+		"ExportTo":  map[string]interface{}{".": true},
+	}
+	validations, valid := NoHostChecker{
+		Namespace:      "bookinfo",
+		ServiceNames:   []string{""},
+		VirtualService: virtualService,
+		RegistryStatus: []*kubernetes.RegistryStatus{&registryService},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("virtualservices.nohost.notexported"), validations[0].Message)
+	assert.Equal("spec/http[0]/route[0]/destination/host", validations[0].Path)
+
+	registryService.Attributes["ExportTo"] = map[string]interface{}{"bookinfo": true}
+	validations, valid = NoHostChecker{
+		Namespace:      "bookinfo",
+		ServiceNames:   []string{""},
+		VirtualService: virtualService,
+		RegistryStatus: []*kubernetes.RegistryStatus{&registryService},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestServiceEntryNotExported(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	virtualService := data.AddRoutesToVirtualService("http", data.CreateRoute("www.google.com", "v1", -1),
+		data.CreateEmptyVirtualService("googleIt", "bookinfo", []string{"www.google.com"}))
+	serviceEntry := data.CreateEmptyMeshExternalServiceEntry("googlecard", "google", []string{"www.google.com"})
+	serviceEntry.GetSpec()["exportTo"] = []interface{}{"."}
+
+	validations, valid := NoHostChecker{
+		Namespace:      "bookinfo",
+		ServiceNames:   []string{"reviews"},
+		VirtualService: virtualService,
+		ServiceEntries: []kubernetes.IstioObject{serviceEntry},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("virtualservices.nohost.notexported"), validations[0].Message)
+	assert.Equal("spec/http[0]/route[0]/destination/host", validations[0].Path)
+
+	serviceEntry.GetSpec()["exportTo"] = []interface{}{".", "bookinfo"}
+	validations, valid = NoHostChecker{
+		Namespace:      "bookinfo",
+		ServiceNames:   []string{"reviews"},
+		VirtualService: virtualService,
+		ServiceEntries: []kubernetes.IstioObject{serviceEntry},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
@@ -0,0 +1,244 @@
+package virtual_services
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ShadowedRouteChecker validates the order of the http routes. A route is unreachable when every one of its
+// match requests is already matched by the match requests of the previous routes, e.g. a route placed after a
+// catch-all route, or a uri prefix covered by a broader prefix of a previous route.
+type ShadowedRouteChecker struct {
+	VirtualService kubernetes.IstioObject
+}
+
+func (s ShadowedRouteChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	routes, ok := s.VirtualService.GetSpec()["http"].([]interface{})
+	if !ok {
+		return checks, true
+	}
+
+	previousMatches := make([]map[string]interface{}, 0)
+	for routeIdx, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		matches := routeMatches(route)
+		if len(matches) > 0 && allMatchesCovered(matches, previousMatches) {
+			check := models.Build("virtualservices.route.unreachable", fmt.Sprintf("spec/http[%d]/match", routeIdx))
+			checks = append(checks, &check)
+		}
+		previousMatches = append(previousMatches, matches...)
+	}
+
+	return checks, true
+}
+
+// routeMatches returns the match requests of the route, a route without match requests matches every request.
+// Nil is returned if a match request can't be read.
+func routeMatches(route map[string]interface{}) []map[string]interface{} {
+	matchList, ok := route["match"].([]interface{})
+	if !ok || len(matchList) == 0 {
+		return []map[string]interface{}{{}}
+	}
+
+	matches := make([]map[string]interface{}, 0, len(matchList))
+	for _, m := range matchList {
+		match, ok := m.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+func allMatchesCovered(matches, previousMatches []map[string]interface{}) bool {
+	for _, match := range matches {
+		covered := false
+		for _, previous := range previousMatches {
+			if matchCovers(previous, match) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// matchCovers returns true if every request matched by the match request b is matched by the match request a.
+// Unknown or unsupported conditions are never considered covered.
+func matchCovers(a, b map[string]interface{}) bool {
+	aIgnoreCase, _ := a["ignoreUriCase"].(bool)
+	bIgnoreCase, _ := b["ignoreUriCase"].(bool)
+
+	for field, aCondition := range a {
+		switch field {
+		case "name", "ignoreUriCase":
+			continue
+		case "uri":
+			// every uri starts with /
+			if isRootPrefix(aCondition) {
+				continue
+			}
+			if bIgnoreCase && !aIgnoreCase {
+				return false
+			}
+			if !stringMatchCovers(aCondition, b[field], aIgnoreCase) {
+				return false
+			}
+		case "scheme", "method", "authority":
+			if !stringMatchCovers(aCondition, b[field], false) {
+				return false
+			}
+		case "headers", "queryParams":
+			if !stringMatchMapCovers(aCondition, b[field]) {
+				return false
+			}
+		case "sourceLabels":
+			if !labelsCover(aCondition, b[field]) {
+				return false
+			}
+		case "gateways":
+			if !gatewaysCover(aCondition, b[field]) {
+				return false
+			}
+		case "port", "sourceNamespace", "withoutHeaders":
+			if !reflect.DeepEqual(aCondition, b[field]) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isRootPrefix(condition interface{}) bool {
+	stringMatch, ok := condition.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	prefix, ok := stringMatch["prefix"].(string)
+	return ok && (prefix == "" || prefix == "/")
+}
+
+// stringMatchCovers returns true if every string matched by the StringMatch b is matched by the StringMatch a
+func stringMatchCovers(a, b interface{}, ignoreCase bool) bool {
+	aKind, aValue, ok := parseStringMatch(a)
+	if !ok {
+		return false
+	}
+	bKind, bValue, ok := parseStringMatch(b)
+	if !ok {
+		return false
+	}
+	// regexes are compiled case insensitive rather than lowercased, that would change their character classes
+	regexFlags := ""
+	if ignoreCase {
+		if aKind != "regex" {
+			aValue = strings.ToLower(aValue)
+		}
+		if bKind != "regex" {
+			bValue = strings.ToLower(bValue)
+		}
+		regexFlags = "(?i)"
+	}
+
+	switch aKind {
+	case "exact":
+		return bKind == "exact" && aValue == bValue
+	case "prefix":
+		return (bKind == "exact" || bKind == "prefix") && strings.HasPrefix(bValue, aValue)
+	case "regex":
+		if bKind == "regex" {
+			return aValue == bValue
+		}
+		// Istio regexes match the full string
+		if bKind == "exact" {
+			matched, err := regexp.MatchString(regexFlags+"^(?:"+aValue+")$", bValue)
+			return err == nil && matched
+		}
+	}
+	return false
+}
+
+func parseStringMatch(condition interface{}) (kind string, value string, ok bool) {
+	stringMatch, isMap := condition.(map[string]interface{})
+	if !isMap {
+		return "", "", false
+	}
+	for _, kind := range []string{"exact", "prefix", "regex"} {
+		if v, found := stringMatch[kind]; found {
+			value, ok := v.(string)
+			return kind, value, ok
+		}
+	}
+	return "", "", false
+}
+
+func stringMatchMapCovers(a, b interface{}) bool {
+	aMap, ok := a.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	bMap, _ := b.(map[string]interface{})
+	for key, aCondition := range aMap {
+		bCondition, found := bMap[key]
+		if !found || !stringMatchCovers(aCondition, bCondition, false) {
+			return false
+		}
+	}
+	return true
+}
+
+func labelsCover(a, b interface{}) bool {
+	aLabels, ok := a.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	bLabels, _ := b.(map[string]interface{})
+	for key, value := range aLabels {
+		if bValue, found := bLabels[key]; !found || bValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// gatewaysCover returns true if b only applies to gateways a applies to
+func gatewaysCover(a, b interface{}) bool {
+	aGateways, ok := a.([]interface{})
+	if !ok {
+		return false
+	}
+	bGateways, ok := b.([]interface{})
+	if !ok || len(bGateways) == 0 {
+		return false
+	}
+	for _, bGateway := range bGateways {
+		found := false
+		for _, aGateway := range aGateways {
+			if aGateway == bGateway {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestReachableRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := shadowedRouteVirtualService(
+		[]interface{}{uriMatch("prefix", "/api/v2")},
+		[]interface{}{uriMatch("prefix", "/api")},
+		[]interface{}{headerMatch("end-user", "exact", "jason")},
+		nil,
+	)
+
+	validations, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestRouteAfterCatchAll(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := shadowedRouteVirtualService(
+		nil,
+		[]interface{}{headerMatch("end-user", "exact", "jason")},
+	)
+
+	validations, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("virtualservices.route.unreachable"), validations[0].Message)
+	assert.Equal("spec/http[1]/match", validations[0].Path)
+}
+
+func TestRouteCoveredByBroaderPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := shadowedRouteVirtualService(
+		[]interface{}{uriMatch("prefix", "/api")},
+		[]interface{}{uriMatch("prefix", "/api/v2"), uriMatch("exact", "/api")},
+		[]interface{}{uriMatch("prefix", "/api/v2"), uriMatch("exact", "/login")},
+	)
+
+	validations, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal("spec/http[1]/match", validations[0].Path)
+}
+
+func TestRouteCoveredByMatchesOfSeveralRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	jasonReviews := uriMatch("prefix", "/reviews")
+	jasonReviews["headers"] = map[string]interface{}{
+		"end-user": map[string]interface{}{"exact": "jason"},
+	}
+
+	vs := shadowedRouteVirtualService(
+		[]interface{}{uriMatch("exact", "/login")},
+		[]interface{}{headerMatch("end-user", "regex", "jason|mike")},
+		[]interface{}{uriMatch("exact", "/login"), jasonReviews},
+	)
+
+	validations, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal("spec/http[2]/match", validations[0].Path)
+}
+
+func TestIgnoreUriCaseRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	ignoreCase := uriMatch("prefix", "/API")
+	ignoreCase["ignoreUriCase"] = true
+
+	vs := shadowedRouteVirtualService(
+		[]interface{}{uriMatch("prefix", "/api")},
+		[]interface{}{ignoreCase},
+	)
+
+	validations, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestIgnoreUriCaseRegexRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	// the regex is matched case insensitive, not lowercased: \D would become \d
+	ignoreCase := uriMatch("regex", "/API/\\D+")
+	ignoreCase["ignoreUriCase"] = true
+
+	vs := shadowedRouteVirtualService(
+		[]interface{}{ignoreCase},
+		[]interface{}{uriMatch("exact", "/api/reviews")},
+	)
+
+	validations, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal("spec/http[1]/match", validations[0].Path)
+}
+
+func shadowedRouteVirtualService(matches ...[]interface{}) kubernetes.IstioObject {
+	vs := data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})
+	for _, match := range matches {
+		vs = data.AddHttpRouteWithMatchToVirtualService(match, data.CreateRoute("reviews", "v1", -1), vs)
+	}
+	return vs
+}
+
+func uriMatch(kind, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"uri": map[string]interface{}{kind: value},
+	}
+}
+
+func headerMatch(header, kind, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"headers": map[string]interface{}{
+			header: map[string]interface{}{kind: value},
+		},
+	}
+}
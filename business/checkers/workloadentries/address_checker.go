@@ -0,0 +1,68 @@
+package workloadentries
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// AddressChecker validates that no two WorkloadEntries of the same network share the same address, in any namespace
+type AddressChecker struct {
+	WorkloadEntries    []kubernetes.IstioObject // the validated WorkloadEntries
+	AllWorkloadEntries []kubernetes.IstioObject // the WorkloadEntries of every namespace, the validated ones if unset
+}
+
+type networkAddress struct {
+	network string
+	address string
+}
+
+func (ac AddressChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+	entriesByAddress := map[networkAddress][]kubernetes.IstioObject{}
+
+	allWorkloadEntries := ac.AllWorkloadEntries
+	if allWorkloadEntries == nil {
+		allWorkloadEntries = ac.WorkloadEntries
+	}
+	for _, we := range allWorkloadEntries {
+		if key, ok := weNetworkAddress(we); ok {
+			entriesByAddress[key] = append(entriesByAddress[key], we)
+		}
+	}
+
+	for _, we := range ac.WorkloadEntries {
+		address, ok := weNetworkAddress(we)
+		if !ok || len(entriesByAddress[address]) < 2 {
+			continue
+		}
+		entries := entriesByAddress[address]
+		key := models.IstioValidationKey{ObjectType: "workloadentry", Name: we.GetObjectMeta().Name, Namespace: we.GetObjectMeta().Namespace}
+		check := models.Build("workloadentry.address.duplicate", "spec/address")
+		validation := &models.IstioValidation{
+			Name:       key.Name,
+			ObjectType: key.ObjectType,
+			Valid:      true,
+			Checks:     []*models.IstioCheck{&check},
+			References: make([]models.IstioValidationKey, 0, len(entries)-1),
+		}
+		for _, ref := range entries {
+			refKey := models.IstioValidationKey{ObjectType: "workloadentry", Name: ref.GetObjectMeta().Name, Namespace: ref.GetObjectMeta().Namespace}
+			if refKey != key {
+				validation.References = append(validation.References, refKey)
+			}
+		}
+		validations.MergeValidations(models.IstioValidations{key: validation})
+	}
+
+	return validations
+}
+
+// weNetworkAddress returns the network and address of the WorkloadEntry, false if it has no address
+func weNetworkAddress(we kubernetes.IstioObject) (networkAddress, bool) {
+	address, _ := we.GetSpec()["address"].(string)
+	if address == "" {
+		return networkAddress{}, false
+	}
+	network, _ := we.GetSpec()["network"].(string)
+	return networkAddress{network: network, address: address}, true
+}
@@ -0,0 +1,74 @@
+package workloadentries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestDuplicateAddress(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := AddressChecker{
+		WorkloadEntries: []kubernetes.IstioObject{
+			data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "ratings"}),
+			data.CreateWorkloadEntry("vm2", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "reviews"}),
+			data.CreateWorkloadEntry("vm3", "bookinfo", "10.0.0.2", map[string]interface{}{"app": "details"}),
+		},
+	}.Check()
+
+	assert.Len(validations, 2)
+	for name, reference := range map[string]string{"vm1": "vm2", "vm2": "vm1"} {
+		validation, ok := validations[models.IstioValidationKey{ObjectType: "workloadentry", Name: name, Namespace: "bookinfo"}]
+		assert.True(ok)
+		assert.True(validation.Valid)
+		assert.Len(validation.Checks, 1)
+		assert.Equal(models.CheckMessage("workloadentry.address.duplicate"), validation.Checks[0].Message)
+		assert.Equal("spec/address", validation.Checks[0].Path)
+		assert.Equal([]models.IstioValidationKey{{ObjectType: "workloadentry", Name: reference, Namespace: "bookinfo"}}, validation.References)
+	}
+}
+
+func TestSameAddressInDifferentNetworks(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vm2 := data.CreateWorkloadEntry("vm2", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "reviews"})
+	vm2.GetSpec()["network"] = "vm-network"
+
+	validations := AddressChecker{
+		WorkloadEntries: []kubernetes.IstioObject{
+			data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "ratings"}),
+			vm2,
+		},
+	}.Check()
+
+	assert.Empty(validations)
+}
+
+func TestDuplicateAddressInOtherNamespace(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vm1 := data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "ratings"})
+	vm2 := data.CreateWorkloadEntry("vm2", "tutorial", "10.0.0.1", map[string]interface{}{"app": "customer"})
+
+	validations := AddressChecker{
+		WorkloadEntries:    []kubernetes.IstioObject{vm1},
+		AllWorkloadEntries: []kubernetes.IstioObject{vm1, vm2},
+	}.Check()
+
+	// only the validated WorkloadEntries are reported, referencing the other namespaces
+	assert.Len(validations, 1)
+	validation, ok := validations[models.IstioValidationKey{ObjectType: "workloadentry", Name: "vm1", Namespace: "bookinfo"}]
+	assert.True(ok)
+	assert.Len(validation.Checks, 1)
+	assert.Equal(models.CheckMessage("workloadentry.address.duplicate"), validation.Checks[0].Message)
+	assert.Equal([]models.IstioValidationKey{{ObjectType: "workloadentry", Name: "vm2", Namespace: "tutorial"}}, validation.References)
+}
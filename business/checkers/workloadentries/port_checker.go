@@ -0,0 +1,67 @@
+package workloadentries
+
+import (
+	"fmt"
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// PortChecker validates that the WorkloadEntry port names match a port name of the Services and ServiceEntries
+// selecting it. A port that matches no name is not used to reach the entry.
+type PortChecker struct {
+	WorkloadEntry  kubernetes.IstioObject
+	Services       []core_v1.Service
+	ServiceEntries []kubernetes.IstioObject
+}
+
+func (pc PortChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	ports, ok := pc.WorkloadEntry.GetSpec()["ports"].(map[string]interface{})
+	if !ok || len(ports) == 0 {
+		return checks, true
+	}
+
+	services, serviceEntries := selectingServices(pc.WorkloadEntry, pc.Services, pc.ServiceEntries)
+	if len(services) == 0 && len(serviceEntries) == 0 {
+		// Reported by the ServiceSelectorChecker
+		return checks, true
+	}
+
+	portNames := map[string]bool{}
+	for _, svc := range services {
+		for _, port := range svc.Spec.Ports {
+			portNames[port.Name] = true
+		}
+	}
+	for _, se := range serviceEntries {
+		if sePorts, ok := se.GetSpec()["ports"].([]interface{}); ok {
+			for _, p := range sePorts {
+				if port, ok := p.(map[string]interface{}); ok {
+					if name, ok := port["name"].(string); ok {
+						portNames[name] = true
+					}
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(ports))
+	for name := range ports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !portNames[name] {
+			check := models.Build("workloadentry.ports.nomatch", fmt.Sprintf("spec/ports/%s", name))
+			checks = append(checks, &check)
+		}
+	}
+
+	return checks, true
+}
@@ -0,0 +1,58 @@
+package workloadentries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestWorkloadEntryPortsMatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := PortChecker{
+		WorkloadEntry:  ratingsWorkloadEntry(map[string]interface{}{"http": int64(8080), "grpc": int64(8081)}),
+		Services:       []core_v1.Service{ratingsService()},
+		ServiceEntries: []kubernetes.IstioObject{ratingsServiceEntry()},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestWorkloadEntryPortsMismatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := PortChecker{
+		WorkloadEntry: ratingsWorkloadEntry(map[string]interface{}{"http": int64(8080), "http-web": int64(8081)}),
+		Services:      []core_v1.Service{ratingsService()},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workloadentry.ports.nomatch"), validations[0].Message)
+	assert.Equal("spec/ports/http-web", validations[0].Path)
+}
+
+func TestWorkloadEntryPortsWithoutService(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := PortChecker{
+		WorkloadEntry: ratingsWorkloadEntry(map[string]interface{}{"http-web": int64(8081)}),
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func ratingsWorkloadEntry(ports map[string]interface{}) kubernetes.IstioObject {
+	return data.AddPortsToWorkloadEntry(ports, data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "ratings"}))
+}
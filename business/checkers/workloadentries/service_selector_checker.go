@@ -0,0 +1,66 @@
+package workloadentries
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ServiceSelectorChecker validates that the WorkloadEntry labels are selected by a Service or by a ServiceEntry
+// workloadSelector of its namespace, otherwise the entry receives no traffic
+type ServiceSelectorChecker struct {
+	WorkloadEntry  kubernetes.IstioObject
+	Services       []core_v1.Service
+	ServiceEntries []kubernetes.IstioObject
+}
+
+func (ssc ServiceSelectorChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	services, serviceEntries := selectingServices(ssc.WorkloadEntry, ssc.Services, ssc.ServiceEntries)
+	if len(services) == 0 && len(serviceEntries) == 0 {
+		check := models.Build("workloadentry.labels.noservice", "spec/labels")
+		checks = append(checks, &check)
+	}
+
+	return checks, true
+}
+
+// selectingServices returns the Services and ServiceEntries selecting the WorkloadEntry labels
+func selectingServices(workloadEntry kubernetes.IstioObject, services []core_v1.Service, serviceEntries []kubernetes.IstioObject) ([]core_v1.Service, []kubernetes.IstioObject) {
+	selectingServices, selectingServiceEntries := []core_v1.Service{}, []kubernetes.IstioObject{}
+
+	weLabels := workloadEntryLabels(workloadEntry)
+	if len(weLabels) == 0 {
+		return selectingServices, selectingServiceEntries
+	}
+
+	for _, svc := range services {
+		if len(svc.Spec.Selector) > 0 && labels.SelectorFromSet(svc.Spec.Selector).Matches(weLabels) {
+			selectingServices = append(selectingServices, svc)
+		}
+	}
+	for _, se := range serviceEntries {
+		seSelector := common.GetWorkloadSelectorLabels(se)
+		if len(seSelector) > 0 && labels.SelectorFromSet(seSelector).Matches(weLabels) {
+			selectingServiceEntries = append(selectingServiceEntries, se)
+		}
+	}
+
+	return selectingServices, selectingServiceEntries
+}
+
+func workloadEntryLabels(workloadEntry kubernetes.IstioObject) labels.Set {
+	weLabels := labels.Set{}
+	if specLabels, ok := workloadEntry.GetSpec()["labels"].(map[string]interface{}); ok {
+		for k, v := range specLabels {
+			if value, ok := v.(string); ok {
+				weLabels[k] = value
+			}
+		}
+	}
+	return weLabels
+}
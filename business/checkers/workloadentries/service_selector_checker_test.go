@@ -0,0 +1,75 @@
+package workloadentries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestWorkloadEntrySelectedByService(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ServiceSelectorChecker{
+		WorkloadEntry: data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "ratings"}),
+		Services:      []core_v1.Service{ratingsService()},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestWorkloadEntrySelectedByServiceEntry(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ServiceSelectorChecker{
+		WorkloadEntry:  data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "ratings"}),
+		ServiceEntries: []kubernetes.IstioObject{ratingsServiceEntry()},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestWorkloadEntryNotSelected(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ServiceSelectorChecker{
+		WorkloadEntry:  data.CreateWorkloadEntry("vm1", "bookinfo", "10.0.0.1", map[string]interface{}{"app": "reviews"}),
+		Services:       []core_v1.Service{ratingsService()},
+		ServiceEntries: []kubernetes.IstioObject{ratingsServiceEntry()},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("workloadentry.labels.noservice"), validations[0].Message)
+	assert.Equal("spec/labels", validations[0].Path)
+}
+
+func ratingsService() core_v1.Service {
+	return core_v1.Service{
+		Spec: core_v1.ServiceSpec{
+			Selector: map[string]string{"app": "ratings"},
+			Ports: []core_v1.ServicePort{
+				{Name: "http", Port: 9080},
+			},
+		},
+	}
+}
+
+func ratingsServiceEntry() kubernetes.IstioObject {
+	se := data.CreateEmptyMeshExternalServiceEntry("ratings-vm", "bookinfo", []string{"ratings.bookinfo.svc.cluster.local"})
+	se.GetSpec()["workloadSelector"] = map[string]interface{}{
+		"labels": map[string]interface{}{"app": "ratings"},
+	}
+	return data.AddPortDefinitionToServiceEntry(data.CreateEmptyPortDefinition(9090, "grpc", "GRPC"), se)
+}
@@ -0,0 +1,41 @@
+package workloadgroups
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ServiceAccountChecker validates that the service account of the WorkloadGroup template exists in its namespace,
+// otherwise the workloads onboarded with the group can't get their identity. The check is skipped when the
+// service accounts are unknown.
+type ServiceAccountChecker struct {
+	WorkloadGroup   kubernetes.IstioObject
+	ServiceAccounts []string
+}
+
+func (sac ServiceAccountChecker) Check() ([]*models.IstioCheck, bool) {
+	checks := make([]*models.IstioCheck, 0)
+
+	if sac.ServiceAccounts == nil {
+		return checks, true
+	}
+
+	template, ok := sac.WorkloadGroup.GetSpec()["template"].(map[string]interface{})
+	if !ok {
+		return checks, true
+	}
+	serviceAccount, ok := template["serviceAccount"].(string)
+	if !ok || serviceAccount == "" {
+		return checks, true
+	}
+
+	for _, sa := range sac.ServiceAccounts {
+		if sa == serviceAccount {
+			return checks, true
+		}
+	}
+
+	check := models.Build("workloadgroup.template.serviceaccountnotfound", "spec/template/serviceAccount")
+	checks = append(checks, &check)
+	return checks, false
+}
@@ -0,0 +1,52 @@
+package workloadgroups
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestServiceAccountFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ServiceAccountChecker{
+		WorkloadGroup:   data.CreateWorkloadGroup("ratings-vm", "bookinfo", "bookinfo-ratings"),
+		ServiceAccounts: []string{"default", "bookinfo-ratings"},
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
+
+func TestServiceAccountNotFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ServiceAccountChecker{
+		WorkloadGroup:   data.CreateWorkloadGroup("ratings-vm", "bookinfo", "bookinfo-ratings"),
+		ServiceAccounts: []string{"default"},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal(models.CheckMessage("workloadgroup.template.serviceaccountnotfound"), validations[0].Message)
+	assert.Equal("spec/template/serviceAccount", validations[0].Path)
+}
+
+func TestServiceAccountsUnknown(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations, valid := ServiceAccountChecker{
+		WorkloadGroup: data.CreateWorkloadGroup("ratings-vm", "bookinfo", "bookinfo-ratings"),
+	}.Check()
+
+	assert.Empty(validations)
+	assert.True(valid)
+}
@@ -58,8 +58,11 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	var rbacDetails kubernetes.RBACDetails
 	var deployments []apps_v1.Deployment
 	var registryStatus []*kubernetes.RegistryStatus
+	var proxyStatus []*kubernetes.ProxyStatus
+	var serviceAccounts []string
+	var allVirtualServices []kubernetes.IstioObject
 
-	wg.Add(9) // We need to add these here to make sure we don't execute wg.Wait() before scheduler has started goroutines
+	wg.Add(12) // We need to add these here to make sure we don't execute wg.Wait() before scheduler has started goroutines
 
 	if service != "" {
 		// These resources are not used if no service is targeted
@@ -78,6 +81,9 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
 	go in.fetchServices(&services, namespace, errChan, &wg)
 	go in.fetchRegistryStatus(&registryStatus, errChan, &wg)
+	go in.fetchProxyStatus(&proxyStatus, &wg)
+	go in.fetchServiceAccounts(&serviceAccounts, namespace, errChan, &wg)
+	go in.fetchAllIstioObjects(&allVirtualServices, kubernetes.VirtualServices, errChan, &wg)
 
 	wg.Wait()
 	close(errChan)
@@ -87,7 +93,8 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
-	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus)
+	// WorkloadEntry addresses are compared within the namespace only, the mesh validations compare them across namespaces
+	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus, proxyStatus, serviceAccounts, allVirtualServices, nil)
 
 	if service != "" {
 		objectCheckers = append(objectCheckers, in.getServiceCheckers(namespace, services, deployments, pods)...)
@@ -95,6 +102,7 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 
 	// Get group validations for same kind istio objects
 	validations := runObjectCheckers(objectCheckers)
+	suppressValidations(validations, istioObjectsPerType(istioDetails, mtlsDetails, rbacDetails, gatewaysPerNamespace))
 	if service != "" {
 		validations = validations.FilterBySingleType("service", service)
 	}
@@ -102,23 +110,99 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	return validations, nil
 }
 
+// GetMeshValidations returns an IstioValidations object with the checks of the Istio objects of every
+// namespace accessible to the user. The mesh-wide details are fetched once and shared by the checkers of
+// every namespace.
+func (in *IstioValidationsService) GetMeshValidations() (models.IstioValidations, error) {
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	var namespaces models.Namespaces
+	var workloadsPerNamespace map[string]models.WorkloadList
+	var gatewaysPerNamespace [][]kubernetes.IstioObject
+	var meshMtlsDetails kubernetes.MTLSDetails
+	var registryStatus []*kubernetes.RegistryStatus
+	var proxyStatus []*kubernetes.ProxyStatus
+	var allVirtualServices []kubernetes.IstioObject
+	var allWorkloadEntries []kubernetes.IstioObject
+
+	wg.Add(8)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
+	go in.fetchMeshmTLSConfigs(&meshMtlsDetails, errChan, &wg)
+	go in.fetchRegistryStatus(&registryStatus, errChan, &wg)
+	go in.fetchProxyStatus(&proxyStatus, &wg)
+	go in.fetchAllIstioObjects(&allVirtualServices, kubernetes.VirtualServices, errChan, &wg)
+	go in.fetchAllIstioObjects(&allWorkloadEntries, kubernetes.WorkloadEntries, errChan, &wg)
+
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			return nil, e
+		}
+	}
+
+	meshValidations := models.IstioValidations{}
+	for _, namespace := range namespaces {
+		var istioDetails kubernetes.IstioDetails
+		var services []core_v1.Service
+		var rbacDetails kubernetes.RBACDetails
+		var serviceAccounts []string
+		mtlsDetails := meshMtlsDetails
+
+		nsWg := sync.WaitGroup{}
+		nsErrChan := make(chan error, 1)
+
+		nsWg.Add(5)
+		go in.fetchDetails(&istioDetails, namespace.Name, nsErrChan, &nsWg)
+		go in.fetchServices(&services, namespace.Name, nsErrChan, &nsWg)
+		go in.fetchPeerAuthentications(&mtlsDetails.PeerAuthentications, namespace.Name, nsErrChan, &nsWg)
+		go in.fetchAuthorizationDetails(&rbacDetails, namespace.Name, nsErrChan, &nsWg)
+		go in.fetchServiceAccounts(&serviceAccounts, namespace.Name, nsErrChan, &nsWg)
+
+		nsWg.Wait()
+		close(nsErrChan)
+		for e := range nsErrChan {
+			if e != nil { // Check that default value wasn't returned
+				return nil, e
+			}
+		}
+
+		objectCheckers := in.getAllObjectCheckers(namespace.Name, istioDetails, services, workloadsPerNamespace, workloadsPerNamespace[namespace.Name], gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus, proxyStatus, serviceAccounts, allVirtualServices, allWorkloadEntries)
+		validations := runObjectCheckers(objectCheckers)
+		suppressValidations(validations, istioObjectsPerType(istioDetails, mtlsDetails, rbacDetails, gatewaysPerNamespace))
+		for key, validation := range validations {
+			// Objects of other namespaces are taken from the validations of their own namespace
+			if key.Namespace == namespace.Name {
+				meshValidations[key] = validation
+			}
+		}
+	}
+	return meshValidations, nil
+}
+
 func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods},
 	}
 }
 
-func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, registryStatus []*kubernetes.RegistryStatus) []ObjectChecker {
+func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, registryStatus []*kubernetes.RegistryStatus, proxyStatus []*kubernetes.ProxyStatus, serviceAccounts []string, allVirtualServices []kubernetes.IstioObject, allWorkloadEntries []kubernetes.IstioObject) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails, RegistryStatus: registryStatus},
-		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices},
-		checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries},
+		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices, GatewaysPerNamespace: gatewaysPerNamespace, WorkloadsPerNamespace: workloadsPerNamespace},
+		checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries, VirtualServices: allVirtualServices, GatewaysPerNamespace: gatewaysPerNamespace, WorkloadsPerNamespace: workloadsPerNamespace},
 		checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace},
 		checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads},
 		checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries},
 		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices, RegistryStatus: registryStatus},
 		checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces, WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries},
 		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads},
+		checkers.EnvoyFilterChecker{EnvoyFilters: istioDetails.EnvoyFilters, ProxyStatus: proxyStatus, WorkloadList: workloads},
+		checkers.WorkloadEntryChecker{WorkloadEntries: istioDetails.WorkloadEntries, AllWorkloadEntries: allWorkloadEntries, Services: services, ServiceEntries: istioDetails.ServiceEntries},
+		checkers.WorkloadGroupChecker{WorkloadGroups: istioDetails.WorkloadGroups, ServiceAccounts: serviceAccounts},
 	}
 }
 
@@ -132,6 +216,10 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 	var mtlsDetails kubernetes.MTLSDetails
 	var rbacDetails kubernetes.RBACDetails
 	var registryStatus []*kubernetes.RegistryStatus
+	var proxyStatus []*kubernetes.ProxyStatus
+	var serviceAccounts []string
+	var allVirtualServices []kubernetes.IstioObject
+	var allWorkloadEntries []kubernetes.IstioObject
 	var err error
 	var objectCheckers []ObjectChecker
 
@@ -145,7 +233,7 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 	errChan := make(chan error, 1)
 
 	// Get all the Istio objects from a Namespace and all gateways from every namespace
-	wg.Add(9)
+	wg.Add(11)
 	go in.fetchNamespaces(&namespaces, errChan, &wg)
 	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
 	go in.fetchServices(&services, namespace, errChan, &wg)
@@ -155,6 +243,18 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
 	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
 	go in.fetchRegistryStatus(&registryStatus, errChan, &wg)
+	go in.fetchProxyStatus(&proxyStatus, &wg)
+	go in.fetchServiceAccounts(&serviceAccounts, namespace, errChan, &wg)
+	if objectType == kubernetes.DestinationRules {
+		// The clients routing to the host of a DestinationRule are found in the VirtualServices of every namespace
+		wg.Add(1)
+		go in.fetchAllIstioObjects(&allVirtualServices, kubernetes.VirtualServices, errChan, &wg)
+	}
+	if objectType == kubernetes.WorkloadEntries {
+		// The WorkloadEntries of every namespace are only needed to validate a WorkloadEntry
+		wg.Add(1)
+		go in.fetchAllIstioObjects(&allWorkloadEntries, kubernetes.WorkloadEntries, errChan, &wg)
+	}
 	wg.Wait()
 
 	noServiceChecker := checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails, RegistryStatus: registryStatus}
@@ -165,10 +265,10 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 			checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace},
 		}
 	case kubernetes.VirtualServices:
-		virtualServiceChecker := checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, VirtualServices: istioDetails.VirtualServices, DestinationRules: istioDetails.DestinationRules}
+		virtualServiceChecker := checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, VirtualServices: istioDetails.VirtualServices, DestinationRules: istioDetails.DestinationRules, GatewaysPerNamespace: gatewaysPerNamespace, WorkloadsPerNamespace: workloadsPerNamespace}
 		objectCheckers = []ObjectChecker{noServiceChecker, virtualServiceChecker}
 	case kubernetes.DestinationRules:
-		destinationRulesChecker := checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries}
+		destinationRulesChecker := checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries, VirtualServices: allVirtualServices, GatewaysPerNamespace: gatewaysPerNamespace, WorkloadsPerNamespace: workloadsPerNamespace}
 		objectCheckers = []ObjectChecker{noServiceChecker, destinationRulesChecker}
 	case kubernetes.ServiceEntries:
 		serviceEntryChecker := checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries}
@@ -187,15 +287,18 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 		peerAuthnChecker := checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads}
 		objectCheckers = []ObjectChecker{peerAuthnChecker}
 	case kubernetes.WorkloadEntries:
-		// Validation on WorkloadEntries are not yet in place
+		workloadEntryChecker := checkers.WorkloadEntryChecker{WorkloadEntries: istioDetails.WorkloadEntries, AllWorkloadEntries: allWorkloadEntries, Services: services, ServiceEntries: istioDetails.ServiceEntries}
+		objectCheckers = []ObjectChecker{workloadEntryChecker}
 	case kubernetes.WorkloadGroups:
-		// Validation on WorkloadGroups are not yet in place
+		workloadGroupChecker := checkers.WorkloadGroupChecker{WorkloadGroups: istioDetails.WorkloadGroups, ServiceAccounts: serviceAccounts}
+		objectCheckers = []ObjectChecker{workloadGroupChecker}
 	case kubernetes.RequestAuthentications:
 		// Validation on RequestAuthentications are not yet in place
 		requestAuthnChecker := checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads}
 		objectCheckers = []ObjectChecker{requestAuthnChecker}
 	case kubernetes.EnvoyFilters:
-		// Validation on EnvoyFilters are not yet in place
+		envoyFilterChecker := checkers.EnvoyFilterChecker{EnvoyFilters: istioDetails.EnvoyFilters, ProxyStatus: proxyStatus, WorkloadList: workloads}
+		objectCheckers = []ObjectChecker{envoyFilterChecker}
 	default:
 		err = fmt.Errorf("object type not found: %v", objectType)
 	}
@@ -211,7 +314,10 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 		return models.IstioValidations{}, err
 	}
 
-	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+	validations := runObjectCheckers(objectCheckers)
+	suppressValidations(validations, istioObjectsPerType(istioDetails, mtlsDetails, rbacDetails, gatewaysPerNamespace))
+
+	return validations.FilterByKey(models.ObjectTypeSingular[objectType], object), nil
 }
 
 func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
@@ -225,6 +331,47 @@ func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
 	return objectTypeValidations
 }
 
+// suppressValidations suppresses the checks whose validation code is listed by the annotation of the validated object
+func suppressValidations(validations models.IstioValidations, objectsPerType map[string][]kubernetes.IstioObject) {
+	for objectType, objects := range objectsPerType {
+		for _, object := range objects {
+			codes := models.GetSuppressedValidationCodes(object.GetObjectMeta().Annotations)
+			if len(codes) == 0 {
+				continue
+			}
+			key := models.IstioValidationKey{ObjectType: objectType, Name: object.GetObjectMeta().Name, Namespace: object.GetObjectMeta().Namespace}
+			if validation, ok := validations[key]; ok {
+				validation.Suppress(codes)
+			}
+		}
+	}
+}
+
+// istioObjectsPerType returns the validated Istio objects by validation object type
+func istioObjectsPerType(istioDetails kubernetes.IstioDetails, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, gatewaysPerNamespace [][]kubernetes.IstioObject) map[string][]kubernetes.IstioObject {
+	gateways := make([]kubernetes.IstioObject, 0)
+	for _, gws := range gatewaysPerNamespace {
+		gateways = append(gateways, gws...)
+	}
+	peerAuthentications := make([]kubernetes.IstioObject, 0, len(mtlsDetails.PeerAuthentications)+len(mtlsDetails.MeshPeerAuthentications))
+	peerAuthentications = append(peerAuthentications, mtlsDetails.PeerAuthentications...)
+	peerAuthentications = append(peerAuthentications, mtlsDetails.MeshPeerAuthentications...)
+
+	return map[string][]kubernetes.IstioObject{
+		checkers.AuthorizationPolicyCheckerType:   rbacDetails.AuthorizationPolicies,
+		checkers.DestinationRuleCheckerType:       istioDetails.DestinationRules,
+		checkers.EnvoyFilterCheckerType:           istioDetails.EnvoyFilters,
+		checkers.GatewayCheckerType:               gateways,
+		checkers.PeerAuthenticationCheckerType:    peerAuthentications,
+		checkers.RequestAuthenticationCheckerType: istioDetails.RequestAuthentications,
+		checkers.ServiceEntryCheckerType:          istioDetails.ServiceEntries,
+		checkers.SidecarCheckerType:               istioDetails.Sidecars,
+		checkers.VirtualCheckerType:               istioDetails.VirtualServices,
+		checkers.WorkloadEntryCheckerType:         istioDetails.WorkloadEntries,
+		checkers.WorkloadGroupCheckerType:         istioDetails.WorkloadGroups,
+	}
+}
+
 // The following idea is used underneath: if errChan has at least one record, we'll effectively cancel the request (if scheduled in such order). On the other hand, if we can't
 // write to the buffered errChan, we just ignore the error as select does not block even if channel is full. This is because a single error is enough to cancel the whole request.
 
@@ -405,7 +552,7 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 	if len(errChan) == 0 {
 		var err error
 		wg2 := sync.WaitGroup{}
-		errChan2 := make(chan error, 5)
+		errChan2 := make(chan error, 9)
 		istioDetails := kubernetes.IstioDetails{}
 
 		if IsResourceCached(namespace, kubernetes.VirtualServices) {
@@ -453,6 +600,33 @@ func (in *IstioValidationsService) fetchDetails(rValue *kubernetes.IstioDetails,
 			}
 			go fetchIstioObjects(&istioDetails.Sidecars, namespace, getSidecars, &wg2, errChan2)
 		}
+		if IsResourceCached(namespace, kubernetes.EnvoyFilters) {
+			istioDetails.EnvoyFilters, err = kialiCache.GetIstioObjects(namespace, kubernetes.EnvoyFilters, "")
+		} else {
+			wg2.Add(1)
+			getEnvoyFilters := func(namespace string) ([]kubernetes.IstioObject, error) {
+				return in.k8s.GetIstioObjects(namespace, kubernetes.EnvoyFilters, "")
+			}
+			go fetchIstioObjects(&istioDetails.EnvoyFilters, namespace, getEnvoyFilters, &wg2, errChan2)
+		}
+		if IsResourceCached(namespace, kubernetes.WorkloadEntries) {
+			istioDetails.WorkloadEntries, err = kialiCache.GetIstioObjects(namespace, kubernetes.WorkloadEntries, "")
+		} else {
+			wg2.Add(1)
+			getWorkloadEntries := func(namespace string) ([]kubernetes.IstioObject, error) {
+				return in.k8s.GetIstioObjects(namespace, kubernetes.WorkloadEntries, "")
+			}
+			go fetchIstioObjects(&istioDetails.WorkloadEntries, namespace, getWorkloadEntries, &wg2, errChan2)
+		}
+		if IsResourceCached(namespace, kubernetes.WorkloadGroups) {
+			istioDetails.WorkloadGroups, err = kialiCache.GetIstioObjects(namespace, kubernetes.WorkloadGroups, "")
+		} else {
+			wg2.Add(1)
+			getWorkloadGroups := func(namespace string) ([]kubernetes.IstioObject, error) {
+				return in.k8s.GetIstioObjects(namespace, kubernetes.WorkloadGroups, "")
+			}
+			go fetchIstioObjects(&istioDetails.WorkloadGroups, namespace, getWorkloadGroups, &wg2, errChan2)
+		}
 		if IsResourceCached(namespace, kubernetes.RequestAuthentications) {
 			istioDetails.RequestAuthentications, err = kialiCache.GetIstioObjects(namespace, kubernetes.RequestAuthentications, "")
 		} else {
@@ -486,7 +660,40 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 		return
 	}
 
-	wg.Add(3)
+	wg.Add(2)
+	go in.fetchPeerAuthentications(&mtlsDetails.PeerAuthentications, namespace, errChan, wg)
+	go in.fetchMeshmTLSConfigs(mtlsDetails, errChan, wg)
+}
+
+func (in *IstioValidationsService) fetchPeerAuthentications(rValue *[]kubernetes.IstioObject, namespace string, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	var peerAuthns []kubernetes.IstioObject
+	var err error
+	if IsResourceCached(namespace, kubernetes.PeerAuthentications) {
+		peerAuthns, err = kialiCache.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
+	} else {
+		peerAuthns, err = in.k8s.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
+	}
+	if err != nil {
+		select {
+		case errChan <- err:
+		default:
+		}
+	} else {
+		*rValue = peerAuthns
+	}
+}
+
+// fetchMeshmTLSConfigs fetches the mTLS details shared by all the namespaces: the mesh PeerAuthentications, the
+// auto mTLS setting and the DestinationRules of all the namespaces
+func (in *IstioValidationsService) fetchMeshmTLSConfigs(mtlsDetails *kubernetes.MTLSDetails, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if len(errChan) > 0 {
+		return
+	}
+
+	wg.Add(2)
 
 	go func(details *kubernetes.MTLSDetails) {
 		defer wg.Done()
@@ -506,23 +713,6 @@ func (in *IstioValidationsService) fetchNonLocalmTLSConfigs(mtlsDetails *kuberne
 		}
 	}(mtlsDetails)
 
-	go func(details *kubernetes.MTLSDetails) {
-		defer wg.Done()
-
-		var peerAuthns []kubernetes.IstioObject
-		var err error
-		if IsResourceCached(namespace, kubernetes.PeerAuthentications) {
-			peerAuthns, err = kialiCache.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
-		} else {
-			peerAuthns, err = in.k8s.GetIstioObjects(namespace, kubernetes.PeerAuthentications, "")
-		}
-		if err != nil {
-			errChan <- err
-		} else {
-			details.PeerAuthentications = peerAuthns
-		}
-	}(mtlsDetails)
-
 	go func(details *kubernetes.MTLSDetails) {
 		defer wg.Done()
 		cfg := config.Get()
@@ -625,6 +815,75 @@ func (in *IstioValidationsService) fetchRegistryStatus(rValue *[]*kubernetes.Reg
 	}
 }
 
+// fetchProxyStatus fetches the proxy status. It is optional, on error the proxy version check is skipped.
+func (in *IstioValidationsService) fetchProxyStatus(rValue *[]*kubernetes.ProxyStatus, wg *sync.WaitGroup) {
+	defer wg.Done()
+	proxyStatus, err := in.businessLayer.ProxyStatus.GetProxyStatus()
+	if err != nil {
+		log.Warningf("Proxy status is not available, the proxy version validation is skipped. Error: %s", err)
+		return
+	}
+	*rValue = proxyStatus
+}
+
+// fetchAllIstioObjects fetches the Istio objects of a type in every namespace, e.g. the WorkloadEntries, their
+// addresses must be unique in the mesh
+func (in *IstioValidationsService) fetchAllIstioObjects(rValue *[]kubernetes.IstioObject, resourceType string, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if len(errChan) == 0 {
+		nss, err := in.businessLayer.Namespace.GetNamespaces()
+		if err != nil {
+			select {
+			case errChan <- err:
+			default:
+			}
+			return
+		}
+		allObjects := []kubernetes.IstioObject{}
+		for _, ns := range nss {
+			var objects []kubernetes.IstioObject
+			if IsResourceCached(ns.Name, resourceType) {
+				objects, err = kialiCache.GetIstioObjects(ns.Name, resourceType, "")
+			} else {
+				objects, err = in.k8s.GetIstioObjects(ns.Name, resourceType, "")
+			}
+			if err != nil {
+				select {
+				case errChan <- err:
+				default:
+				}
+				return
+			}
+			allObjects = append(allObjects, objects...)
+		}
+		*rValue = allObjects
+	}
+}
+
+// fetchServiceAccounts fetches the names of the namespace service accounts. They are left nil, and the checks using
+// them skipped, when the user can't list them. They are not cached, Kiali may not be allowed to watch them.
+func (in *IstioValidationsService) fetchServiceAccounts(rValue *[]string, namespace string, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if len(errChan) == 0 {
+		serviceAccounts, err := in.k8s.GetServiceAccounts(namespace)
+		if err != nil {
+			if checkForbidden("fetchServiceAccounts", err, "") {
+				return
+			}
+			select {
+			case errChan <- err:
+			default:
+			}
+		} else {
+			names := make([]string, 0, len(serviceAccounts))
+			for _, sa := range serviceAccounts {
+				names = append(names, sa.Name)
+			}
+			*rValue = names
+		}
+	}
+}
+
 var (
 	// used with checkForbidden - if a caller is in the map, its forbidden warning message was already logged
 	forbiddenCaller map[string]bool = map[string]bool{}
@@ -32,6 +32,20 @@ func TestGetNamespaceValidations(t *testing.T) {
 	assert.True(validations[models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}].Valid)
 }
 
+func TestGetMeshValidations(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(),
+		[]string{"details", "product", "customer"}, fakePods())
+
+	validations, err := vs.GetMeshValidations()
+	assert.NoError(err)
+	assert.NotEmpty(validations)
+	assert.True(validations[models.IstioValidationKey{ObjectType: "virtualservice", Namespace: "test", Name: "product-vs"}].Valid)
+}
+
 func TestGetIstioObjectValidations(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
@@ -54,6 +68,26 @@ func TestGatewayValidation(t *testing.T) {
 	assert.NotEmpty(validations)
 }
 
+func TestSuppressValidations(t *testing.T) {
+	assert := assert.New(t)
+
+	dr := data.CreateEmptyDestinationRule("test", "product-dr", "product")
+	meta := dr.GetObjectMeta()
+	meta.Annotations = map[string]string{models.SuppressValidationsAnnotation: "KIA0201"}
+	dr.SetObjectMeta(meta)
+
+	multiMatch := models.Build("destinationrules.multimatch", "spec/host")
+	key := models.IstioValidationKey{ObjectType: "destinationrule", Namespace: "test", Name: "product-dr"}
+	validations := models.IstioValidations{
+		key: &models.IstioValidation{Name: "product-dr", ObjectType: "destinationrule", Valid: true, Checks: []*models.IstioCheck{&multiMatch}},
+	}
+
+	suppressValidations(validations, map[string][]kubernetes.IstioObject{"destinationrule": {dr}})
+	assert.True(validations[key].Valid)
+	assert.Empty(validations[key].Checks)
+	assert.Len(validations[key].SuppressedChecks, 1)
+}
+
 func mockWorkLoadService(k8s *kubetest.K8SClientMock) WorkloadService {
 	// Setup mocks
 	k8s.On("IsOpenShift").Return(true)
@@ -88,6 +122,10 @@ func mockMultiNamespaceGatewaysValidationService() IstioValidationsService {
 	k8s.On("GetMeshPolicies", mock.AnythingOfType("string")).Return(fakeMeshPolicies(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "peerauthentications", "").Return(fakePolicies(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "requestauthentications", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "envoyfilters", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "workloadentries", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "workloadgroups", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetServiceAccounts", mock.AnythingOfType("string")).Return([]core_v1.ServiceAccount{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "clusterrbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "authorizationpolicies", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "servicerolebindings", "").Return([]kubernetes.IstioObject{}, nil)
@@ -102,6 +140,10 @@ func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, servic
 	k8s := new(kubetest.K8SClientMock)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "sidecars", "").Return(istioObjects.Sidecars, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "requestauthentications", "").Return(istioObjects.RequestAuthentications, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "envoyfilters", "").Return(istioObjects.EnvoyFilters, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "workloadentries", "").Return(istioObjects.WorkloadEntries, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "workloadgroups", "").Return(istioObjects.WorkloadGroups, nil)
+	k8s.On("GetServiceAccounts", mock.AnythingOfType("string")).Return([]core_v1.ServiceAccount{}, nil)
 	k8s.On("GetServices", mock.AnythingOfType("string"), mock.AnythingOfType("map[string]string")).Return(fakeCombinedServices(services), nil)
 	k8s.On("GetDeployments", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(FakeDepSyncedWithRS(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "virtualservices", "").Return(fakeCombinedIstioDetails().VirtualServices, nil)
@@ -17,8 +17,28 @@ func (in *ProxyStatusService) GetPodProxyStatus(ns, pod string) (*kubernetes.Pro
 		return nil, nil
 	}
 
+	if err := in.refreshProxyStatus(); err != nil {
+		return nil, err
+	}
+	return kialiCache.GetPodProxyStatus(ns, pod), nil
+}
+
+// GetProxyStatus returns the proxy status of every proxy in the mesh
+func (in *ProxyStatusService) GetProxyStatus() ([]*kubernetes.ProxyStatus, error) {
+	if kialiCache == nil {
+		return nil, nil
+	}
+
+	if err := in.refreshProxyStatus(); err != nil {
+		return nil, err
+	}
+	return kialiCache.GetProxyStatus(), nil
+}
+
+// refreshProxyStatus fetches the proxy status into the cache, unless it is already cached
+func (in *ProxyStatusService) refreshProxyStatus() error {
 	if kialiCache.CheckProxyStatus() {
-		return kialiCache.GetPodProxyStatus(ns, pod), nil
+		return nil
 	}
 
 	var proxyStatus []*kubernetes.ProxyStatus
@@ -26,12 +46,12 @@ func (in *ProxyStatusService) GetPodProxyStatus(ns, pod string) (*kubernetes.Pro
 
 	if proxyStatus, err = in.k8s.GetProxyStatus(); err != nil {
 		if proxyStatus, err = in.getProxyStatusUsingKialiSA(); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	kialiCache.SetProxyStatus(proxyStatus)
-	return kialiCache.GetPodProxyStatus(ns, pod), nil
+	return nil
 }
 
 func (in *ProxyStatusService) getProxyStatusUsingKialiSA() ([]*kubernetes.ProxyStatus, error) {
@@ -114,10 +114,14 @@ type PrometheusConfig struct {
 	// Enable cache for Prometheus queries
 	CacheEnabled bool `yaml:"cache_enabled,omitempty"`
 	// Global cache expiration expressed in seconds
-	CacheExpiration int    `yaml:"cache_expiration,omitempty"`
-	HealthCheckUrl  string `yaml:"health_check_url,omitempty"`
-	IsCore          bool   `yaml:"is_core,omitempty"`
-	URL             string `yaml:"url,omitempty"`
+	CacheExpiration int `yaml:"cache_expiration,omitempty"`
+	// Prometheus instances holding the telemetry of each cluster, keyed by cluster name. When set, the graph
+	// traffic and the appender metrics are queried from every cluster Prometheus instead of this one. Supported only
+	// for external_services.prometheus.
+	Clusters       map[string]PrometheusConfig `yaml:"clusters,omitempty"`
+	HealthCheckUrl string                      `yaml:"health_check_url,omitempty"`
+	IsCore         bool                        `yaml:"is_core,omitempty"`
+	URL            string                      `yaml:"url,omitempty"`
 }
 
 // CustomDashboardsConfig describes configuration specific to Custom Dashboards
@@ -178,9 +182,19 @@ type IstioConfig struct {
 	IstioSidecarInjectorConfigMapName string              `yaml:"istio_sidecar_injector_config_map_name,omitempty"`
 	IstioSidecarAnnotation            string              `yaml:"istio_sidecar_annotation,omitempty"`
 	IstiodDeploymentName              string              `yaml:"istiod_deployment_name,omitempty"`
+	TelemetryMapping                  TelemetryMapping    `yaml:"telemetry_mapping,omitempty"`
 	UrlServiceVersion                 string              `yaml:"url_service_version"`
 }
 
+// TelemetryMapping maps the Istio standard metric and label names, used for graph generation, to the names
+// actually stored in Prometheus (e.g. when mesh metrics are exported through an OpenTelemetry Collector
+// pipeline). Names that are not mapped are used as-is. A mapped histogram metric also maps its _bucket,
+// _count and _sum series.
+type TelemetryMapping struct {
+	Labels  map[string]string `yaml:"labels,omitempty"`
+	Metrics map[string]string `yaml:"metrics,omitempty"`
+}
+
 type IstioCanaryRevision struct {
 	Current string `yaml:"current,omitempty"`
 	Upgrade string `yaml:"upgrade,omitempty"`
@@ -317,14 +331,33 @@ type DeploymentConfig struct {
 	Namespace            string   `yaml:"namespace,omitempty"` // Kiali deployment namespace
 }
 
+// GraphCustomMetric defines an extra metric reported on the graph nodes or edges by the customMetrics appender.
+// The query is a PromQL template returning an instant vector, its values are summed. Node queries support the
+// ${namespace}, ${workload}, ${app}, ${version} and ${service} placeholders, edge queries the same placeholders
+// prefixed with source_ or dest_ (e.g. ${dest_service}). Both support ${duration}, the graph duration (e.g. 600s).
+// A node or edge without a value for a query placeholder is skipped.
+type GraphCustomMetric struct {
+	Key    string `yaml:"key"`    // the key of the value in the node or edge customMetrics
+	Query  string `yaml:"query"`  // the PromQL template
+	Target string `yaml:"target"` // node | edge
+}
+
+// GraphSnapshotsConfig defines the store for saved graph snapshots, snapshots are disabled when no store is set
+type GraphSnapshotsConfig struct {
+	Directory string `yaml:"directory,omitempty"` // the directory of the file store, typically on a persistent volume
+	Store     string `yaml:"store,omitempty"`     // the store type. Supported: file
+}
+
 // GraphFindOption defines a single Graph Find/Hide Option
 type GraphFindOption struct {
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 	Expression  string `yaml:"expression,omitempty" json:"expression,omitempty"`
 }
 
-// GraphUIDefaults defines UI Defaults specific to the UI Graph
+// GraphUIDefaults defines UI Defaults specific to the UI Graph. BoxByLabels lists the workload labels
+// accepted as graph boxBy values, in addition to the built-in app, cluster and namespace boxing.
 type GraphUIDefaults struct {
+	BoxByLabels []string          `yaml:"box_by_labels,omitempty" json:"boxByLabels,omitempty"`
 	FindOptions []GraphFindOption `yaml:"find_options,omitempty" json:"findOptions,omitempty"`
 	HideOptions []GraphFindOption `yaml:"hide_options,omitempty" json:"hideOptions,omitempty"`
 }
@@ -375,6 +408,8 @@ type Config struct {
 	Deployment               DeploymentConfig                    `yaml:"deployment,omitempty"`
 	Extensions               Extensions                          `yaml:"extensions,omitempty"`
 	ExternalServices         ExternalServices                    `yaml:"external_services,omitempty"`
+	GraphCustomMetrics       []GraphCustomMetric                 `yaml:"graph_custom_metrics,omitempty"`
+	GraphSnapshots           GraphSnapshotsConfig                `yaml:"graph_snapshots,omitempty"`
 	HealthConfig             HealthConfig                        `yaml:"health_config,omitempty" json:"healthConfig,omitempty"`
 	Identity                 security.Identity                   `yaml:",omitempty"`
 	InCluster                bool                                `yaml:"in_cluster,omitempty"`
@@ -639,6 +674,14 @@ func (conf Config) String() (str string) {
 	obf := conf
 	obf.ExternalServices.Grafana.Auth.Obfuscate()
 	obf.ExternalServices.Prometheus.Auth.Obfuscate()
+	if clusters := conf.ExternalServices.Prometheus.Clusters; len(clusters) > 0 {
+		// copy the map, to not obfuscate the given config
+		obf.ExternalServices.Prometheus.Clusters = make(map[string]PrometheusConfig, len(clusters))
+		for cluster, prometheusConfig := range clusters {
+			prometheusConfig.Auth.Obfuscate()
+			obf.ExternalServices.Prometheus.Clusters[cluster] = prometheusConfig
+		}
+	}
 	obf.ExternalServices.Tracing.Auth.Obfuscate()
 	obf.Identity.Obfuscate()
 	obf.LoginToken.Obfuscate()
@@ -67,6 +67,9 @@ func TestSensitiveDataObfuscation(t *testing.T) {
 	conf.ExternalServices.Prometheus.Auth.Username = "my-username"
 	conf.ExternalServices.Prometheus.Auth.Password = "my-password"
 	conf.ExternalServices.Prometheus.Auth.Token = "my-token"
+	conf.ExternalServices.Prometheus.Clusters = map[string]PrometheusConfig{
+		"east": {Auth: Auth{Username: "my-username", Password: "my-password"}, URL: "http://prometheus.east:9090"},
+	}
 	conf.ExternalServices.Tracing.Auth.Username = "my-username"
 	conf.ExternalServices.Tracing.Auth.Password = "my-password"
 	conf.ExternalServices.Tracing.Auth.Token = "my-token"
@@ -84,6 +87,7 @@ func TestSensitiveDataObfuscation(t *testing.T) {
 	// Test that the original values are unchanged
 	assert.Equal(t, "my-username", conf.ExternalServices.Grafana.Auth.Username)
 	assert.Equal(t, "my-password", conf.ExternalServices.Prometheus.Auth.Password)
+	assert.Equal(t, "my-password", conf.ExternalServices.Prometheus.Clusters["east"].Auth.Password)
 	assert.Equal(t, "my-token", conf.ExternalServices.Tracing.Auth.Token)
 	assert.Equal(t, "my-signkey", conf.LoginToken.SigningKey)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/snapshot"
 	"github.com/kiali/kiali/handlers"
 	"github.com/kiali/kiali/jaeger"
 	"github.com/kiali/kiali/models"
@@ -134,6 +135,15 @@ type ServiceParam struct {
 	Name string `json:"service"`
 }
 
+// swagger:parameters graphSnapshot graphSnapshotDelete
+type SnapshotParam struct {
+	// The graph snapshot ID.
+	//
+	// in: path
+	// required: true
+	Name string `json:"snapshot"`
+}
+
 // swagger:parameters podLogs
 type SinceTimeParam struct {
 	// The start time for fetching logs. UNIX time in seconds. Default is all logs.
@@ -153,7 +163,25 @@ type DurationLogParam struct {
 	Name string `json:"duration"`
 }
 
-// swagger:parameters traceDetails
+// swagger:parameters namespaceValidations meshValidations
+type ValidationsFormatParam struct {
+	// Report format of the validations, "sarif" or "junit". Default is the validation summary.
+	//
+	// in: query
+	// required: false
+	Name string `json:"format"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesSnapshot graphNamespacesStream graphService graphTrace graphWorkload
+type FindParam struct {
+	// Find expression, using the UI Graph Find syntax (e.g. "rt > 1000"). Matching nodes or edges are marked with isFound.
+	//
+	// in: query
+	// required: false
+	Name string `json:"find"`
+}
+
+// swagger:parameters graphTrace traceDetails
 type TraceIDParam struct {
 	// The trace ID.
 	//
@@ -185,9 +213,9 @@ type WorkloadParam struct {
 // - keep this alphabetized
 /////////////////////
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, deadNode, healthConfig, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck, throughput].
+	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, anomaly, authorizationPolicy, circuitBreakerState, customMetrics, deadNode, healthConfig, idleNode, istio, responseFlags, responseTime, securityPolicy, serviceEntry, sidecarsCheck, sparklines, tcpConnections, throughput].
 	//
 	// in: query
 	// required: false
@@ -195,9 +223,39 @@ type AppendersParam struct {
 	Name string `json:"appenders"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
+type AnomalyBaselineDaysParam struct {
+	// Used only with anomaly appender. The number of previous days, at the same time of day, providing the baseline.
+	//
+	// in: query
+	// required: false
+	// default: 7
+	Name string `json:"anomalyBaselineDays"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
+type AnomalyThresholdParam struct {
+	// Used only with anomaly appender. The deviation from the baseline, in standard deviations, that flags an anomaly.
+	//
+	// in: query
+	// required: false
+	// default: 3
+	Name string `json:"anomalyThreshold"`
+}
+
+// swagger:parameters graphNamespacesDiff
+type BaselineOffsetParam struct {
+	// Offset (Golang string duration) subtracted from queryTime to get the baseline queryTime.
+	//
+	// in: query
+	// required: false
+	// default: 1h
+	Name string `json:"baselineOffset"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphTrace graphWorkload
 type BoxByParam struct {
-	// Comma-separated list of desired node boxing. Available boxings: [app, cluster, namespace, none].
+	// Comma-separated list of desired node boxing. Available boxings: [app, cluster, namespace, none], plus the workload labels configured in kiali_feature_flags.ui_defaults.graph.box_by_labels.
 	//
 	// in: query
 	// required: false
@@ -205,7 +263,26 @@ type BoxByParam struct {
 	Name string `json:"boxBy"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesSnapshot graphNamespacesStream graphService graphTrace graphWorkload
+type ConfigVendorParam struct {
+	// The config vendor used to format the graph. Available vendors: [cytoscape, dot, graphml].
+	//
+	// in: query
+	// required: false
+	// default: cytoscape
+	Name string `json:"configVendor"`
+}
+
+// swagger:parameters graphNamespacesPaths
+type DestNodeParam struct {
+	// The ID of the path destination node, as provided in the namespaces graph.
+	//
+	// in: query
+	// required: true
+	Name string `json:"dest"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
 type DurationGraphParam struct {
 	// Query time-range duration (Golang string duration).
 	//
@@ -215,7 +292,7 @@ type DurationGraphParam struct {
 	Name string `json:"duration"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphTrace graphWorkload
 type GraphTypeParam struct {
 	// Graph type. Available graph types: [app, service, versionedApp, workload].
 	//
@@ -225,7 +302,16 @@ type GraphTypeParam struct {
 	Name string `json:"graphType"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesSnapshot graphNamespacesStream graphService graphTrace graphWorkload
+type HideParam struct {
+	// Hide expression, using the UI Graph Hide syntax (e.g. "name = unknown"). Matching nodes or edges are removed from the graph.
+	//
+	// in: query
+	// required: false
+	Name string `json:"hide"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphWorkload
 type IncludeIdleEdges struct {
 	// Flag for including edges that have no request traffic for the time period.
 	//
@@ -235,7 +321,7 @@ type IncludeIdleEdges struct {
 	Name string `json:"includeIdleEdges"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphTrace graphWorkload
 type InjectServiceNodes struct {
 	// Flag for injecting the requested service node between source and destination nodes.
 	//
@@ -245,7 +331,7 @@ type InjectServiceNodes struct {
 	Name string `json:"injectServiceNodes"`
 }
 
-// swagger:parameters graphNamespaces
+// swagger:parameters graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream
 type NamespacesParam struct {
 	// Comma-separated list of namespaces to include in the graph. The namespaces must be accessible to the client.
 	//
@@ -254,7 +340,7 @@ type NamespacesParam struct {
 	Name string `json:"namespaces"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
 type QueryTimeParam struct {
 	// Unix time (seconds) for query such that time range is [queryTime-duration..queryTime]. Default is now.
 	//
@@ -264,7 +350,17 @@ type QueryTimeParam struct {
 	Name string `json:"queryTime"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphNamespacesStream
+type RefreshIntervalParam struct {
+	// Time between graph updates (Golang string duration), at least 5s.
+	//
+	// in: query
+	// required: false
+	// default: the configured UI refresh interval
+	Name string `json:"refreshInterval"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
 type ResponseTimeParam struct {
 	// Used only with responseTime appender. One of: avg | 50 | 95 | 99.
 	//
@@ -274,7 +370,35 @@ type ResponseTimeParam struct {
 	Name string `json:"responseTime"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphNamespacesSnapshot
+type SnapshotNameParam struct {
+	// An optional description of the graph snapshot.
+	//
+	// in: query
+	// required: false
+	Name string `json:"name"`
+}
+
+// swagger:parameters graphNamespacesPaths
+type SourceNodeParam struct {
+	// The ID of the path source node, as provided in the namespaces graph.
+	//
+	// in: query
+	// required: true
+	Name string `json:"source"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
+type SparklineStepParam struct {
+	// Used only with sparklines appender. The time between edge time-series values (Golang string duration), a whole number of seconds.
+	//
+	// in: query
+	// required: false
+	// default: duration/30
+	Name string `json:"sparklineStep"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesDiff graphNamespacesPaths graphNamespacesSnapshot graphNamespacesStream graphService graphWorkload
 type ThroughputParam struct {
 	// Used only with throughput appender. One of: request | response.
 	//
@@ -535,6 +659,34 @@ type GraphResponse struct {
 	Body cytoscape.Config
 }
 
+// HTTP status code 200 and the request paths between two graph nodes
+// swagger:response graphPathsResponse
+type GraphPathsResponse struct {
+	// in:body
+	Body cytoscape.PathsConfig
+}
+
+// HTTP status code 201 and the saved graph snapshot info, without the graph
+// swagger:response graphSnapshotInfoResponse
+type GraphSnapshotInfoResponse struct {
+	// in:body
+	Body snapshot.Info
+}
+
+// HTTP status code 200 and the list of saved graph snapshot infos, most recent first
+// swagger:response graphSnapshotsResponse
+type GraphSnapshotsResponse struct {
+	// in:body
+	Body []snapshot.Info
+}
+
+// HTTP status code 200 and the saved graph snapshot, including the graph
+// swagger:response graphSnapshotResponse
+type GraphSnapshotResponse struct {
+	// in:body
+	Body snapshot.Snapshot
+}
+
 // HTTP status code 200 and IstioConfigList model in data
 // swagger:response istioConfigList
 type IstioConfigResponse struct {
@@ -710,6 +862,13 @@ type NamespaceValidationSummaryResponse struct {
 	Body models.IstioValidationSummary
 }
 
+// Return the validation status of every Namespace
+// swagger:response meshValidationSummaryResponse
+type MeshValidationSummaryResponse struct {
+	// in:body
+	Body map[string]models.IstioValidationSummary
+}
+
 // Return a dump of the configuration of a given envoy proxy
 // swagger:response configDump
 type ConfigDumpResponse struct {
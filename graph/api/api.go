@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/http"
 
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/config/dot"
+	"github.com/kiali/kiali/graph/config/graphml"
 	"github.com/kiali/kiali/graph/telemetry/istio"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus"
@@ -42,11 +46,87 @@ func graphNamespacesIstio(business *business.Layer, prom *prometheus.Client, o g
 	globalInfo.Business = business
 
 	trafficMap := istio.BuildNamespacesTrafficMap(o.TelemetryOptions, prom, globalInfo)
-	code, config = generateGraph(trafficMap, o)
+	code, config = generateGraph(trafficMap, globalInfo, o)
+
+	return code, config
+}
+
+// GraphNamespacesDiff generates a namespaces graph using the provided options, and then diffs it
+// against the same graph generated for the baseline queryTime.
+func GraphNamespacesDiff(business *business.Layer, o graph.DiffOptions) (code int, config interface{}) {
+	// time how long it takes to generate this graph
+	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
+	defer promtimer.ObserveDuration()
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, config = graphNamespacesDiffIstio(business, prom, o)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	// update metrics
+	internalmetrics.SetGraphNodes(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes, 0)
 
 	return code, config
 }
 
+// graphNamespacesDiffIstio provides a test hook that accepts mock clients
+func graphNamespacesDiffIstio(business *business.Layer, prom *prometheus.Client, o graph.DiffOptions) (code int, config interface{}) {
+
+	// Each graph gets its own 'global' object, the cached information is specific to the queryTime.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+	baselineGlobalInfo := graph.NewAppenderGlobalInfo()
+	baselineGlobalInfo.Business = business
+
+	trafficMap := istio.BuildNamespacesTrafficMap(o.TelemetryOptions, prom, globalInfo)
+	baselineTrafficMap := istio.BuildNamespacesTrafficMap(o.Baseline.TelemetryOptions, prom, baselineGlobalInfo)
+	trafficMap = graph.DiffTrafficMaps(trafficMap, baselineTrafficMap)
+	// a failed baseline namespace or appender also alters the diff, the dropped telemetry is only reported for the graph
+	globalInfo.Warnings = append(globalInfo.Warnings, baselineGlobalInfo.Warnings...)
+	code, config = generateGraph(trafficMap, globalInfo, o.Options)
+
+	return code, config
+}
+
+// GraphNamespacesPaths generates a namespaces graph using the provided options, and then returns every
+// request path between the source and dest nodes.
+func GraphNamespacesPaths(business *business.Layer, o graph.PathOptions) (code int, config interface{}) {
+	// time how long it takes to generate this graph
+	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
+	defer promtimer.ObserveDuration()
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		code, config = graphNamespacesPathsIstio(business, prom, o)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	return code, config
+}
+
+// graphNamespacesPathsIstio provides a test hook that accepts mock clients
+func graphNamespacesPathsIstio(business *business.Layer, prom *prometheus.Client, o graph.PathOptions) (code int, config interface{}) {
+
+	// Create a 'global' object to store the business. Global only to the request.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+
+	trafficMap := istio.BuildNamespacesTrafficMap(o.TelemetryOptions, prom, globalInfo)
+	pathsConfig := cytoscape.NewPathsConfig(trafficMap, o.Source, o.Dest, o.ConfigOptions)
+	pathsConfig.DroppedTelemetry = globalInfo.DroppedTelemetry.List()
+	pathsConfig.Warnings = append(globalInfo.Warnings, pathsConfig.Warnings...)
+	config = pathsConfig
+
+	return http.StatusOK, config
+}
+
 // GraphNode generates a node graph using the provided options
 func GraphNode(business *business.Layer, o graph.Options) (code int, config interface{}) {
 	if len(o.Namespaces) != 1 {
@@ -79,21 +159,69 @@ func graphNodeIstio(business *business.Layer, client *prometheus.Client, o graph
 	globalInfo.Business = business
 
 	trafficMap := istio.BuildNodeTrafficMap(o.TelemetryOptions, client, globalInfo)
-	code, config = generateGraph(trafficMap, o)
+	code, config = generateGraph(trafficMap, globalInfo, o)
+
+	return code, config
+}
+
+// GraphTrace generates a graph of the requests traversed by a single trace, using the provided options
+func GraphTrace(business *business.Layer, o graph.TraceOptions) (code int, config interface{}) {
+	trace, err := business.Jaeger.GetJaegerTraceDetail(o.TraceID)
+	graph.CheckError(err)
+	if trace == nil {
+		graph.Panic(fmt.Sprintf("Trace [%s] not found", o.TraceID), http.StatusNotFound)
+	}
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		code, config = graphTraceIstio(business, trace.Data, o.Options)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	return code, config
+}
+
+// graphTraceIstio provides a test hook that accepts a trace
+func graphTraceIstio(business *business.Layer, trace jaegerModels.Trace, o graph.Options) (code int, config interface{}) {
+
+	// Create a 'global' object to store the business. Global only to the request.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+
+	trafficMap := istio.BuildTraceTrafficMap(trace, o.TelemetryOptions, globalInfo)
+	code, config = generateGraph(trafficMap, globalInfo, o)
 
 	return code, config
 }
 
-func generateGraph(trafficMap graph.TrafficMap, o graph.Options) (int, interface{}) {
+// generateGraph returns the vendor config of the traffic map, reporting the warnings of a partial graph
+// and the dropped telemetry
+func generateGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, o graph.Options) (int, interface{}) {
 	log.Tracef("Generating config for [%s] graph...", o.ConfigVendor)
 
 	promtimer := internalmetrics.GetGraphMarshalTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
+	graph.FindAndHide(trafficMap, o.Find, o.Hide)
+
 	var vendorConfig interface{}
 	switch o.ConfigVendor {
 	case graph.VendorCytoscape:
-		vendorConfig = cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+		cytoscapeConfig := cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+		cytoscapeConfig.DroppedTelemetry = globalInfo.DroppedTelemetry.List()
+		cytoscapeConfig.Warnings = globalInfo.Warnings
+		vendorConfig = cytoscapeConfig
+	case graph.VendorDot:
+		dotConfig := dot.NewConfig(trafficMap, o.ConfigOptions)
+		dotConfig.DroppedTelemetry = globalInfo.DroppedTelemetry.List()
+		dotConfig.Warnings = globalInfo.Warnings
+		vendorConfig = dotConfig
+	case graph.VendorGraphML:
+		graphmlConfig := graphml.NewConfig(trafficMap, o.ConfigOptions)
+		graphmlConfig.AddDroppedTelemetry(globalInfo.DroppedTelemetry.List())
+		graphmlConfig.AddWarnings(globalInfo.Warnings)
+		vendorConfig = graphmlConfig
 	default:
 		graph.Error(fmt.Sprintf("ConfigVendor [%s] not supported", o.ConfigVendor))
 	}
@@ -0,0 +1,123 @@
+package api
+
+// Snapshot.go supports saved graph snapshots. A snapshot is available only to clients with access to
+// every namespace of the snapshot graph, and it can be deleted only by its owner, the authenticated user that
+// saved it.
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/snapshot"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// GraphNamespacesSnapshot generates a namespaces graph using the provided options and saves it as a snapshot
+// owned by the user, empty if not authenticated
+func GraphNamespacesSnapshot(business *business.Layer, o graph.SnapshotOptions, user string) (code int, info interface{}) {
+	store := newSnapshotStore()
+
+	var config cytoscape.Config
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, err := prometheus.NewClient()
+		graph.CheckError(err)
+		_, vendorConfig := graphNamespacesIstio(business, prom, o.Options)
+		config = vendorConfig.(cytoscape.Config)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	s, err := snapshot.NewSnapshot(o.Name, user, o.Options, config)
+	graph.CheckError(err)
+	graph.CheckError(store.Save(s))
+
+	return http.StatusCreated, s.Info
+}
+
+// GraphSnapshots returns the snapshots accessible to the client, most recent first
+func GraphSnapshots(business *business.Layer) (code int, infos interface{}) {
+	store := newSnapshotStore()
+
+	all, err := store.List()
+	graph.CheckError(err)
+
+	accessible := accessibleNamespaces(business)
+	result := []snapshot.Info{}
+	for _, info := range all {
+		if isSnapshotAccessible(info, accessible) {
+			result = append(result, info)
+		}
+	}
+
+	return http.StatusOK, result
+}
+
+// GraphSnapshot returns the requested snapshot
+func GraphSnapshot(business *business.Layer, id string) (code int, s interface{}) {
+	return http.StatusOK, getSnapshot(business, newSnapshotStore(), id)
+}
+
+// DeleteGraphSnapshot removes the requested snapshot, it must be owned by the user. A snapshot saved by a user
+// that was not authenticated has no owner and can't be deleted.
+func DeleteGraphSnapshot(business *business.Layer, id, user string) (code int) {
+	store := newSnapshotStore()
+
+	s := getSnapshot(business, store, id)
+	if s.Owner == "" || s.Owner != user {
+		graph.Forbidden(fmt.Sprintf("Graph snapshot [%s] is not owned by the user.", id))
+	}
+	graph.CheckError(store.Delete(id))
+
+	return http.StatusNoContent
+}
+
+func newSnapshotStore() snapshot.Store {
+	store, err := snapshot.NewStore()
+	if err != nil {
+		graph.Panic(err.Error(), http.StatusNotImplemented)
+	}
+	return store
+}
+
+// getSnapshot returns the snapshot, it panics if the snapshot does not exist or is not accessible
+func getSnapshot(business *business.Layer, store snapshot.Store, id string) *snapshot.Snapshot {
+	if !snapshot.IsValidID(id) {
+		graph.BadRequest(fmt.Sprintf("Invalid graph snapshot ID [%s]", id))
+	}
+
+	s, err := store.Get(id)
+	if err == snapshot.ErrNotFound {
+		graph.Panic(fmt.Sprintf("Graph snapshot [%s] not found", id), http.StatusNotFound)
+	}
+	graph.CheckError(err)
+
+	if !isSnapshotAccessible(s.Info, accessibleNamespaces(business)) {
+		graph.Forbidden(fmt.Sprintf("Graph snapshot [%s] is not accessible.", id))
+	}
+
+	return s
+}
+
+func accessibleNamespaces(business *business.Layer) map[string]bool {
+	namespaces, err := business.Namespace.GetNamespaces()
+	graph.CheckError(err)
+
+	accessible := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		accessible[namespace.Name] = true
+	}
+	return accessible
+}
+
+func isSnapshotAccessible(info snapshot.Info, accessible map[string]bool) bool {
+	for _, namespace := range info.Options.Namespaces {
+		if !accessible[namespace] {
+			return false
+		}
+	}
+	return true
+}
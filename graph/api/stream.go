@@ -0,0 +1,156 @@
+package api
+
+// Stream.go supports streaming namespaces graphs. The graph is regenerated every refresh interval and,
+// after the initial graph, only the incremental update is sent to the client.
+//
+// Every event is sent with an ID of the form <streamID>:<timestamp>.  The most recent config of each
+// stream is cached for a short time so that a client reconnecting with the ID of the last received
+// event (e.g. an EventSource sending Last-Event-ID) can resume with incremental updates. If the stream
+// is not found (e.g. expired or served by another Kiali instance) a full graph is sent.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/util"
+)
+
+// The stream event types
+const (
+	StreamEventError  string = "error"  // payload is the error message, the stream is closed
+	StreamEventGraph  string = "graph"  // payload is a full cytoscape.Config
+	StreamEventUpdate string = "update" // payload is a cytoscape.ConfigUpdate
+)
+
+// StreamEventWriter sends a single event to the client. It should return an error if the client is gone.
+type StreamEventWriter func(event, id string, payload interface{}) error
+
+type streamEntry struct {
+	config  cytoscape.Config
+	expires time.Time
+	request string // the request params, minus queryTime, used to ensure a resumed stream is for the same graph
+}
+
+var (
+	streams      = make(map[string]streamEntry)
+	streamsMutex sync.Mutex
+)
+
+// GraphNamespacesStream generates a namespaces graph every refresh interval and writes the events to the
+// client until the context is done, the client is gone or the graph generation fails.
+func GraphNamespacesStream(ctx context.Context, business *business.Layer, o graph.StreamOptions, lastEventID string, write StreamEventWriter) {
+	request := streamRequest(o.TelemetryOptions.Params)
+	streamID, previous := resumeStream(lastEventID, request)
+	if streamID == "" {
+		randomBytes, err := util.CryptoRandomBytes(16)
+		if err != nil {
+			_ = write(StreamEventError, "", err.Error())
+			return
+		}
+		streamID = fmt.Sprintf("%x", randomBytes)
+	}
+
+	ticker := time.NewTicker(o.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := generateStreamConfig(business, o.WithQueryTime(time.Now().Unix()))
+		if err != nil {
+			log.Errorf("Graph stream [%s] failed: %v", streamID, err)
+			_ = write(StreamEventError, "", err.Error())
+			return
+		}
+
+		id := fmt.Sprintf("%s:%d", streamID, current.Timestamp)
+		if previous == nil {
+			err = write(StreamEventGraph, id, current)
+		} else {
+			err = write(StreamEventUpdate, id, cytoscape.NewConfigUpdate(*previous, current))
+		}
+		if err != nil {
+			log.Debugf("Graph stream [%s] closed by client: %v", streamID, err)
+			return
+		}
+		storeStream(streamID, request, current, 2*o.RefreshInterval)
+		previous = &current
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// generateStreamConfig converts graph generation panics to errors, a panic would otherwise end the
+// response in the middle of the stream.
+func generateStreamConfig(business *business.Layer, o graph.Options) (config cytoscape.Config, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			message, _ := graph.PanicMessage(r)
+			err = errors.New(message)
+		}
+	}()
+
+	switch o.TelemetryVendor {
+	case graph.VendorIstio:
+		prom, promErr := prometheus.NewClient()
+		graph.CheckError(promErr)
+		_, vendorConfig := graphNamespacesIstio(business, prom, o)
+		config = vendorConfig.(cytoscape.Config)
+	default:
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+
+	return config, nil
+}
+
+// streamRequest returns the request params that define the graph, ignoring queryTime which advances
+func streamRequest(params url.Values) string {
+	request := url.Values{}
+	for k, v := range params {
+		if k != "queryTime" {
+			request[k] = v
+		}
+	}
+	return request.Encode()
+}
+
+func resumeStream(lastEventID, request string) (string, *cytoscape.Config) {
+	if lastEventID == "" {
+		return "", nil
+	}
+	streamID := strings.Split(lastEventID, ":")[0]
+
+	streamsMutex.Lock()
+	defer streamsMutex.Unlock()
+
+	entry, ok := streams[streamID]
+	if !ok || entry.request != request || time.Now().After(entry.expires) {
+		return "", nil
+	}
+	return streamID, &entry.config
+}
+
+func storeStream(streamID, request string, config cytoscape.Config, ttl time.Duration) {
+	streamsMutex.Lock()
+	defer streamsMutex.Unlock()
+
+	now := time.Now()
+	for id, entry := range streams {
+		if now.After(entry.expires) {
+			delete(streams, id)
+		}
+	}
+	streams[streamID] = streamEntry{config: config, expires: now.Add(ttl), request: request}
+}
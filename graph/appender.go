@@ -12,10 +12,12 @@ type AppenderVendorInfo map[string]interface{}
 // can re-use the information.  A new instance is generated for graph and
 // is initially empty.
 type AppenderGlobalInfo struct {
-	Business    *business.Layer
-	HomeCluster string
-	PromClient  *prometheus.Client
-	Vendor      AppenderVendorInfo // telemetry vendor's global info
+	Business         *business.Layer
+	DroppedTelemetry DroppedTelemetryReport // the telemetry samples discarded by the traffic map generation
+	HomeCluster      string
+	PromClient       *prometheus.Client
+	Vendor           AppenderVendorInfo // telemetry vendor's global info
+	Warnings         []Warning          // the failed namespaces and appenders, see CatchWarning
 }
 
 // AppenderNamespaceInfo caches information relevant to a single namespace. It allows
@@ -32,7 +34,7 @@ func NewAppenderVendorInfo() AppenderVendorInfo {
 }
 
 func NewAppenderGlobalInfo() *AppenderGlobalInfo {
-	return &AppenderGlobalInfo{Vendor: NewAppenderVendorInfo()}
+	return &AppenderGlobalInfo{DroppedTelemetry: NewDroppedTelemetryReport(), Vendor: NewAppenderVendorInfo()}
 }
 
 func NewAppenderNamespaceInfo(namespace string) *AppenderNamespaceInfo {
@@ -41,7 +43,7 @@ func NewAppenderNamespaceInfo(namespace string) *AppenderNamespaceInfo {
 
 // Appender is implemented by any code offering to append a service graph with
 // supplemental information.  On error the appender should panic and it will be
-// handled as an error response.
+// reported as a graph Warning, the graph is returned without the failed appender.
 type Appender interface {
 	// AppendGraph performs the appender work on the provided traffic map. The map
 	// may be initially empty. An appender is allowed to add or remove map entries.
@@ -0,0 +1,170 @@
+// Package config contains config vendor implementations as well as common code that can be
+// shared by each config vendor.  Cytoscape is the canonical impl, the other vendors render
+// the same node and edge data in their own formats.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+// Attribute types, named after the GraphML attr.type values
+const (
+	AttributeTypeBoolean string = "boolean"
+	AttributeTypeDouble  string = "double"
+	AttributeTypeString  string = "string"
+)
+
+// Attribute is a single, flattened node or edge value
+type Attribute struct {
+	Name  string
+	Type  string // AttributeTypeBoolean | AttributeTypeDouble | AttributeTypeString
+	Value string
+}
+
+// numericFields are cytoscape fields reported as strings that hold numeric values
+var numericFields = map[string]bool{
+	"cbEjections":  true,
+	"cbOverflow":   true,
+	"isMTLS":       true,
+	"responseTime": true,
+	"throughput":   true,
+}
+
+// NodeAttributes returns the flattened attributes of the cytoscape node data, sorted by name. Traffic
+// rates are flattened into one attribute per rate.  The structural fields (id, parent) are omitted,
+// they are expected to be expressed by the vendor format itself.
+func NodeAttributes(nd *cytoscape.NodeData) []Attribute {
+	return toAttributes(nd)
+}
+
+// EdgeAttributes returns the flattened attributes of the cytoscape edge data, sorted by name. Traffic
+// rates are flattened into one attribute per rate.  The structural fields (id, source, target) are
+// omitted, they are expected to be expressed by the vendor format itself.
+func EdgeAttributes(ed *cytoscape.EdgeData) []Attribute {
+	return toAttributes(ed)
+}
+
+// NodeLabel returns a human readable label for the cytoscape node data
+func NodeLabel(nd *cytoscape.NodeData) string {
+	switch nd.NodeType {
+	case graph.NodeTypeAggregate:
+		return nd.Aggregate
+	case graph.NodeTypeApp:
+		if nd.Version != "" {
+			return fmt.Sprintf("%s %s", nd.App, nd.Version)
+		}
+		return nd.App
+	case graph.NodeTypeBox:
+		switch nd.IsBox {
+		case graph.BoxByApp:
+			return nd.App
+		case graph.BoxByCluster:
+			return nd.Cluster
+		case graph.BoxByLabel:
+			return fmt.Sprintf("%s=%s", nd.BoxLabel, nd.Labels[nd.BoxLabel])
+		default:
+			return nd.Namespace
+		}
+	case graph.NodeTypeService:
+		return nd.Service
+	case graph.NodeTypeWorkload:
+		return nd.Workload
+	default:
+		return graph.Unknown
+	}
+}
+
+// toAttributes converts the data to attributes via its json representation, that way every field
+// exposed by the cytoscape vendor is automatically carried by the other vendors.
+func toAttributes(data interface{}) []Attribute {
+	raw, err := json.Marshal(data)
+	graph.CheckError(err)
+
+	fields := make(map[string]interface{})
+	err = json.Unmarshal(raw, &fields)
+	graph.CheckError(err)
+
+	attributes := []Attribute{}
+	for name, val := range fields {
+		switch name {
+		case "id", "parent", "source", "target":
+			continue
+		case "traffic":
+			attributes = append(attributes, trafficAttributes(val)...)
+		default:
+			attributes = append(attributes, toAttribute(name, val))
+		}
+	}
+
+	sort.Slice(attributes, func(i, j int) bool {
+		return attributes[i].Name < attributes[j].Name
+	})
+
+	return attributes
+}
+
+// trafficAttributes handles both node traffic (a list of ProtocolTraffic) and edge traffic (a single ProtocolTraffic)
+func trafficAttributes(val interface{}) []Attribute {
+	attributes := []Attribute{}
+
+	switch traffic := val.(type) {
+	case []interface{}:
+		for _, protocolTraffic := range traffic {
+			attributes = append(attributes, protocolTrafficAttributes(protocolTraffic.(map[string]interface{}), false)...)
+		}
+	case map[string]interface{}:
+		attributes = append(attributes, protocolTrafficAttributes(traffic, true)...)
+	}
+
+	return attributes
+}
+
+func protocolTrafficAttributes(protocolTraffic map[string]interface{}, isEdge bool) []Attribute {
+	attributes := []Attribute{}
+
+	// rate names are unique across protocols, so there is no need to qualify them
+	if rates, ok := protocolTraffic["rates"]; ok {
+		for rate, rateVal := range rates.(map[string]interface{}) {
+			attributes = append(attributes, Attribute{Name: rate, Type: AttributeTypeDouble, Value: rateVal.(string)})
+		}
+	}
+
+	// for nodes the protocol is implied by the rate names
+	if !isEdge {
+		return attributes
+	}
+
+	if protocol, ok := protocolTraffic["protocol"]; ok {
+		attributes = append(attributes, toAttribute("protocol", protocol))
+	}
+	if responses, ok := protocolTraffic["responses"]; ok {
+		attributes = append(attributes, toAttribute("responses", responses))
+	}
+
+	return attributes
+}
+
+func toAttribute(name string, val interface{}) Attribute {
+	switch v := val.(type) {
+	case bool:
+		return Attribute{Name: name, Type: AttributeTypeBoolean, Value: strconv.FormatBool(v)}
+	case float64:
+		return Attribute{Name: name, Type: AttributeTypeDouble, Value: strconv.FormatFloat(v, 'f', -1, 64)}
+	case string:
+		if numericFields[name] {
+			return Attribute{Name: name, Type: AttributeTypeDouble, Value: v}
+		}
+		return Attribute{Name: name, Type: AttributeTypeString, Value: v}
+	default:
+		// compound values (e.g. destServices, isServiceEntry, responses) are provided as compact json
+		raw, err := json.Marshal(v)
+		graph.CheckError(err)
+		return Attribute{Name: name, Type: AttributeTypeString, Value: string(raw)}
+	}
+}
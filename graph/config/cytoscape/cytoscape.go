@@ -18,7 +18,6 @@ import (
 	"crypto/md5"
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/kiali/kiali/graph"
 )
@@ -54,6 +53,40 @@ type ProtocolTraffic struct {
 	Responses Responses         `json:"responses,omitempty"` // see comment above
 }
 
+// DiffData describes how a node or edge changed from the baseline graph of a graph diff. Deltas
+// are current-baseline, unchanged values are omitted.
+type DiffData struct {
+	Status       string            `json:"status"`                 // added | changed | removed | unchanged
+	PercentErr   map[string]string `json:"percentErr,omitempty"`   // map[protocol]error percentage delta
+	Rates        map[string]string `json:"rates,omitempty"`        // map[rate]delta
+	ResponseTime string            `json:"responseTime,omitempty"` // response time delta, in millis
+}
+
+// AnomalyData describes an edge value deviating from its historical baseline
+type AnomalyData struct {
+	Baseline string `json:"baseline"` // the mean baseline value
+	Current  string `json:"current"`
+	Metric   string `json:"metric"` // errorRate | requestRate | responseTime
+	Score    string `json:"score"`  // the deviation from the baseline mean, in standard deviations
+}
+
+// AuthorizationData describes how the AuthorizationPolicies apply to the edge requests
+type AuthorizationData struct {
+	Decision string   `json:"decision"`           // allow | default | deny | partial
+	Policies []string `json:"policies,omitempty"` // the policies determining the decision, as namespace/name
+}
+
+// SparklineData holds the time series of edge values over the requested duration. Each series has a
+// value for every step, from start to end inclusive.
+type SparklineData struct {
+	End          int64     `json:"end"`          // unix time in seconds
+	ErrorRate    []float64 `json:"errorRate"`    // error percentage
+	RequestRate  []float64 `json:"requestRate"`  // requests per second
+	ResponseTime []float64 `json:"responseTime"` // in millis, 0 when there are no requests
+	Start        int64     `json:"start"`        // unix time in seconds
+	Step         int64     `json:"step"`         // in seconds
+}
+
 // HealthConfig maps annotations information for health
 type HealthConfig map[string]string
 
@@ -71,7 +104,14 @@ type NodeData struct {
 	Version               string              `json:"version,omitempty"`
 	Service               string              `json:"service,omitempty"`               // requested service for NodeTypeService
 	Aggregate             string              `json:"aggregate,omitempty"`             // set like "<aggregate>=<aggregateVal>"
+	BoxLabel              string              `json:"boxLabel,omitempty"`              // set for a label box, the label name
+	Labels                map[string]string   `json:"labels,omitempty"`                // values of the requested boxBy labels
+	CBEjections           string              `json:"cbEjections,omitempty"`           // max upstream hosts ejected by the incoming edges' outlier detection
+	CBOverflow            string              `json:"cbOverflow,omitempty"`            // incoming requests per second rejected by the pending requests limit
+	CustomMetrics         map[string]string   `json:"customMetrics,omitempty"`         // metric key => value, set only by the customMetrics appender
 	DestServices          []graph.ServiceName `json:"destServices,omitempty"`          // requested services for [dest] node
+	Diff                  *DiffData           `json:"diff,omitempty"`                  // set only for graph diffs
+	FlagBadges            []string            `json:"flagBadges,omitempty"`            // response flag categories reported for incoming traffic
 	Traffic               []ProtocolTraffic   `json:"traffic,omitempty"`               // traffic rates for all detected protocols
 	HasCB                 bool                `json:"hasCB,omitempty"`                 // true (has circuit breaker) | false
 	HasFaultInjection     bool                `json:"hasFaultInjection,omitempty"`     // true (vs has fault injection) | false
@@ -82,8 +122,11 @@ type NodeData struct {
 	HasTCPTrafficShifting bool                `json:"hasTCPTrafficShifting,omitempty"` // true (vs has tcp traffic shifting) | false
 	HasTrafficShifting    bool                `json:"hasTrafficShifting,omitempty"`    // true (vs has traffic shifting) | false
 	HasVS                 bool                `json:"hasVS,omitempty"`                 // true (has route rule) | false
-	IsBox                 string              `json:"isBox,omitempty"`                 // set for NodeTypeBox, current values: [ 'app', 'cluster', 'namespace' ]
+	IsAnomaly             bool                `json:"isAnomaly,omitempty"`             // true (has an anomalous incoming edge) | false
+	IsBox                 string              `json:"isBox,omitempty"`                 // set for NodeTypeBox, current values: [ 'app', 'cluster', 'label', 'namespace' ]
+	IsCBTripped           bool                `json:"isCBTripped,omitempty"`           // true (has an incoming edge with ejections or overflow) | false
 	IsDead                bool                `json:"isDead,omitempty"`                // true (has no pods) | false
+	IsFound               bool                `json:"isFound,omitempty"`               // true (matches the find expression) | false
 	IsIdle                bool                `json:"isIdle,omitempty"`                // true | false
 	IsInaccessible        bool                `json:"isInaccessible,omitempty"`        // true if the node exists in an inaccessible namespace
 	IsOutside             bool                `json:"isOutside,omitempty"`             // true | false
@@ -98,12 +141,23 @@ type EdgeData struct {
 	Target string `json:"target"` // child node ID
 
 	// App Fields (not required by Cytoscape)
-	DestPrincipal   string          `json:"destPrincipal,omitempty"`   // principal used for the edge destination
-	IsMTLS          string          `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
-	ResponseTime    string          `json:"responseTime,omitempty"`    // in millis
-	SourcePrincipal string          `json:"sourcePrincipal,omitempty"` // principal used for the edge source
-	Throughput      string          `json:"throughput,omitempty"`      // in bytes/sec (request or response, depends on client request)
-	Traffic         ProtocolTraffic `json:"traffic,omitempty"`         // traffic rates for the edge protocol
+	Anomalies       []AnomalyData      `json:"anomalies,omitempty"`       // set only by the anomaly appender
+	Authorization   *AuthorizationData `json:"authorization,omitempty"`   // set only by the authorizationPolicy appender
+	CBEjections     string             `json:"cbEjections,omitempty"`     // upstream hosts ejected by outlier detection
+	CBOverflow      string             `json:"cbOverflow,omitempty"`      // requests per second rejected by the pending requests limit
+	CustomMetrics   map[string]string  `json:"customMetrics,omitempty"`   // metric key => value, set only by the customMetrics appender
+	DestPrincipal   string             `json:"destPrincipal,omitempty"`   // principal used for the edge destination
+	Diff            *DiffData          `json:"diff,omitempty"`            // set only for graph diffs
+	FlagCategories  map[string]string  `json:"flagCategories,omitempty"`  // response flag category => percentage of traffic, set only by the responseFlags appender
+	IsAnomaly       bool               `json:"isAnomaly,omitempty"`       // true (deviates from its historical baseline) | false
+	IsCBTripped     bool               `json:"isCBTripped,omitempty"`     // true (has ejections or overflow) | false
+	IsFound         bool               `json:"isFound,omitempty"`         // true (matches the find expression) | false
+	IsMTLS          string             `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
+	ResponseTime    string             `json:"responseTime,omitempty"`    // in millis
+	SourcePrincipal string             `json:"sourcePrincipal,omitempty"` // principal used for the edge source
+	Sparklines      *SparklineData     `json:"sparklines,omitempty"`      // set only by the sparklines appender
+	Throughput      string             `json:"throughput,omitempty"`      // in bytes/sec (request or response, depends on client request)
+	Traffic         ProtocolTraffic    `json:"traffic,omitempty"`         // traffic rates for the edge protocol
 }
 
 type NodeWrapper struct {
@@ -120,10 +174,12 @@ type Elements struct {
 }
 
 type Config struct {
-	Timestamp int64    `json:"timestamp"`
-	Duration  int64    `json:"duration"`
-	GraphType string   `json:"graphType"`
-	Elements  Elements `json:"elements"`
+	Timestamp        int64                    `json:"timestamp"`
+	Duration         int64                    `json:"duration"`
+	GraphType        string                   `json:"graphType"`
+	Elements         Elements                 `json:"elements"`
+	DroppedTelemetry []graph.DroppedTelemetry `json:"droppedTelemetry,omitempty"` // the telemetry samples missing from the graph
+	Warnings         []graph.Warning          `json:"warnings,omitempty"`         // set for a partial graph, the parts that failed
 }
 
 func nodeHash(id string) string {
@@ -142,13 +198,17 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 	buildConfig(trafficMap, &nodes, &edges, o)
 
 	// Add compound nodes as needed, inner boxes first
-	if strings.Contains(o.BoxBy, graph.BoxByApp) || o.GraphType == graph.GraphTypeApp || o.GraphType == graph.GraphTypeVersionedApp {
+	if o.IsBoxBy(graph.BoxByApp) || o.GraphType == graph.GraphTypeApp || o.GraphType == graph.GraphTypeVersionedApp {
 		boxByApp(&nodes)
 	}
-	if strings.Contains(o.BoxBy, graph.BoxByNamespace) {
+	labels := graph.BoxByLabels(o.BoxBy)
+	for _, label := range labels {
+		boxByLabel(&nodes, label, o.IsBoxBy(graph.BoxByNamespace))
+	}
+	if o.IsBoxBy(graph.BoxByNamespace) {
 		boxByNamespace(&nodes)
 	}
-	if strings.Contains(o.BoxBy, graph.BoxByCluster) {
+	if o.IsBoxBy(graph.BoxByCluster) {
 		boxByCluster(&nodes)
 	}
 
@@ -156,20 +216,28 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 	// kiali-1258 parent nodes must come before the child references
 	sort.Slice(nodes, func(i, j int) bool {
 		switch {
-		case nodes[i].Data.IsBox != nodes[j].Data.IsBox:
-			rank := func(boxBy string) int {
-				switch boxBy {
+		case nodes[i].Data.IsBox != nodes[j].Data.IsBox || nodes[i].Data.BoxLabel != nodes[j].Data.BoxLabel:
+			rank := func(nd *NodeData) int {
+				switch nd.IsBox {
 				case graph.BoxByCluster:
 					return 0
 				case graph.BoxByNamespace:
 					return 1
+				case graph.BoxByLabel:
+					// the last requested label is the outermost label box
+					for i, label := range labels {
+						if label == nd.BoxLabel {
+							return 2 + len(labels) - 1 - i
+						}
+					}
+					return 2 + len(labels)
 				case graph.BoxByApp:
-					return 2
+					return 2 + len(labels)
 				default:
-					return 3
+					return 3 + len(labels)
 				}
 			}
-			return rank(nodes[i].Data.IsBox) < rank(nodes[j].Data.IsBox)
+			return rank(nodes[i].Data) < rank(nodes[j].Data)
 		case nodes[i].Data.Cluster != nodes[j].Data.Cluster:
 			return nodes[i].Data.Cluster < nodes[j].Data.Cluster
 		case nodes[i].Data.Namespace != nodes[j].Data.Namespace:
@@ -223,6 +291,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 
 		addNodeTelemetry(n, nd)
 
+		// node may have requested boxBy labels
+		if val, ok := n.Metadata[graph.Labels]; ok {
+			nd.Labels = val.(map[string]string)
+		}
+
 		// set annotations, if available
 		if val, ok := n.Metadata[graph.HasHealthConfig]; ok {
 			nd.HasHealthConfig = val.(map[string]string)
@@ -238,6 +311,33 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.IsIdle = val.(bool)
 		}
 
+		// node may have a tripped circuit breaker on incoming traffic
+		if val, ok := n.Metadata[graph.IsCBTripped]; ok {
+			nd.IsCBTripped = val.(bool)
+			nd.CBEjections = fmt.Sprintf("%.0f", n.Metadata[graph.CBEjections].(float64))
+			nd.CBOverflow = rateToString(2, n.Metadata[graph.CBOverflow].(float64))
+		}
+
+		// node may have custom metrics
+		if val, ok := n.Metadata[graph.CustomMetrics]; ok {
+			nd.CustomMetrics = newCustomMetricData(val.(map[string]float64))
+		}
+
+		// node may have anomalous incoming traffic
+		if val, ok := n.Metadata[graph.IsAnomaly]; ok {
+			nd.IsAnomaly = val.(bool)
+		}
+
+		// node may have incoming traffic with response flags
+		if val, ok := n.Metadata[graph.FlagBadges]; ok {
+			nd.FlagBadges = val.([]string)
+		}
+
+		// node may match the find expression
+		if val, ok := n.Metadata[graph.IsFound]; ok {
+			nd.IsFound = val.(bool)
+		}
+
 		// node may be a root
 		if val, ok := n.Metadata[graph.IsRoot]; ok {
 			nd.IsRoot = val.(bool)
@@ -294,6 +394,10 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			for _, val := range val.(graph.DestServicesMetadata) {
 				nd.DestServices = append(nd.DestServices, val)
 			}
+			// sort for predictable output, the metadata is a map
+			sort.Slice(nd.DestServices, func(i, j int) bool {
+				return nd.DestServices[i].Key() < nd.DestServices[j].Key()
+			})
 		}
 
 		// node may have service entry static info
@@ -301,6 +405,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.IsServiceEntry = val.(*graph.SEInfo)
 		}
 
+		// node may have graph diff info
+		if val, ok := n.Metadata[graph.Diff]; ok {
+			nd.Diff = newDiffData(val.(*graph.DiffInfo))
+		}
+
 		// node may be an aggregate
 		if n.NodeType == graph.NodeTypeAggregate {
 			nd.Aggregate = fmt.Sprintf("%s=%s", n.Metadata[graph.Aggregate].(string), n.Metadata[graph.AggregateValue].(string))
@@ -334,6 +443,33 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			if e.Metadata[graph.SourcePrincipal] != nil {
 				ed.SourcePrincipal = e.Metadata[graph.SourcePrincipal].(string)
 			}
+			if e.Metadata[graph.Diff] != nil {
+				ed.Diff = newDiffData(e.Metadata[graph.Diff].(*graph.DiffInfo))
+			}
+			if e.Metadata[graph.FlagCategories] != nil {
+				ed.FlagCategories = newFlagCategoryData(e.Metadata[graph.FlagCategories].(map[string]float64))
+			}
+			if e.Metadata[graph.IsFound] != nil {
+				ed.IsFound = e.Metadata[graph.IsFound].(bool)
+			}
+			if e.Metadata[graph.IsCBTripped] != nil {
+				ed.IsCBTripped = e.Metadata[graph.IsCBTripped].(bool)
+				ed.CBEjections = fmt.Sprintf("%.0f", e.Metadata[graph.CBEjections].(float64))
+				ed.CBOverflow = rateToString(2, e.Metadata[graph.CBOverflow].(float64))
+			}
+			if e.Metadata[graph.CustomMetrics] != nil {
+				ed.CustomMetrics = newCustomMetricData(e.Metadata[graph.CustomMetrics].(map[string]float64))
+			}
+			if e.Metadata[graph.IsAnomaly] != nil {
+				ed.IsAnomaly = e.Metadata[graph.IsAnomaly].(bool)
+				ed.Anomalies = newAnomalyData(e.Metadata[graph.Anomalies].([]*graph.Anomaly))
+			}
+			if e.Metadata[graph.Authorization] != nil {
+				ed.Authorization = newAuthorizationData(e.Metadata[graph.Authorization].(*graph.AuthorizationInfo))
+			}
+			if e.Metadata[graph.Sparklines] != nil {
+				ed.Sparklines = newSparklineData(e.Metadata[graph.Sparklines].(*graph.SparklinesInfo))
+			}
 			addEdgeTelemetry(e, &ed)
 
 			ew := EdgeWrapper{
@@ -452,6 +588,91 @@ func addEdgeTelemetry(e *graph.Edge, ed *EdgeData) {
 	}
 }
 
+func newDiffData(diff *graph.DiffInfo) *DiffData {
+	diffData := &DiffData{Status: diff.Status}
+
+	for _, p := range graph.Protocols {
+		for _, rates := range [][]graph.Rate{p.EdgeRates, p.NodeRates} {
+			for _, r := range rates {
+				if delta, ok := diff.Rates[r.Name]; ok && delta != 0.0 {
+					if diffData.Rates == nil {
+						diffData.Rates = make(map[string]string)
+					}
+					diffData.Rates[string(r.Name)] = deltaToString(r.Precision, delta)
+				}
+			}
+		}
+	}
+	for protocol, delta := range diff.PercentErr {
+		if delta != 0.0 {
+			if diffData.PercentErr == nil {
+				diffData.PercentErr = make(map[string]string)
+			}
+			diffData.PercentErr[protocol] = fmt.Sprintf("%.1f", delta)
+		}
+	}
+	if diff.ResponseTime != 0.0 {
+		diffData.ResponseTime = fmt.Sprintf("%.0f", diff.ResponseTime)
+	}
+
+	return diffData
+}
+
+func newAnomalyData(anomalies []*graph.Anomaly) []AnomalyData {
+	anomalyData := make([]AnomalyData, len(anomalies))
+	for i, anomaly := range anomalies {
+		anomalyData[i] = AnomalyData{
+			Baseline: fmt.Sprintf("%.2f", anomaly.Baseline),
+			Current:  fmt.Sprintf("%.2f", anomaly.Current),
+			Metric:   anomaly.Metric,
+			Score:    fmt.Sprintf("%.2f", anomaly.Score),
+		}
+	}
+	return anomalyData
+}
+
+func newAuthorizationData(authorization *graph.AuthorizationInfo) *AuthorizationData {
+	return &AuthorizationData{
+		Decision: authorization.Decision,
+		Policies: authorization.Policies,
+	}
+}
+
+func newSparklineData(sparklines *graph.SparklinesInfo) *SparklineData {
+	return &SparklineData{
+		End:          sparklines.End,
+		ErrorRate:    sparklines.ErrorRate,
+		RequestRate:  sparklines.RequestRate,
+		ResponseTime: sparklines.ResponseTime,
+		Start:        sparklines.Start,
+		Step:         sparklines.Step,
+	}
+}
+
+func newCustomMetricData(customMetrics map[string]float64) map[string]string {
+	customMetricData := make(map[string]string, len(customMetrics))
+	for key, value := range customMetrics {
+		customMetricData[key] = deltaToString(2, value)
+	}
+	return customMetricData
+}
+
+func newFlagCategoryData(categories map[string]float64) map[string]string {
+	categoryData := make(map[string]string, len(categories))
+	for category, percent := range categories {
+		categoryData[category] = fmt.Sprintf("%.1f", percent)
+	}
+	return categoryData
+}
+
+// deltaToString is rateToString for values that may be negative
+func deltaToString(minPrecision int, delta float64) string {
+	if delta < 0 {
+		return "-" + rateToString(minPrecision, -delta)
+	}
+	return rateToString(minPrecision, delta)
+}
+
 func getRate(md graph.Metadata, k graph.MetadataKey) float64 {
 	if rate, ok := md[k]; ok {
 		return rate.(float64)
@@ -470,7 +691,7 @@ func boxByApp(nodes *[]*NodeWrapper) {
 		}
 	}
 
-	generateBoxCompoundNodes(box, nodes, graph.BoxByApp)
+	generateBoxCompoundNodes(box, nodes, graph.BoxByApp, "")
 }
 
 // boxByNamespace adds compound nodes to box nodes in the same namespace
@@ -484,7 +705,7 @@ func boxByNamespace(nodes *[]*NodeWrapper) {
 		}
 	}
 
-	generateBoxCompoundNodes(box, nodes, graph.BoxByNamespace)
+	generateBoxCompoundNodes(box, nodes, graph.BoxByNamespace, "")
 }
 
 // boxByCluster adds compound nodes to box nodes in the same cluster
@@ -498,10 +719,29 @@ func boxByCluster(nodes *[]*NodeWrapper) {
 		}
 	}
 
-	generateBoxCompoundNodes(box, nodes, graph.BoxByCluster)
+	generateBoxCompoundNodes(box, nodes, graph.BoxByCluster, "")
 }
 
-func generateBoxCompoundNodes(box map[string][]*NodeData, nodes *[]*NodeWrapper, boxBy string) {
+// boxByLabel adds compound nodes to box nodes with the same value for the label. When also boxing by
+// namespace the label boxes are nested in the namespace boxes, so they do not span namespaces.
+func boxByLabel(nodes *[]*NodeWrapper, label string, inNamespace bool) {
+	box := make(map[string][]*NodeData)
+
+	for _, nw := range *nodes {
+		if val, ok := nw.Data.Labels[label]; ok && nw.Data.Parent == "" {
+			namespace := ""
+			if inNamespace {
+				namespace = nw.Data.Namespace
+			}
+			k := fmt.Sprintf("box_%s_%s_%s_%s", nw.Data.Cluster, namespace, label, val)
+			box[k] = append(box[k], nw.Data)
+		}
+	}
+
+	generateBoxCompoundNodes(box, nodes, graph.BoxByLabel, label)
+}
+
+func generateBoxCompoundNodes(box map[string][]*NodeData, nodes *[]*NodeWrapper, boxBy, boxLabel string) {
 	for k, members := range box {
 		if boxBy != graph.BoxByApp || len(members) > 1 {
 			// create the compound (parent) node for the member nodes
@@ -514,6 +754,8 @@ func generateBoxCompoundNodes(box map[string][]*NodeData, nodes *[]*NodeWrapper,
 			case graph.BoxByApp:
 				namespace = members[0].Namespace
 				app = members[0].App
+			case graph.BoxByLabel:
+				namespace = commonNamespace(members)
 			}
 			nd := NodeData{
 				ID:        nodeID,
@@ -523,6 +765,12 @@ func generateBoxCompoundNodes(box map[string][]*NodeData, nodes *[]*NodeWrapper,
 				App:       app,
 				Version:   "",
 				IsBox:     boxBy,
+				BoxLabel:  boxLabel,
+			}
+
+			// logical boxes (app, label) carry the labels common to their members, so they can be boxed by label
+			if boxBy == graph.BoxByApp || boxBy == graph.BoxByLabel {
+				nd.Labels = commonLabels(members)
 			}
 
 			nw := NodeWrapper{
@@ -551,6 +799,37 @@ func generateBoxCompoundNodes(box map[string][]*NodeData, nodes *[]*NodeWrapper,
 	}
 }
 
+// commonNamespace returns the namespace of the members, or "" if the members are not all in the same namespace
+func commonNamespace(members []*NodeData) string {
+	for _, n := range members {
+		if n.Namespace != members[0].Namespace {
+			return ""
+		}
+	}
+	return members[0].Namespace
+}
+
+// commonLabels returns the labels having the same value for every member, nil if there are none
+func commonLabels(members []*NodeData) map[string]string {
+	var labels map[string]string
+	for k, v := range members[0].Labels {
+		shared := true
+		for _, n := range members[1:] {
+			if n.Labels[k] != v {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
 func rateToString(minPrecision int, rateVal float64) string {
 	precision := minPrecision
 	if requiredPrecision := calcPrecision(rateVal, 5); requiredPrecision > minPrecision {
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
 )
 
 func TestRateStrings(t *testing.T) {
@@ -81,3 +84,76 @@ func TestRateStrings(t *testing.T) {
 	assert.Equal("0.0009", rateToString(2, 0.00094))
 	assert.Equal("0.0010", rateToString(2, 0.00099))
 }
+
+func TestBoxByLabel(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.UIDefaults.Graph.BoxByLabels = []string{"team"}
+	config.Set(conf)
+
+	trafficMap := graph.NewTrafficMap()
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	reviews.Metadata[graph.Labels] = map[string]string{"team": "blue"}
+	ratings := graph.NewNode(graph.Unknown, "other", "", "other", "ratings-v1", "ratings", "v1", graph.GraphTypeWorkload)
+	ratings.Metadata[graph.Labels] = map[string]string{"team": "blue"}
+	details := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeWorkload)
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[ratings.ID] = &ratings
+	trafficMap[details.ID] = &details
+
+	o := graph.ConfigOptions{BoxBy: "team", CommonOptions: graph.CommonOptions{GraphType: graph.GraphTypeWorkload}}
+	nodes := NewConfig(trafficMap, o).Elements.Nodes
+	assert.Equal(4, len(nodes))
+
+	// the label box comes first and spans both namespaces
+	box := nodes[0].Data
+	assert.Equal(graph.BoxByLabel, box.IsBox)
+	assert.Equal("team", box.BoxLabel)
+	assert.Equal("", box.Namespace)
+	assert.Equal(map[string]string{"team": "blue"}, box.Labels)
+	for _, nw := range nodes[1:] {
+		if nw.Data.Workload == "details-v1" {
+			assert.Equal("", nw.Data.Parent)
+		} else {
+			assert.Equal(box.ID, nw.Data.Parent)
+		}
+	}
+
+	// boxed by namespace, a label box is nested in each namespace box
+	o.BoxBy = "team,namespace"
+	nodes = NewConfig(trafficMap, o).Elements.Nodes
+	assert.Equal(7, len(nodes))
+	assert.Equal(graph.BoxByNamespace, nodes[0].Data.IsBox)
+	assert.Equal(graph.BoxByNamespace, nodes[1].Data.IsBox)
+	assert.Equal(graph.BoxByLabel, nodes[2].Data.IsBox)
+	assert.Equal(graph.BoxByLabel, nodes[3].Data.IsBox)
+	assert.NotEqual("", nodes[2].Data.Parent)
+	assert.NotEqual("", nodes[3].Data.Parent)
+}
+
+func TestBoxByLabelNamedLikeBox(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.UIDefaults.Graph.BoxByLabels = []string{"app.kubernetes.io/part-of", "namespace-owner"}
+	config.Set(conf)
+
+	trafficMap := graph.NewTrafficMap()
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	reviews.Metadata[graph.Labels] = map[string]string{"app.kubernetes.io/part-of": "bookinfo"}
+	ratings := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "ratings-v1", "ratings", "v1", graph.GraphTypeWorkload)
+	ratings.Metadata[graph.Labels] = map[string]string{"app.kubernetes.io/part-of": "bookinfo"}
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[ratings.ID] = &ratings
+
+	// the labels box by label only, not by app or namespace
+	boxBy := "app.kubernetes.io/part-of,namespace-owner"
+	o := graph.ConfigOptions{BoxBy: boxBy, CommonOptions: graph.CommonOptions{GraphType: graph.GraphTypeWorkload}}
+	nodes := NewConfig(trafficMap, o).Elements.Nodes
+	assert.Equal(3, len(nodes))
+	assert.Equal(graph.BoxByLabel, nodes[0].Data.IsBox)
+	assert.Equal("app.kubernetes.io/part-of", nodes[0].Data.BoxLabel)
+	assert.Equal("", nodes[1].Data.IsBox)
+	assert.Equal("", nodes[2].Data.IsBox)
+}
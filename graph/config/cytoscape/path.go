@@ -0,0 +1,84 @@
+package cytoscape
+
+// Path.go provides the request paths between two nodes of the graph. Nodes and edges are
+// referenced by their Cytoscape IDs, so that the paths can be highlighted on the graph.
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/graph"
+)
+
+type PathData struct {
+	Nodes        []string `json:"nodes"`                // node IDs, from source to destination
+	Edges        []string `json:"edges"`                // edge IDs, from source to destination
+	ErrorRate    string   `json:"errorRate"`            // compounded error percentage
+	IsCritical   bool     `json:"isCritical,omitempty"` // true for the slowest path
+	ResponseTime string   `json:"responseTime"`         // cumulative response time, in millis
+}
+
+type PathsConfig struct {
+	Timestamp        int64                    `json:"timestamp"`
+	Duration         int64                    `json:"duration"`
+	GraphType        string                   `json:"graphType"`
+	Source           string                   `json:"source"`
+	Dest             string                   `json:"dest"`
+	Paths            []PathData               `json:"paths"`
+	Truncated        bool                     `json:"truncated,omitempty"`        // true if the path search stopped at a limit, the paths are only those found
+	DroppedTelemetry []graph.DroppedTelemetry `json:"droppedTelemetry,omitempty"` // the telemetry samples missing from the graph
+	Warnings         []graph.Warning          `json:"warnings,omitempty"`         // set for a partial graph, the parts that failed
+}
+
+// NewPathsConfig returns every path between the source and dest nodes, identified by their Cytoscape IDs. When
+// the path search is truncated the result holds only the paths found, and is flagged as truncated.
+func NewPathsConfig(trafficMap graph.TrafficMap, source, dest string, o graph.ConfigOptions) PathsConfig {
+	result := PathsConfig{
+		Duration:  int64(o.Duration.Seconds()),
+		Timestamp: o.QueryTime,
+		GraphType: o.GraphType,
+		Source:    source,
+		Dest:      dest,
+		Paths:     []PathData{},
+	}
+
+	var sourceID, destID string
+	for id := range trafficMap {
+		switch nodeHash(id) {
+		case source:
+			sourceID = id
+		case dest:
+			destID = id
+		}
+	}
+	if sourceID == "" {
+		graph.BadRequest(fmt.Sprintf("Source node [%s] not found in the graph", source))
+	}
+	if destID == "" {
+		graph.BadRequest(fmt.Sprintf("Dest node [%s] not found in the graph", dest))
+	}
+
+	paths, complete := graph.FindPaths(trafficMap, sourceID, destID)
+	result.Truncated = !complete
+	for _, path := range paths {
+		pd := PathData{
+			Nodes:        []string{source},
+			Edges:        []string{},
+			ErrorRate:    fmt.Sprintf("%.2f", path.ErrorRate),
+			IsCritical:   path.IsCritical,
+			ResponseTime: fmt.Sprintf("%.0f", path.ResponseTime),
+		}
+		for _, e := range path.Edges {
+			sourceIDHash := nodeHash(e.Source.ID)
+			destIDHash := nodeHash(e.Dest.ID)
+			protocol := ""
+			if e.Metadata[graph.ProtocolKey] != nil {
+				protocol = e.Metadata[graph.ProtocolKey].(string)
+			}
+			pd.Nodes = append(pd.Nodes, destIDHash)
+			pd.Edges = append(pd.Edges, edgeHash(sourceIDHash, destIDHash, protocol))
+		}
+		result.Paths = append(result.Paths, pd)
+	}
+
+	return result
+}
@@ -0,0 +1,88 @@
+package cytoscape
+
+// Update.go provides the incremental changes between two Configs generated for the same graph
+// request, typically at consecutive refresh times.
+
+import (
+	"reflect"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// RemovedElements holds the IDs of the removed nodes and edges
+type RemovedElements struct {
+	Nodes []string `json:"nodes"`
+	Edges []string `json:"edges"`
+}
+
+// ConfigUpdate holds the changes from a previous Config to the current Config. Added and changed
+// elements are provided in full, removed elements only by ID.
+type ConfigUpdate struct {
+	Timestamp        int64                    `json:"timestamp"`
+	Duration         int64                    `json:"duration"`
+	GraphType        string                   `json:"graphType"`
+	Added            Elements                 `json:"added"`
+	Changed          Elements                 `json:"changed"`
+	Removed          RemovedElements          `json:"removed"`
+	DroppedTelemetry []graph.DroppedTelemetry `json:"droppedTelemetry,omitempty"` // the current dropped telemetry
+	Warnings         []graph.Warning          `json:"warnings,omitempty"`         // the current warnings, set for a partial graph
+}
+
+// NewConfigUpdate returns the changes required to transform the previous Config into the current Config
+func NewConfigUpdate(previous, current Config) ConfigUpdate {
+	update := ConfigUpdate{
+		Timestamp:        current.Timestamp,
+		Duration:         current.Duration,
+		GraphType:        current.GraphType,
+		Added:            Elements{Nodes: []*NodeWrapper{}, Edges: []*EdgeWrapper{}},
+		Changed:          Elements{Nodes: []*NodeWrapper{}, Edges: []*EdgeWrapper{}},
+		Removed:          RemovedElements{Nodes: []string{}, Edges: []string{}},
+		DroppedTelemetry: current.DroppedTelemetry,
+		Warnings:         current.Warnings,
+	}
+
+	previousNodes := make(map[string]*NodeData, len(previous.Elements.Nodes))
+	for _, nw := range previous.Elements.Nodes {
+		previousNodes[nw.Data.ID] = nw.Data
+	}
+	currentNodes := make(map[string]bool, len(current.Elements.Nodes))
+	for _, nw := range current.Elements.Nodes {
+		currentNodes[nw.Data.ID] = true
+		previousNode, ok := previousNodes[nw.Data.ID]
+		switch {
+		case !ok:
+			update.Added.Nodes = append(update.Added.Nodes, nw)
+		case !reflect.DeepEqual(previousNode, nw.Data):
+			update.Changed.Nodes = append(update.Changed.Nodes, nw)
+		}
+	}
+	// keep the previous ordering, so parent (box) nodes are removed first
+	for _, nw := range previous.Elements.Nodes {
+		if !currentNodes[nw.Data.ID] {
+			update.Removed.Nodes = append(update.Removed.Nodes, nw.Data.ID)
+		}
+	}
+
+	previousEdges := make(map[string]*EdgeData, len(previous.Elements.Edges))
+	for _, ew := range previous.Elements.Edges {
+		previousEdges[ew.Data.ID] = ew.Data
+	}
+	currentEdges := make(map[string]bool, len(current.Elements.Edges))
+	for _, ew := range current.Elements.Edges {
+		currentEdges[ew.Data.ID] = true
+		previousEdge, ok := previousEdges[ew.Data.ID]
+		switch {
+		case !ok:
+			update.Added.Edges = append(update.Added.Edges, ew)
+		case !reflect.DeepEqual(previousEdge, ew.Data):
+			update.Changed.Edges = append(update.Changed.Edges, ew)
+		}
+	}
+	for _, ew := range previous.Elements.Edges {
+		if !currentEdges[ew.Data.ID] {
+			update.Removed.Edges = append(update.Removed.Edges, ew.Data.ID)
+		}
+	}
+
+	return update
+}
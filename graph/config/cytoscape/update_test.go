@@ -0,0 +1,64 @@
+package cytoscape
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := Config{
+		Timestamp: 100,
+		Elements: Elements{
+			Nodes: []*NodeWrapper{
+				{Data: &NodeData{ID: "a", Workload: "a"}},
+				{Data: &NodeData{ID: "b", Workload: "b"}},
+				{Data: &NodeData{ID: "c", Workload: "c"}},
+			},
+			Edges: []*EdgeWrapper{
+				{Data: &EdgeData{ID: "ab", Source: "a", Target: "b", ResponseTime: "10"}},
+				{Data: &EdgeData{ID: "ac", Source: "a", Target: "c"}},
+			},
+		},
+	}
+	current := Config{
+		Timestamp: 115,
+		Elements: Elements{
+			Nodes: []*NodeWrapper{
+				{Data: &NodeData{ID: "a", Workload: "a"}},
+				{Data: &NodeData{ID: "b", Workload: "b", IsDead: true}},
+				{Data: &NodeData{ID: "d", Workload: "d"}},
+			},
+			Edges: []*EdgeWrapper{
+				{Data: &EdgeData{ID: "ab", Source: "a", Target: "b", ResponseTime: "20"}},
+				{Data: &EdgeData{ID: "ad", Source: "a", Target: "d"}},
+			},
+		},
+	}
+
+	update := NewConfigUpdate(previous, current)
+
+	assert.Equal(int64(115), update.Timestamp)
+	assert.Equal(1, len(update.Added.Nodes))
+	assert.Equal("d", update.Added.Nodes[0].Data.ID)
+	assert.Equal(1, len(update.Changed.Nodes))
+	assert.Equal("b", update.Changed.Nodes[0].Data.ID)
+	assert.Equal([]string{"c"}, update.Removed.Nodes)
+
+	assert.Equal(1, len(update.Added.Edges))
+	assert.Equal("ad", update.Added.Edges[0].Data.ID)
+	assert.Equal(1, len(update.Changed.Edges))
+	assert.Equal("ab", update.Changed.Edges[0].Data.ID)
+	assert.Equal([]string{"ac"}, update.Removed.Edges)
+
+	// no changes
+	update = NewConfigUpdate(current, current)
+	assert.Empty(update.Added.Nodes)
+	assert.Empty(update.Changed.Nodes)
+	assert.Empty(update.Removed.Nodes)
+	assert.Empty(update.Added.Edges)
+	assert.Empty(update.Changed.Edges)
+	assert.Empty(update.Removed.Edges)
+}
@@ -0,0 +1,141 @@
+// Package dot provides conversion from our graph to the Graphviz DOT language.
+//
+// The following links are useful for understanding DOT:
+//
+// Main page: https://graphviz.org/
+// Language:  https://graphviz.org/doc/info/lang.html
+//
+// Algorithm: Generate the Cytoscape config, which decorates each node and edge with
+//            the information provided, and then render its nodes and edges as a DOT
+//            digraph. Boxing is represented with nested "cluster" subgraphs. Node and
+//            edge information is provided as (non-Graphviz) attributes, which are
+//            ignored by the renderers but available to any DOT consumer.
+//
+// The package provides the DOT implementation of graph/ConfigVendor.
+package dot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+type Node struct {
+	ID         string
+	Label      string
+	Attributes []config.Attribute
+	Members    []*Node // set for box nodes, rendered as a cluster subgraph
+}
+
+type Edge struct {
+	Source     string
+	Target     string
+	Attributes []config.Attribute
+}
+
+type Config struct {
+	Duration         int64
+	GraphType        string
+	Timestamp        int64
+	Nodes            []*Node
+	Edges            []*Edge
+	DroppedTelemetry []graph.DroppedTelemetry // the telemetry samples missing from the graph, rendered as comments
+	Warnings         []graph.Warning          // set for a partial graph, rendered as comments
+}
+
+// NewConfig is required by the graph/ConfigVendor interface
+func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Config) {
+	cytoscapeConfig := cytoscape.NewConfig(trafficMap, o)
+
+	result = Config{
+		Duration:  cytoscapeConfig.Duration,
+		GraphType: cytoscapeConfig.GraphType,
+		Timestamp: cytoscapeConfig.Timestamp,
+		Nodes:     []*Node{},
+		Edges:     []*Edge{},
+	}
+
+	// first create every node, then nest them, so we don't depend on parent ordering
+	nodes := make(map[string]*Node, len(cytoscapeConfig.Elements.Nodes))
+	for _, nw := range cytoscapeConfig.Elements.Nodes {
+		nodes[nw.Data.ID] = &Node{
+			ID:         nw.Data.ID,
+			Label:      config.NodeLabel(nw.Data),
+			Attributes: config.NodeAttributes(nw.Data),
+		}
+	}
+	for _, nw := range cytoscapeConfig.Elements.Nodes {
+		n := nodes[nw.Data.ID]
+		if parent, ok := nodes[nw.Data.Parent]; ok {
+			parent.Members = append(parent.Members, n)
+		} else {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+
+	for _, ew := range cytoscapeConfig.Elements.Edges {
+		result.Edges = append(result.Edges, &Edge{
+			Source:     ew.Data.Source,
+			Target:     ew.Data.Target,
+			Attributes: config.EdgeAttributes(ew.Data),
+		})
+	}
+
+	return result
+}
+
+// String renders the config as a DOT digraph
+func (c Config) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph \"kiali\" {\n")
+	fmt.Fprintf(&sb, "  graph [duration=%s graphType=%s timestamp=%s];\n", quote(fmt.Sprintf("%d", c.Duration)), quote(c.GraphType), quote(fmt.Sprintf("%d", c.Timestamp)))
+	for _, w := range c.Warnings {
+		fmt.Fprintf(&sb, "  // warning: namespace=%s appender=%s cluster=%s code=%d message=%s\n", w.Namespace, w.Appender, w.Cluster, w.Code, strings.ReplaceAll(w.Message, "\n", " "))
+	}
+	for _, d := range c.DroppedTelemetry {
+		fmt.Fprintf(&sb, "  // dropped telemetry: namespace=%s reason=%s count=%d\n", d.Namespace, d.Reason, d.Count)
+	}
+	for _, n := range c.Nodes {
+		writeNode(&sb, n, "  ")
+	}
+	for _, e := range c.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s [%s];\n", quote(e.Source), quote(e.Target), attributeList(e.Attributes))
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+func writeNode(sb *strings.Builder, n *Node, indent string) {
+	if len(n.Members) == 0 {
+		fmt.Fprintf(sb, "%s%s [label=%s %s];\n", indent, quote(n.ID), quote(n.Label), attributeList(n.Attributes))
+		return
+	}
+
+	// Graphviz draws a box around subgraphs whose name starts with "cluster"
+	fmt.Fprintf(sb, "%ssubgraph %s {\n", indent, quote(fmt.Sprintf("cluster_%s", n.ID)))
+	fmt.Fprintf(sb, "%s  graph [label=%s %s];\n", indent, quote(n.Label), attributeList(n.Attributes))
+	for _, member := range n.Members {
+		writeNode(sb, member, indent+"  ")
+	}
+	fmt.Fprintf(sb, "%s}\n", indent)
+}
+
+func attributeList(attributes []config.Attribute) string {
+	list := make([]string, len(attributes))
+	for i, a := range attributes {
+		list[i] = fmt.Sprintf("%s=%s", a.Name, quote(a.Value))
+	}
+	return strings.Join(list, " ")
+}
+
+// quote returns a DOT double-quoted string
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return fmt.Sprintf(`"%s"`, s)
+}
@@ -0,0 +1,55 @@
+package dot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestNewConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+
+	edge := productpage.AddEdge(&reviews)
+	edge.Metadata[graph.ProtocolKey] = "tcp"
+	edge.Metadata[graph.IsMTLS] = 100.0
+	graph.AddToMetadata("tcp", 31.0, "", "-", "reviews.bookinfo.svc.cluster.local", productpage.Metadata, reviews.Metadata, edge.Metadata)
+
+	o := graph.ConfigOptions{
+		BoxBy: graph.BoxByNamespace,
+		CommonOptions: graph.CommonOptions{
+			Duration:  time.Duration(600) * time.Second,
+			GraphType: graph.GraphTypeWorkload,
+			QueryTime: 1000,
+		},
+	}
+	config := NewConfig(trafficMap, o)
+
+	assert.Equal(1, len(config.Nodes))
+	assert.Equal(2, len(config.Nodes[0].Members))
+	assert.Equal("bookinfo", config.Nodes[0].Label)
+	assert.Equal(1, len(config.Edges))
+
+	dot := config.String()
+	assert.True(strings.HasPrefix(dot, "digraph \"kiali\" {\n"))
+	assert.Contains(dot, `graph [duration="600" graphType="workload" timestamp="1000"];`)
+	assert.Contains(dot, "subgraph \"cluster_")
+	assert.Contains(dot, `[label="productpage-v1" `)
+	assert.Contains(dot, `isMTLS="100" protocol="tcp" responses="{\"-\":{\"flags\":{\"-\":\"100.0\"},\"hosts\":{\"reviews.bookinfo.svc.cluster.local\":\"100.0\"}}}" tcp="31.00"`)
+}
+
+func TestQuote(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`"plain"`, quote("plain"))
+	assert.Equal(`"{\"a\":\"b\\c\"}"`, quote(`{"a":"b\c"}`))
+}
@@ -0,0 +1,197 @@
+// Package graphml provides conversion from our graph to the GraphML xml model.
+//
+// The following links are useful for understanding GraphML:
+//
+// Main page: http://graphml.graphdrawing.org/
+// Primer:    http://graphml.graphdrawing.org/primer/graphml-primer.html
+//
+// Algorithm: Generate the Cytoscape config, which decorates each node and edge with
+//            the information provided, and then render its nodes and edges as GraphML.
+//            Boxing is represented with nested graphs, each box node holding a subgraph
+//            of its member nodes. Node and edge information is provided as <data> elements,
+//            with a <key> declared for each attribute.
+//
+// The package provides the GraphML implementation of graph/ConfigVendor.
+package graphml
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+const (
+	graphmlNamespace string = "http://graphml.graphdrawing.org/xmlns"
+	edgeDefault      string = "directed"
+	forEdge          string = "edge"
+	forGraph         string = "graph"
+	forNode          string = "node"
+)
+
+// Key declares a GraphML attribute
+type Key struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+// Data holds the value of a declared attribute
+type Data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type Node struct {
+	ID    string `xml:"id,attr"`
+	Data  []Data `xml:"data"`
+	Graph *Graph `xml:"graph,omitempty"` // set for box nodes, holds the member nodes
+}
+
+type Edge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []Data `xml:"data"`
+}
+
+type Graph struct {
+	ID          string  `xml:"id,attr"`
+	EdgeDefault string  `xml:"edgedefault,attr"`
+	Data        []Data  `xml:"data"`
+	Nodes       []*Node `xml:"node"`
+	Edges       []*Edge `xml:"edge"`
+}
+
+type Config struct {
+	XMLName xml.Name `xml:"graphml"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Keys    []Key    `xml:"key"`
+	Graph   Graph    `xml:"graph"`
+}
+
+// keys collects the attribute declarations while the config is generated
+type keys map[string]Key
+
+func (k keys) data(domain string, attribute config.Attribute) Data {
+	id := fmt.Sprintf("%s_%s", domain, attribute.Name)
+	if _, ok := k[id]; !ok {
+		k[id] = Key{ID: id, For: domain, AttrName: attribute.Name, AttrType: attribute.Type}
+	}
+	return Data{Key: id, Value: attribute.Value}
+}
+
+// NewConfig is required by the graph/ConfigVendor interface
+func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Config) {
+	cytoscapeConfig := cytoscape.NewConfig(trafficMap, o)
+	declaredKeys := make(keys)
+
+	root := Graph{
+		ID:          "G",
+		EdgeDefault: edgeDefault,
+		Data: []Data{
+			declaredKeys.data(forGraph, config.Attribute{Name: "duration", Type: config.AttributeTypeDouble, Value: fmt.Sprintf("%d", cytoscapeConfig.Duration)}),
+			declaredKeys.data(forGraph, config.Attribute{Name: "graphType", Type: config.AttributeTypeString, Value: cytoscapeConfig.GraphType}),
+			declaredKeys.data(forGraph, config.Attribute{Name: "timestamp", Type: config.AttributeTypeDouble, Value: fmt.Sprintf("%d", cytoscapeConfig.Timestamp)}),
+		},
+		Nodes: []*Node{},
+		Edges: []*Edge{},
+	}
+
+	// first create every node, then nest them, so we don't depend on parent ordering
+	nodes := make(map[string]*Node, len(cytoscapeConfig.Elements.Nodes))
+	for _, nw := range cytoscapeConfig.Elements.Nodes {
+		n := &Node{
+			ID:   nw.Data.ID,
+			Data: []Data{declaredKeys.data(forNode, config.Attribute{Name: "label", Type: config.AttributeTypeString, Value: config.NodeLabel(nw.Data)})},
+		}
+		for _, attribute := range config.NodeAttributes(nw.Data) {
+			n.Data = append(n.Data, declaredKeys.data(forNode, attribute))
+		}
+		nodes[n.ID] = n
+	}
+	for _, nw := range cytoscapeConfig.Elements.Nodes {
+		n := nodes[nw.Data.ID]
+		parent, ok := nodes[nw.Data.Parent]
+		if !ok {
+			root.Nodes = append(root.Nodes, n)
+			continue
+		}
+		if parent.Graph == nil {
+			parent.Graph = &Graph{
+				ID:          fmt.Sprintf("%s:", parent.ID),
+				EdgeDefault: edgeDefault,
+			}
+		}
+		parent.Graph.Nodes = append(parent.Graph.Nodes, n)
+	}
+
+	// edges may connect nodes in different boxes, so they are all declared in the root graph
+	for _, ew := range cytoscapeConfig.Elements.Edges {
+		e := &Edge{
+			ID:     ew.Data.ID,
+			Source: ew.Data.Source,
+			Target: ew.Data.Target,
+		}
+		for _, attribute := range config.EdgeAttributes(ew.Data) {
+			e.Data = append(e.Data, declaredKeys.data(forEdge, attribute))
+		}
+		root.Edges = append(root.Edges, e)
+	}
+
+	result = Config{
+		XMLNS: graphmlNamespace,
+		Keys:  []Key{},
+		Graph: root,
+	}
+	for _, k := range declaredKeys {
+		result.Keys = append(result.Keys, k)
+	}
+	sort.Slice(result.Keys, func(i, j int) bool {
+		return result.Keys[i].ID < result.Keys[j].ID
+	})
+
+	return result
+}
+
+// AddWarnings reports the warnings of a partial graph as the graph "warnings" attribute, in compact json
+func (c *Config) AddWarnings(warnings []graph.Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+	c.addGraphAttribute("warnings", warnings)
+}
+
+// AddDroppedTelemetry reports the telemetry samples missing from the graph as the graph "droppedTelemetry"
+// attribute, in compact json
+func (c *Config) AddDroppedTelemetry(droppedTelemetry []graph.DroppedTelemetry) {
+	if len(droppedTelemetry) == 0 {
+		return
+	}
+	c.addGraphAttribute("droppedTelemetry", droppedTelemetry)
+}
+
+// addGraphAttribute adds a graph attribute holding the value in compact json, declaring its key
+func (c *Config) addGraphAttribute(name string, value interface{}) {
+	raw, err := json.Marshal(value)
+	graph.CheckError(err)
+
+	declaredKeys := make(keys, len(c.Keys)+1)
+	for _, k := range c.Keys {
+		declaredKeys[k.ID] = k
+	}
+	c.Graph.Data = append(c.Graph.Data, declaredKeys.data(forGraph, config.Attribute{Name: name, Type: config.AttributeTypeString, Value: string(raw)}))
+
+	c.Keys = []Key{}
+	for _, k := range declaredKeys {
+		c.Keys = append(c.Keys, k)
+	}
+	sort.Slice(c.Keys, func(i, j int) bool {
+		return c.Keys[i].ID < c.Keys[j].ID
+	})
+}
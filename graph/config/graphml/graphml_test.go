@@ -0,0 +1,72 @@
+package graphml
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func trafficMap() graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage := graph.NewNode("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+
+	edge := productpage.AddEdge(&reviews)
+	edge.Metadata[graph.ProtocolKey] = "http"
+	edge.Metadata[graph.ResponseTime] = 20.0
+	graph.AddToMetadata("http", 10.0, "200", "-", "reviews.bookinfo.svc.cluster.local", productpage.Metadata, reviews.Metadata, edge.Metadata)
+	graph.AddToMetadata("http", 2.0, "500", "-", "reviews.bookinfo.svc.cluster.local", productpage.Metadata, reviews.Metadata, edge.Metadata)
+
+	return trafficMap
+}
+
+func TestNewConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.ConfigOptions{
+		BoxBy: graph.BoxByNamespace,
+		CommonOptions: graph.CommonOptions{
+			Duration:  time.Duration(600) * time.Second,
+			GraphType: graph.GraphTypeWorkload,
+			QueryTime: 1000,
+		},
+	}
+	config := NewConfig(trafficMap(), o)
+
+	assert.Equal(graphmlNamespace, config.XMLNS)
+	assert.Equal(3, len(config.Graph.Data))
+
+	// the namespace box holds both workload nodes
+	assert.Equal(1, len(config.Graph.Nodes))
+	box := config.Graph.Nodes[0]
+	assert.NotNil(box.Graph)
+	assert.Equal(2, len(box.Graph.Nodes))
+	assert.Contains(box.Data, Data{Key: "node_isBox", Value: graph.BoxByNamespace})
+	assert.Contains(box.Data, Data{Key: "node_label", Value: "bookinfo"})
+
+	assert.Equal(1, len(config.Graph.Edges))
+	edge := config.Graph.Edges[0]
+	assert.Contains(edge.Data, Data{Key: "edge_protocol", Value: "http"})
+	assert.Contains(edge.Data, Data{Key: "edge_http", Value: "12.00"})
+	assert.Contains(edge.Data, Data{Key: "edge_http5xx", Value: "2.00"})
+	assert.Contains(edge.Data, Data{Key: "edge_httpPercentErr", Value: "16.7"})
+	assert.Contains(edge.Data, Data{Key: "edge_responseTime", Value: "20"})
+
+	keysByID := make(map[string]Key)
+	for _, k := range config.Keys {
+		keysByID[k.ID] = k
+	}
+	assert.Equal(Key{ID: "edge_http", For: "edge", AttrName: "http", AttrType: "double"}, keysByID["edge_http"])
+	assert.Equal(Key{ID: "node_workload", For: "node", AttrName: "workload", AttrType: "string"}, keysByID["node_workload"])
+	assert.Equal(Key{ID: "graph_graphType", For: "graph", AttrName: "graphType", AttrType: "string"}, keysByID["graph_graphType"])
+
+	_, err := xml.Marshal(config)
+	assert.NoError(err)
+}
@@ -0,0 +1,201 @@
+package graph
+
+// Diff.go provides support for comparing a TrafficMap with a baseline TrafficMap, typically the same
+// graph generated for an earlier queryTime.
+
+// The possible DiffInfo.Status values
+const (
+	DiffAdded     string = "added"     // only in the current graph
+	DiffChanged   string = "changed"   // in both graphs, with different telemetry
+	DiffRemoved   string = "removed"   // only in the baseline graph
+	DiffUnchanged string = "unchanged" // in both graphs, with the same telemetry
+)
+
+// DiffInfo describes how a node or edge changed from the baseline graph to the current graph. Deltas
+// are always calculated as current-baseline, with missing values treated as 0.
+type DiffInfo struct {
+	Status       string
+	PercentErr   map[string]float64      // error percentage delta for each protocol reported in either graph
+	Rates        map[MetadataKey]float64 // delta for each rate reported in either graph
+	ResponseTime float64                 // response time delta, in millis (edges only)
+}
+
+// DiffTrafficMaps merges the baseline traffic map into the current traffic map and sets Diff metadata
+// on every node and edge. Nodes and edges found only in the baseline are added to the current traffic
+// map, keeping their baseline telemetry.  The merged (current) traffic map is returned.
+func DiffTrafficMaps(trafficMap, baselineTrafficMap TrafficMap) TrafficMap {
+	// first add the removed nodes, so that removed edges have both endpoints available
+	for id, baselineNode := range baselineTrafficMap {
+		if _, ok := trafficMap[id]; !ok {
+			trafficMap[id] = &Node{
+				ID:        id,
+				NodeType:  baselineNode.NodeType,
+				Cluster:   baselineNode.Cluster,
+				Namespace: baselineNode.Namespace,
+				Workload:  baselineNode.Workload,
+				App:       baselineNode.App,
+				Version:   baselineNode.Version,
+				Service:   baselineNode.Service,
+				Edges:     []*Edge{},
+				Metadata:  copyMetadata(baselineNode.Metadata),
+			}
+			trafficMap[id].Metadata[Diff] = newNodeDiffInfo(NewMetadata(), baselineNode.Metadata, DiffRemoved)
+		}
+	}
+
+	for id, n := range trafficMap {
+		baselineNode, ok := baselineTrafficMap[id]
+		if !ok {
+			n.Metadata[Diff] = newNodeDiffInfo(n.Metadata, NewMetadata(), DiffAdded)
+			continue
+		}
+		if _, ok := n.Metadata[Diff]; !ok {
+			n.Metadata[Diff] = newNodeDiffInfo(n.Metadata, baselineNode.Metadata, "")
+		}
+
+		for _, baselineEdge := range baselineNode.Edges {
+			e := findEdge(n, baselineEdge.Dest.ID, baselineEdge.Metadata[ProtocolKey])
+			if e == nil {
+				removedEdge := n.AddEdge(trafficMap[baselineEdge.Dest.ID])
+				removedEdge.Metadata = copyMetadata(baselineEdge.Metadata)
+				removedEdge.Metadata[Diff] = newEdgeDiffInfo(NewMetadata(), baselineEdge.Metadata, DiffRemoved)
+				continue
+			}
+			e.Metadata[Diff] = newEdgeDiffInfo(e.Metadata, baselineEdge.Metadata, "")
+		}
+	}
+
+	// anything not yet marked is new in the current graph
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			if _, ok := e.Metadata[Diff]; !ok {
+				e.Metadata[Diff] = newEdgeDiffInfo(e.Metadata, NewMetadata(), DiffAdded)
+			}
+		}
+	}
+
+	return trafficMap
+}
+
+func findEdge(n *Node, destID string, protocol interface{}) *Edge {
+	for _, e := range n.Edges {
+		if e.Dest.ID == destID && e.Metadata[ProtocolKey] == protocol {
+			return e
+		}
+	}
+	return nil
+}
+
+func copyMetadata(md Metadata) Metadata {
+	result := NewMetadata()
+	for k, v := range md {
+		result[k] = v
+	}
+	return result
+}
+
+func newNodeDiffInfo(md, baselineMd Metadata, status string) *DiffInfo {
+	diff := &DiffInfo{PercentErr: make(map[string]float64), Rates: make(map[MetadataKey]float64)}
+
+	// a node may serve several protocols, the error percentages are compared for each of them
+	for _, p := range Protocols {
+		for _, r := range p.NodeRates {
+			addRateDelta(diff, r.Name, md, baselineMd)
+		}
+		addPercentErrDelta(diff, p.Name, p.NodeRates, func(r Rate) bool { return r.IsIn }, md, baselineMd)
+	}
+
+	diff.Status = diffStatus(diff, status)
+	return diff
+}
+
+func newEdgeDiffInfo(md, baselineMd Metadata, status string) *DiffInfo {
+	diff := &DiffInfo{PercentErr: make(map[string]float64), Rates: make(map[MetadataKey]float64)}
+
+	// an edge represents traffic for at most one protocol, but it is harmless to check them all
+	for _, p := range Protocols {
+		for _, r := range p.EdgeRates {
+			if r.IsPercentErr || r.IsPercentReq {
+				continue
+			}
+			addRateDelta(diff, r.Name, md, baselineMd)
+		}
+		addPercentErrDelta(diff, p.Name, p.EdgeRates, func(r Rate) bool { return r.IsTotal }, md, baselineMd)
+	}
+	diff.ResponseTime = metadataValue(md, ResponseTime) - metadataValue(baselineMd, ResponseTime)
+
+	diff.Status = diffStatus(diff, status)
+	return diff
+}
+
+func addRateDelta(diff *DiffInfo, k MetadataKey, md, baselineMd Metadata) {
+	_, ok := md[k]
+	_, baselineOk := baselineMd[k]
+	if ok || baselineOk {
+		diff.Rates[k] = metadataValue(md, k) - metadataValue(baselineMd, k)
+	}
+}
+
+// addPercentErrDelta sets the error percentage delta of the protocol, if either graph reports its total rate
+func addPercentErrDelta(diff *DiffInfo, protocol string, rates []Rate, isTotal func(Rate) bool, md, baselineMd Metadata) {
+	for _, r := range rates {
+		if !isTotal(r) {
+			continue
+		}
+		_, ok := md[r.Name]
+		_, baselineOk := baselineMd[r.Name]
+		if ok || baselineOk {
+			diff.PercentErr[protocol] = percentErr(md, rates, isTotal) - percentErr(baselineMd, rates, isTotal)
+		}
+		return
+	}
+}
+
+// percentErr returns the error percentage of the total traffic, the total rate is the first rate satisfying isTotal
+func percentErr(md Metadata, rates []Rate, isTotal func(Rate) bool) float64 {
+	total := 0.0
+	totalFound := false
+	err := 0.0
+	for _, r := range rates {
+		switch {
+		case isTotal(r):
+			if !totalFound {
+				total = metadataValue(md, r.Name)
+				totalFound = true
+			}
+		case r.IsErr:
+			err += metadataValue(md, r.Name)
+		}
+	}
+	if total == 0.0 {
+		return 0.0
+	}
+	return err / total * 100.0
+}
+
+func diffStatus(diff *DiffInfo, status string) string {
+	if status != "" {
+		return status
+	}
+	if diff.ResponseTime != 0.0 {
+		return DiffChanged
+	}
+	for _, delta := range diff.PercentErr {
+		if delta != 0.0 {
+			return DiffChanged
+		}
+	}
+	for _, delta := range diff.Rates {
+		if delta != 0.0 {
+			return DiffChanged
+		}
+	}
+	return DiffUnchanged
+}
+
+func metadataValue(md Metadata, k MetadataKey) float64 {
+	if val, ok := md[k]; ok {
+		return val.(float64)
+	}
+	return 0.0
+}
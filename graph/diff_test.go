@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTrafficMaps(t *testing.T) {
+	assert := assert.New(t)
+
+	// baseline: a -> b (http), a -> c (tcp)
+	baseline := NewTrafficMap()
+	baseA := NewNode("east", "bookinfo", "", "bookinfo", "a-v1", "a", "v1", GraphTypeWorkload)
+	baseB := NewNode("east", "bookinfo", "", "bookinfo", "b-v1", "b", "v1", GraphTypeWorkload)
+	baseC := NewNode("east", "bookinfo", "", "bookinfo", "c-v1", "c", "v1", GraphTypeWorkload)
+	baseline[baseA.ID] = &baseA
+	baseline[baseB.ID] = &baseB
+	baseline[baseC.ID] = &baseC
+	e := baseA.AddEdge(&baseB)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 10.0
+	AddToMetadata("http", 10.0, "200", "-", "b", baseA.Metadata, baseB.Metadata, e.Metadata)
+	e = baseA.AddEdge(&baseC)
+	e.Metadata[ProtocolKey] = "tcp"
+	AddToMetadata("tcp", 100.0, "", "-", "c", baseA.Metadata, baseC.Metadata, e.Metadata)
+
+	// current: a -> b (http, with errors and slower), a -> d (http), d -> b (grpc, with errors)
+	current := NewTrafficMap()
+	a := NewNode("east", "bookinfo", "", "bookinfo", "a-v1", "a", "v1", GraphTypeWorkload)
+	b := NewNode("east", "bookinfo", "", "bookinfo", "b-v1", "b", "v1", GraphTypeWorkload)
+	d := NewNode("east", "bookinfo", "", "bookinfo", "d-v1", "d", "v1", GraphTypeWorkload)
+	current[a.ID] = &a
+	current[b.ID] = &b
+	current[d.ID] = &d
+	e = a.AddEdge(&b)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 25.0
+	AddToMetadata("http", 8.0, "200", "-", "b", a.Metadata, b.Metadata, e.Metadata)
+	AddToMetadata("http", 2.0, "500", "-", "b", a.Metadata, b.Metadata, e.Metadata)
+	e = a.AddEdge(&d)
+	e.Metadata[ProtocolKey] = "http"
+	AddToMetadata("http", 5.0, "200", "-", "d", a.Metadata, d.Metadata, e.Metadata)
+	e = d.AddEdge(&b)
+	e.Metadata[ProtocolKey] = "grpc"
+	AddToMetadata("grpc", 3.0, "0", "-", "b", d.Metadata, b.Metadata, e.Metadata)
+	AddToMetadata("grpc", 1.0, "14", "-", "b", d.Metadata, b.Metadata, e.Metadata)
+
+	trafficMap := DiffTrafficMaps(current, baseline)
+
+	assert.Equal(4, len(trafficMap))
+	assert.Equal(DiffChanged, trafficMap[a.ID].Metadata[Diff].(*DiffInfo).Status)
+	assert.Equal(DiffChanged, trafficMap[b.ID].Metadata[Diff].(*DiffInfo).Status)
+	assert.Equal(DiffRemoved, trafficMap[baseC.ID].Metadata[Diff].(*DiffInfo).Status)
+	assert.Equal(DiffAdded, trafficMap[d.ID].Metadata[Diff].(*DiffInfo).Status)
+	// the error percentages of the http and grpc traffic of b are compared separately
+	assert.Equal(map[string]float64{"grpc": 25.0, "http": 20.0}, trafficMap[b.ID].Metadata[Diff].(*DiffInfo).PercentErr)
+	assert.Equal(2.0, trafficMap[b.ID].Metadata[Diff].(*DiffInfo).Rates[httpIn5xx])
+
+	edges := trafficMap[a.ID].Edges
+	assert.Equal(3, len(edges))
+	for _, e := range edges {
+		diff := e.Metadata[Diff].(*DiffInfo)
+		switch e.Dest.ID {
+		case b.ID:
+			assert.Equal(DiffChanged, diff.Status)
+			assert.Equal(0.0, diff.Rates[http])
+			assert.Equal(2.0, diff.Rates[http5xx])
+			assert.Equal(map[string]float64{"http": 20.0}, diff.PercentErr)
+			assert.Equal(15.0, diff.ResponseTime)
+		case baseC.ID:
+			assert.Equal(DiffRemoved, diff.Status)
+			assert.Equal(-100.0, diff.Rates[tcp])
+			assert.Equal(trafficMap[baseC.ID], e.Dest)
+		case d.ID:
+			assert.Equal(DiffAdded, diff.Status)
+			assert.Equal(5.0, diff.Rates[http])
+		default:
+			assert.Fail("unexpected edge", e.Dest.ID)
+		}
+	}
+}
+
+func TestDiffTrafficMapsUnchanged(t *testing.T) {
+	assert := assert.New(t)
+
+	newTrafficMap := func() TrafficMap {
+		trafficMap := NewTrafficMap()
+		a := NewNode("east", "bookinfo", "", "bookinfo", "a-v1", "a", "v1", GraphTypeWorkload)
+		b := NewNode("east", "bookinfo", "", "bookinfo", "b-v1", "b", "v1", GraphTypeWorkload)
+		trafficMap[a.ID] = &a
+		trafficMap[b.ID] = &b
+		e := a.AddEdge(&b)
+		e.Metadata[ProtocolKey] = "grpc"
+		AddToMetadata("grpc", 3.0, "0", "-", "b", a.Metadata, b.Metadata, e.Metadata)
+		return trafficMap
+	}
+
+	trafficMap := DiffTrafficMaps(newTrafficMap(), newTrafficMap())
+
+	for _, n := range trafficMap {
+		assert.Equal(DiffUnchanged, n.Metadata[Diff].(*DiffInfo).Status)
+		for _, e := range n.Edges {
+			assert.Equal(DiffUnchanged, e.Metadata[Diff].(*DiffInfo).Status)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxDroppedTelemetryExamples is the number of label sets reported for each namespace and reason
+const maxDroppedTelemetryExamples = 3
+
+// DroppedTelemetry reports the telemetry samples discarded by the graph generation, for a namespace and reason,
+// because they are missing labels or hold bad label values (see telemetry/istio/util.BadSourceTelemetryReason).
+// The traffic of a dropped sample is missing from the graph.
+type DroppedTelemetry struct {
+	Count     int                 `json:"count"`    // the number of distinct label sets of the dropped samples
+	Examples  []map[string]string `json:"examples"` // distinct label sets of the dropped samples
+	Namespace string              `json:"namespace"`
+	Reason    string              `json:"reason"`
+
+	examples map[string]map[string]string // the examples by labelsKey, limited to the lowest keys for a stable report
+	seen     map[string]bool              // the labelsKey of every dropped sample, the same series is queried more than once
+}
+
+// DroppedTelemetryReport collects the DroppedTelemetry of a graph, keyed by namespace and reason
+type DroppedTelemetryReport map[string]*DroppedTelemetry
+
+func NewDroppedTelemetryReport() DroppedTelemetryReport {
+	return make(map[string]*DroppedTelemetry)
+}
+
+// Drop records a sample dropped from the namespace telemetry for the reason
+func (in DroppedTelemetryReport) Drop(namespace, reason string, labels map[string]string) {
+	key := namespace + " " + reason
+	dropped, ok := in[key]
+	if !ok {
+		dropped = &DroppedTelemetry{Namespace: namespace, Reason: reason, examples: make(map[string]map[string]string), seen: make(map[string]bool)}
+		in[key] = dropped
+	}
+
+	lk := labelsKey(labels)
+	if dropped.seen[lk] {
+		return
+	}
+	dropped.seen[lk] = true
+	dropped.Count++
+
+	dropped.examples[lk] = labels
+	if len(dropped.examples) > maxDroppedTelemetryExamples {
+		highest := ""
+		for k := range dropped.examples {
+			if k > highest {
+				highest = k
+			}
+		}
+		delete(dropped.examples, highest)
+	}
+}
+
+// List returns the DroppedTelemetry sorted by namespace and reason, or nil if no sample was dropped
+func (in DroppedTelemetryReport) List() []DroppedTelemetry {
+	if len(in) == 0 {
+		return nil
+	}
+
+	list := make([]DroppedTelemetry, 0, len(in))
+	for _, dropped := range in {
+		keys := make([]string, 0, len(dropped.examples))
+		for k := range dropped.examples {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		d := *dropped
+		d.examples = nil
+		d.seen = nil
+		d.Examples = make([]map[string]string, 0, len(keys))
+		for _, k := range keys {
+			d.Examples = append(d.Examples, dropped.examples[k])
+		}
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Namespace != list[j].Namespace {
+			return list[i].Namespace < list[j].Namespace
+		}
+		return list[i].Reason < list[j].Reason
+	})
+	return list
+}
+
+// labelsKey returns the labels as a string, sorted by label name
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDroppedTelemetryReport(t *testing.T) {
+	assert := assert.New(t)
+
+	report := NewDroppedTelemetryReport()
+	assert.Nil(report.List())
+
+	report.Drop("tutorial", "badSourceWorkload", map[string]string{"source_workload": "unknown"})
+	for _, svc := range []string{"10.0.0.5", "10.0.0.4", "10.0.0.3", "10.0.0.2", "10.0.0.3"} {
+		report.Drop("bookinfo", "badDestService", map[string]string{"destination_service": svc})
+	}
+
+	assert.Equal([]DroppedTelemetry{
+		{
+			Count: 4,
+			// the samples are counted once per label set, the lowest label sets are reported
+			Examples: []map[string]string{
+				{"destination_service": "10.0.0.2"},
+				{"destination_service": "10.0.0.3"},
+				{"destination_service": "10.0.0.4"},
+			},
+			Namespace: "bookinfo",
+			Reason:    "badDestService",
+		},
+		{
+			Count:     1,
+			Examples:  []map[string]string{{"source_workload": "unknown"}},
+			Namespace: "tutorial",
+			Reason:    "badSourceWorkload",
+		},
+	}, report.List())
+}
@@ -0,0 +1,418 @@
+package graph
+
+// Find.go evaluates the find and hide expressions of a graph request. The expression language is the one
+// used by the Kiali UI Graph Find/Hide (see KialiFeatureFlags.UIDefaults.Graph), e.g.:
+//
+//   rt > 1000
+//   ! healthy
+//   name = unknown
+//   namespace = bookinfo and httpin > 10
+//
+// An expression is one or more criteria, combined with 'and' (&&) and 'or' (||), where 'and' takes
+// precedence. Each criterion is either a unary "[!]<keyword>" or a binary "<field> <operator> <value>".
+// All criteria of an expression must apply to the same element kind, either nodes or edges.
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	findAndRegexp    = regexp.MustCompile(`(?i)\s+and\s+|\s*&&\s*`)
+	findBinaryRegexp = regexp.MustCompile(`^(%?[a-zA-Z]+)\s*(!\*=|\*=|!\^=|\^=|!\$=|\$=|!=|>=|<=|=|>|<)\s*(.+)$`)
+	findOrRegexp     = regexp.MustCompile(`(?i)\s+or\s+|\s*\|\|\s*`)
+	findUnaryRegexp  = regexp.MustCompile(`^(!?)\s*([a-zA-Z]+)$`)
+)
+
+// findAliases maps the supported abbreviations to the field or keyword name
+var findAliases = map[string]string{
+	"circuitbreaker":     "cb",
+	"faultinjection":     "fi",
+	"failure":            "unhealthy",
+	"ns":                 "namespace",
+	"outsider":           "outside",
+	"requestrouting":     "rr",
+	"requesttimeout":     "rto",
+	"responsetime":       "rt",
+	"serviceentry":       "se",
+	"sidecar":            "sc",
+	"svc":                "service",
+	"tcptrafficshifting": "tcpts",
+	"throughput":         "tp",
+	"trafficshifting":    "ts",
+	"virtualservice":     "vs",
+	"wl":                 "workload",
+}
+
+var findNodeNumericFields = map[string]func(n *Node) float64{
+	"grpcin":  func(n *Node) float64 { return metadataValue(n.Metadata, grpcIn) },
+	"grpcout": func(n *Node) float64 { return metadataValue(n.Metadata, grpcOut) },
+	"httpin":  func(n *Node) float64 { return metadataValue(n.Metadata, httpIn) },
+	"httpout": func(n *Node) float64 { return metadataValue(n.Metadata, httpOut) },
+	"tcpin":   func(n *Node) float64 { return metadataValue(n.Metadata, tcpIn) },
+	"tcpout":  func(n *Node) float64 { return metadataValue(n.Metadata, tcpOut) },
+}
+
+var findNodeStringFields = map[string]func(n *Node) []string{
+	"app":       func(n *Node) []string { return []string{n.App} },
+	"cluster":   func(n *Node) []string { return []string{n.Cluster} },
+	"name":      nodeNames,
+	"namespace": func(n *Node) []string { return []string{n.Namespace} },
+	"node":      func(n *Node) []string { return []string{n.NodeType} },
+	"service":   func(n *Node) []string { return []string{n.Service} },
+	"version":   func(n *Node) []string { return []string{n.Version} },
+	"workload":  func(n *Node) []string { return []string{n.Workload} },
+}
+
+var findNodeKeywords = map[string]func(n *Node) bool{
+	"anomaly": func(n *Node) bool { return isTrue(n.Metadata, IsAnomaly) },
+	"cb":      func(n *Node) bool { return isTrue(n.Metadata, HasCB) },
+	"dead":    func(n *Node) bool { return isTrue(n.Metadata, IsDead) },
+	"fi":      func(n *Node) bool { return isTrue(n.Metadata, HasFaultInjection) },
+	"idle":    func(n *Node) bool { return isTrue(n.Metadata, IsIdle) },
+	"outside": func(n *Node) bool { return isTrue(n.Metadata, IsOutside) },
+	"root":    func(n *Node) bool { return isTrue(n.Metadata, IsRoot) },
+	"rr":      func(n *Node) bool { return isTrue(n.Metadata, HasRequestRouting) },
+	"rto":     func(n *Node) bool { return isTrue(n.Metadata, HasRequestTimeout) },
+	"sc":      func(n *Node) bool { return isTrue(n.Metadata, HasMissingSC) },
+	"se":      func(n *Node) bool { return n.Metadata[IsServiceEntry] != nil },
+	"tcpts":   func(n *Node) bool { return isTrue(n.Metadata, HasTCPTrafficShifting) },
+	"ts":      func(n *Node) bool { return isTrue(n.Metadata, HasTrafficShifting) },
+	"vs":      func(n *Node) bool { return isTrue(n.Metadata, HasVS) },
+}
+
+var findEdgeNumericFields = map[string]func(e *Edge) float64{
+	"%grpcerr": func(e *Edge) float64 { return percentErr(e.Metadata, GRPC.EdgeRates, isTotalRate) },
+	"%httperr": func(e *Edge) float64 { return percentErr(e.Metadata, HTTP.EdgeRates, isTotalRate) },
+	"grpc":     func(e *Edge) float64 { return metadataValue(e.Metadata, grpc) },
+	"http":     func(e *Edge) float64 { return metadataValue(e.Metadata, http) },
+	"mtls":     func(e *Edge) float64 { return metadataValue(e.Metadata, IsMTLS) },
+	"rt":       func(e *Edge) float64 { return metadataValue(e.Metadata, ResponseTime) },
+	"tcp":      func(e *Edge) float64 { return metadataValue(e.Metadata, tcp) },
+	"tp":       func(e *Edge) float64 { return metadataValue(e.Metadata, Throughput) },
+}
+
+var findEdgeStringFields = map[string]func(e *Edge) []string{
+	"destprincipal":   func(e *Edge) []string { return []string{metadataString(e.Metadata, DestPrincipal)} },
+	"protocol":        func(e *Edge) []string { return []string{metadataString(e.Metadata, ProtocolKey)} },
+	"sourceprincipal": func(e *Edge) []string { return []string{metadataString(e.Metadata, SourcePrincipal)} },
+}
+
+var findEdgeKeywords = map[string]func(e *Edge) bool{
+	"mtls": func(e *Edge) bool { return metadataValue(e.Metadata, IsMTLS) > 0.0 },
+	"traffic": func(e *Edge) bool {
+		for _, p := range Protocols {
+			if metadataValue(e.Metadata, MetadataKey(p.Name)) > 0.0 {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+// The node health values
+const (
+	findDegraded  = "degraded"
+	findHealthy   = "healthy"
+	findUnhealthy = "unhealthy"
+)
+
+// findHealthKeywords maps the node health keywords to their health value, see nodeHealth
+var findHealthKeywords = map[string]string{
+	"degraded":  findDegraded,
+	"healthy":   findHealthy,
+	"unhealthy": findUnhealthy,
+}
+
+// FindExpression is a parsed find or hide expression
+type FindExpression struct {
+	Expression string
+	IsEdge     bool           // true if the expression applies to edges, otherwise it applies to nodes
+	criteria   [][]*criterion // disjunction of conjunctions
+}
+
+type criterion struct {
+	field    string
+	negate   bool // unary only
+	operator string
+	value    string
+	numValue float64
+}
+
+// ParseFindExpression parses a find or hide expression, it returns nil for an empty expression
+func ParseFindExpression(expression string) (*FindExpression, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, nil
+	}
+
+	fe := &FindExpression{Expression: expression}
+	isNode := false
+	for _, disjunct := range findOrRegexp.Split(expression, -1) {
+		var conjunction []*criterion
+		for _, conjunct := range findAndRegexp.Split(disjunct, -1) {
+			c, isEdge, err := parseCriterion(strings.TrimSpace(conjunct))
+			if err != nil {
+				return nil, err
+			}
+			if isEdge {
+				fe.IsEdge = true
+			} else {
+				isNode = true
+			}
+			if fe.IsEdge && isNode {
+				return nil, fmt.Errorf("node and edge criteria can not be combined in [%s]", expression)
+			}
+			conjunction = append(conjunction, c)
+		}
+		fe.criteria = append(fe.criteria, conjunction)
+	}
+
+	return fe, nil
+}
+
+func parseCriterion(s string) (c *criterion, isEdge bool, err error) {
+	if match := findUnaryRegexp.FindStringSubmatch(s); match != nil {
+		c = &criterion{field: findAlias(match[2]), negate: match[1] == "!"}
+		_, isNodeKeyword := findNodeKeywords[c.field]
+		_, isHealthKeyword := findHealthKeywords[c.field]
+		isNodeKeyword = isNodeKeyword || isHealthKeyword
+		_, isEdgeKeyword := findEdgeKeywords[c.field]
+		if !isNodeKeyword && !isEdgeKeyword {
+			return nil, false, fmt.Errorf("unsupported keyword [%s]", match[2])
+		}
+		return c, !isNodeKeyword, nil
+	}
+
+	match := findBinaryRegexp.FindStringSubmatch(s)
+	if match == nil {
+		return nil, false, fmt.Errorf("invalid criterion [%s]", s)
+	}
+	c = &criterion{
+		field:    findAlias(match[1]),
+		operator: match[2],
+		value:    strings.ToLower(strings.Trim(strings.TrimSpace(match[3]), `"'`)),
+	}
+
+	_, isNodeNumeric := findNodeNumericFields[c.field]
+	_, isEdgeNumeric := findEdgeNumericFields[c.field]
+	_, isNodeString := findNodeStringFields[c.field]
+	_, isEdgeString := findEdgeStringFields[c.field]
+
+	switch {
+	case isNodeNumeric || isEdgeNumeric:
+		switch c.operator {
+		case "=", "!=", ">", ">=", "<", "<=":
+		default:
+			return nil, false, fmt.Errorf("unsupported operator [%s] for numeric field [%s]", c.operator, match[1])
+		}
+		if c.numValue, err = strconv.ParseFloat(c.value, 64); err != nil {
+			return nil, false, fmt.Errorf("invalid numeric value [%s] for field [%s]", c.value, match[1])
+		}
+		return c, isEdgeNumeric, nil
+	case isNodeString || isEdgeString:
+		switch c.operator {
+		case ">", ">=", "<", "<=":
+			return nil, false, fmt.Errorf("unsupported operator [%s] for field [%s]", c.operator, match[1])
+		}
+		return c, isEdgeString, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported field [%s]", match[1])
+	}
+}
+
+func findAlias(name string) string {
+	name = strings.ToLower(name)
+	if alias, ok := findAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// MatchNode returns true if the node satisfies the expression, always false for an edge expression. The inbound
+// edges of the node are needed to evaluate its health.
+func (fe *FindExpression) MatchNode(n *Node, inbound []*Edge) bool {
+	if fe.IsEdge {
+		return false
+	}
+	return fe.match(func(c *criterion) bool {
+		if health, ok := findHealthKeywords[c.field]; ok {
+			return (nodeHealth(n, inbound) == health) != c.negate
+		}
+		if f, ok := findNodeKeywords[c.field]; ok && c.operator == "" {
+			return f(n) != c.negate
+		}
+		if f, ok := findNodeNumericFields[c.field]; ok {
+			return c.matchNumeric(f(n))
+		}
+		return c.matchStrings(findNodeStringFields[c.field](n))
+	})
+}
+
+// MatchEdge returns true if the edge satisfies the expression, always false for a node expression
+func (fe *FindExpression) MatchEdge(e *Edge) bool {
+	if !fe.IsEdge {
+		return false
+	}
+	return fe.match(func(c *criterion) bool {
+		if f, ok := findEdgeKeywords[c.field]; ok && c.operator == "" {
+			return f(e) != c.negate
+		}
+		if f, ok := findEdgeNumericFields[c.field]; ok {
+			return c.matchNumeric(f(e))
+		}
+		return c.matchStrings(findEdgeStringFields[c.field](e))
+	})
+}
+
+func (fe *FindExpression) match(matchCriterion func(c *criterion) bool) bool {
+	for _, conjunction := range fe.criteria {
+		matches := true
+		for _, c := range conjunction {
+			if !matchCriterion(c) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *criterion) matchNumeric(val float64) bool {
+	switch c.operator {
+	case "=":
+		return val == c.numValue
+	case "!=":
+		return val != c.numValue
+	case ">":
+		return val > c.numValue
+	case ">=":
+		return val >= c.numValue
+	case "<":
+		return val < c.numValue
+	case "<=":
+		return val <= c.numValue
+	}
+	return false
+}
+
+// matchStrings returns true if any of the values satisfies the criterion. For a negated operator it
+// returns true only if every value satisfies the criterion.
+func (c *criterion) matchStrings(vals []string) bool {
+	negate := strings.HasPrefix(c.operator, "!")
+	for _, val := range vals {
+		val = strings.ToLower(val)
+		var matches bool
+		switch strings.TrimPrefix(c.operator, "!") {
+		case "=":
+			matches = val == c.value
+		case "*=":
+			matches = strings.Contains(val, c.value)
+		case "^=":
+			matches = strings.HasPrefix(val, c.value)
+		case "$=":
+			matches = strings.HasSuffix(val, c.value)
+		}
+		if matches {
+			return !negate
+		}
+	}
+	return negate
+}
+
+// FindAndHide removes the nodes and edges matching the hide expression and then marks the remaining nodes
+// and edges matching the find expression. Hiding nodes also hides their edges, and any node left without
+// edges by the hide is also removed. Either expression may be nil.
+func FindAndHide(trafficMap TrafficMap, find, hide *FindExpression) {
+	if hide != nil {
+		hideElements(trafficMap, hide)
+	}
+	if find == nil {
+		return
+	}
+	inbound := inboundEdges(trafficMap)
+	for _, n := range trafficMap {
+		if find.MatchNode(n, inbound[n.ID]) {
+			n.Metadata[IsFound] = true
+		}
+		for _, e := range n.Edges {
+			if find.MatchEdge(e) {
+				e.Metadata[IsFound] = true
+			}
+		}
+	}
+}
+
+func hideElements(trafficMap TrafficMap, hide *FindExpression) {
+	connected := connectedNodes(trafficMap)
+	inbound := inboundEdges(trafficMap)
+
+	for id, n := range trafficMap {
+		if hide.MatchNode(n, inbound[id]) {
+			delete(trafficMap, id)
+		}
+	}
+	for _, n := range trafficMap {
+		edges := []*Edge{}
+		for _, e := range n.Edges {
+			if _, ok := trafficMap[e.Dest.ID]; ok && !hide.MatchEdge(e) {
+				edges = append(edges, e)
+			}
+		}
+		n.Edges = edges
+	}
+
+	stillConnected := connectedNodes(trafficMap)
+	for id := range trafficMap {
+		if connected[id] && !stillConnected[id] {
+			delete(trafficMap, id)
+		}
+	}
+}
+
+// connectedNodes returns the IDs of the nodes with at least one incoming or outgoing edge
+func connectedNodes(trafficMap TrafficMap) map[string]bool {
+	connected := make(map[string]bool)
+	for id, n := range trafficMap {
+		for _, e := range n.Edges {
+			connected[id] = true
+			connected[e.Dest.ID] = true
+		}
+	}
+	return connected
+}
+
+// inboundEdges returns the inbound edges of the nodes, by node ID
+func inboundEdges(trafficMap TrafficMap) map[string][]*Edge {
+	inbound := make(map[string][]*Edge)
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			inbound[e.Dest.ID] = append(inbound[e.Dest.ID], e)
+		}
+	}
+	return inbound
+}
+
+// nodeNames returns the names by which the node may be found
+func nodeNames(n *Node) []string {
+	return []string{n.App, n.Service, n.Workload, metadataString(n.Metadata, AggregateValue)}
+}
+
+func isTotalRate(r Rate) bool {
+	return r.IsTotal
+}
+
+func isTrue(md Metadata, k MetadataKey) bool {
+	val, ok := md[k].(bool)
+	return ok && val
+}
+
+func metadataString(md Metadata, k MetadataKey) string {
+	val, _ := md[k].(string)
+	return val
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestParseFindExpression(t *testing.T) {
+	assert := assert.New(t)
+
+	fe, err := ParseFindExpression("  ")
+	assert.Nil(fe)
+	assert.Nil(err)
+
+	fe, err = ParseFindExpression("rt > 1000")
+	assert.Nil(err)
+	assert.True(fe.IsEdge)
+
+	fe, err = ParseFindExpression("! healthy")
+	assert.Nil(err)
+	assert.False(fe.IsEdge)
+
+	fe, err = ParseFindExpression("ns = bookinfo AND httpin > 10 || name *= \"unknown\"")
+	assert.Nil(err)
+	assert.False(fe.IsEdge)
+	assert.Equal(2, len(fe.criteria))
+	assert.Equal(2, len(fe.criteria[0]))
+	assert.Equal("namespace", fe.criteria[0][0].field)
+
+	for _, invalid := range []string{"foo", "foo = bar", "rt > slow", "rt *= 10", "name > 10", "rt > 1000 and name = details", "= 10"} {
+		fe, err = ParseFindExpression(invalid)
+		assert.Nil(fe, invalid)
+		assert.NotNil(err, invalid)
+	}
+}
+
+func TestFindAndHide(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// productpage -> reviews -> ratings, productpage -> details, unknown -> productpage
+	trafficMap := NewTrafficMap()
+	unknown := NewNode(Unknown, Unknown, "", Unknown, Unknown, Unknown, Unknown, GraphTypeVersionedApp)
+	productpage := NewNode(Unknown, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", GraphTypeVersionedApp)
+	reviews := NewNode(Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", GraphTypeVersionedApp)
+	ratings := NewNode(Unknown, "bookinfo", "ratings", "bookinfo", "ratings-v1", "ratings", "v1", GraphTypeVersionedApp)
+	details := NewNode(Unknown, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", GraphTypeVersionedApp)
+	trafficMap[unknown.ID] = &unknown
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[ratings.ID] = &ratings
+	trafficMap[details.ID] = &details
+
+	e := unknown.AddEdge(&productpage)
+	e.Metadata[ProtocolKey] = "http"
+	AddToMetadata("http", 10.0, "200", "-", "productpage", unknown.Metadata, productpage.Metadata, e.Metadata)
+	e = productpage.AddEdge(&reviews)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 1500.0
+	AddToMetadata("http", 8.0, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	AddToMetadata("http", 2.0, "500", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e = reviews.AddEdge(&ratings)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 500.0
+	AddToMetadata("http", 10.0, "200", "-", "ratings", reviews.Metadata, ratings.Metadata, e.Metadata)
+	e = productpage.AddEdge(&details)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 10.0
+	AddToMetadata("http", 9.5, "200", "-", "details", productpage.Metadata, details.Metadata, e.Metadata)
+	AddToMetadata("http", 0.5, "503", "-", "details", productpage.Metadata, details.Metadata, e.Metadata)
+
+	inbound := inboundEdges(trafficMap)
+	find, _ := ParseFindExpression("! healthy")
+	assert.True(find.MatchNode(&reviews, inbound[reviews.ID]))
+	assert.True(find.MatchNode(&details, inbound[details.ID]))
+	assert.False(find.MatchNode(&ratings, inbound[ratings.ID]))
+	assert.False(find.MatchNode(&unknown, inbound[unknown.ID]))
+	assert.False(find.MatchEdge(e))
+
+	find, _ = ParseFindExpression("unhealthy")
+	assert.True(find.MatchNode(&reviews, inbound[reviews.ID]))
+	assert.False(find.MatchNode(&details, inbound[details.ID]))
+
+	find, _ = ParseFindExpression("name != details")
+	assert.True(find.MatchNode(&reviews, inbound[reviews.ID]))
+	assert.False(find.MatchNode(&details, inbound[details.ID]))
+
+	find, _ = ParseFindExpression("%httperr >= 5 and rt < 100")
+	assert.True(find.MatchEdge(e))
+	assert.False(find.MatchNode(&details, inbound[details.ID]))
+
+	// hiding productpage also hides unknown and details, left without edges
+	find, _ = ParseFindExpression("rt > 1000")
+	hide, _ := ParseFindExpression("name = productpage")
+	FindAndHide(trafficMap, find, hide)
+	assert.Equal(2, len(trafficMap))
+	assert.NotNil(trafficMap[reviews.ID])
+	assert.NotNil(trafficMap[ratings.ID])
+	assert.Nil(reviews.Metadata[IsFound])
+	assert.Nil(reviews.Edges[0].Metadata[IsFound])
+
+	find, _ = ParseFindExpression("rt >= 500")
+	FindAndHide(trafficMap, find, nil)
+	assert.Equal(true, reviews.Edges[0].Metadata[IsFound])
+
+	// hiding the only edge leaves no nodes
+	hide, _ = ParseFindExpression("protocol = http")
+	FindAndHide(trafficMap, nil, hide)
+	assert.Equal(0, len(trafficMap))
+}
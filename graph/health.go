@@ -0,0 +1,148 @@
+package graph
+
+// Health.go evaluates the node health used by the find and hide 'healthy', 'degraded' and 'unhealthy' keywords.
+// Like the Kiali UI, the request error rates of a node are checked against the tolerances of the first
+// config.HealthConfig rate matching the node namespace, kind and name. The defaults are, for any direction:
+//
+//   http 4XX: degraded >= 10%, failure >= 20%
+//   http 5XX: degraded > 0%, failure >= 10%
+//   grpc errors: degraded > 0%, failure >= 10%
+//   no response: degraded > 0%, failure >= 10%
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+)
+
+// The traffic directions of a tolerance
+const (
+	healthInbound  = "inbound"
+	healthOutbound = "outbound"
+)
+
+// healthRank orders the node health values, from best to worst
+var healthRank = map[string]int{
+	findHealthy:   0,
+	findDegraded:  1,
+	findUnhealthy: 2,
+}
+
+// healthResponses maps the protocols with response codes to their edge responses
+var healthResponses = map[string]MetadataKey{
+	grpc: grpcResponses,
+	http: httpResponses,
+}
+
+// nodeHealth returns the node health, determined by the error rates of its inbound and outbound edges. A service
+// node is evaluated only on its inbound requests, like the service health. A dead node is unhealthy.
+func nodeHealth(n *Node, inbound []*Edge) string {
+	if isTrue(n.Metadata, IsDead) {
+		return findUnhealthy
+	}
+	rate := healthRate(n)
+	if rate == nil {
+		return findHealthy
+	}
+
+	edges := map[string][]*Edge{healthInbound: inbound}
+	if n.NodeType != NodeTypeService {
+		edges[healthOutbound] = n.Edges
+	}
+	health := findHealthy
+	for _, tolerance := range rate.Tolerance {
+		for direction, directionEdges := range edges {
+			if !healthMatch(tolerance.Direction, direction) {
+				continue
+			}
+			for protocol := range healthResponses {
+				if !healthMatch(tolerance.Protocol, protocol) {
+					continue
+				}
+				errPercent := healthErrorPercent(directionEdges, protocol, tolerance.Code)
+				if toleranceHealth := healthOf(errPercent, tolerance); healthRank[toleranceHealth] > healthRank[health] {
+					health = toleranceHealth
+				}
+			}
+		}
+	}
+	return health
+}
+
+// healthRate returns the first health rate config matching the node, nil if none
+func healthRate(n *Node) *config.Rate {
+	var kind, name string
+	switch n.NodeType {
+	case NodeTypeApp:
+		kind, name = "app", n.App
+	case NodeTypeService:
+		kind, name = "service", n.Service
+	case NodeTypeWorkload:
+		kind, name = "workload", n.Workload
+	default:
+		kind = n.NodeType
+	}
+
+	rates := config.Get().HealthConfig.Rate
+	for i := range rates {
+		if healthMatch(rates[i].Namespace, n.Namespace) && healthMatch(rates[i].Kind, kind) && healthMatch(rates[i].Name, name) {
+			return &rates[i]
+		}
+	}
+	return nil
+}
+
+// healthErrorPercent returns the percentage of the protocol requests of the edges with a response code matching
+// the tolerance code. An 'X' of the code matches any digit, e.g. 5XX.
+func healthErrorPercent(edges []*Edge, protocol, code string) float64 {
+	codeRegexp, err := regexp.Compile(strings.NewReplacer("X", `\d`, "x", `\d`).Replace(code))
+	if err != nil {
+		return 0.0
+	}
+
+	total := 0.0
+	errs := 0.0
+	for _, e := range edges {
+		responses, ok := e.Metadata[healthResponses[protocol]].(Responses)
+		if !ok || metadataString(e.Metadata, ProtocolKey) != protocol {
+			continue
+		}
+		for responseCode, detail := range responses {
+			for _, val := range detail.Flags {
+				total += val
+				if codeRegexp.MatchString(responseCode) {
+					errs += val
+				}
+			}
+		}
+	}
+	if total == 0.0 {
+		return 0.0
+	}
+	return errs / total * 100.0
+}
+
+// healthOf returns the health for the error percentage, given the tolerance thresholds
+func healthOf(errPercent float64, tolerance config.Tolerance) string {
+	switch {
+	case errPercent <= 0.0:
+		return findHealthy
+	case errPercent >= float64(tolerance.Failure):
+		return findUnhealthy
+	case errPercent >= float64(tolerance.Degraded):
+		return findDegraded
+	default:
+		return findHealthy
+	}
+}
+
+// healthMatch returns true if the value matches the health config expression, an empty expression matches any
+// value and an invalid one matches none
+func healthMatch(expr, val string) bool {
+	if expr == "" {
+		return true
+	}
+	matched, err := regexp.MatchString(expr, val)
+	return err == nil && matched
+}
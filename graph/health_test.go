@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestNodeHealth(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// unknown -> productpage svc -> productpage app -> reviews app
+	productpageSvc := NewNode(Unknown, "bookinfo", "productpage", "", "", "", "", GraphTypeVersionedApp)
+	productpage := NewNode(Unknown, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", GraphTypeVersionedApp)
+	reviews := NewNode(Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", GraphTypeVersionedApp)
+	unknown := NewNode(Unknown, Unknown, "", Unknown, Unknown, Unknown, Unknown, GraphTypeVersionedApp)
+
+	in := unknown.AddEdge(&productpageSvc)
+	in.Metadata[ProtocolKey] = "http"
+	AddToMetadata("http", 85.0, "200", "-", "productpage", unknown.Metadata, productpageSvc.Metadata, in.Metadata)
+	AddToMetadata("http", 15.0, "404", "-", "productpage", unknown.Metadata, productpageSvc.Metadata, in.Metadata)
+	svcOut := productpageSvc.AddEdge(&productpage)
+	svcOut.Metadata[ProtocolKey] = "http"
+	AddToMetadata("http", 100.0, "200", "-", "productpage", productpageSvc.Metadata, productpage.Metadata, svcOut.Metadata)
+	AddToMetadata("http", 20.0, "503", "-", "productpage", productpageSvc.Metadata, productpage.Metadata, svcOut.Metadata)
+	out := productpage.AddEdge(&reviews)
+	out.Metadata[ProtocolKey] = "grpc"
+	AddToMetadata("grpc", 99.0, "0", "-", "reviews", productpage.Metadata, reviews.Metadata, out.Metadata)
+	AddToMetadata("grpc", 1.0, "14", "-", "reviews", productpage.Metadata, reviews.Metadata, out.Metadata)
+
+	// 15% of 4XX is degraded, a service is evaluated only on its inbound requests
+	assert.Equal(findDegraded, nodeHealth(&productpageSvc, []*Edge{in}))
+	// 16.7% of 5XX is unhealthy
+	assert.Equal(findUnhealthy, nodeHealth(&productpage, []*Edge{svcOut}))
+	// any gRPC error is at least degraded
+	assert.Equal(findDegraded, nodeHealth(&reviews, []*Edge{out}))
+	assert.Equal(findHealthy, nodeHealth(&reviews, nil))
+
+	// a namespace, kind and name override takes precedence over the defaults
+	conf := config.NewConfig()
+	conf.HealthConfig.Rate = []config.Rate{
+		{
+			Namespace: "bookinfo",
+			Kind:      "app",
+			Name:      "reviews",
+			Tolerance: []config.Tolerance{{Code: "14", Protocol: "grpc", Direction: "inbound", Failure: 1}},
+		},
+		{
+			Namespace: "bookinfo",
+			Kind:      "service",
+			Tolerance: []config.Tolerance{{Code: "4XX", Protocol: "http", Direction: "inbound", Degraded: 20, Failure: 30}},
+		},
+	}
+	config.Set(conf)
+	assert.Equal(findUnhealthy, nodeHealth(&reviews, []*Edge{out}))
+	assert.Equal(findHealthy, nodeHealth(&productpageSvc, []*Edge{in}))
+	assert.Equal(findUnhealthy, nodeHealth(&productpage, []*Edge{svcOut}))
+
+	reviews.Metadata[IsDead] = true
+	assert.Equal(findUnhealthy, nodeHealth(&reviews, nil))
+}
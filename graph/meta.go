@@ -15,8 +15,16 @@ func NewMetadata() Metadata {
 const (
 	Aggregate             MetadataKey = "aggregate" // the prom attribute used for aggregation
 	AggregateValue        MetadataKey = "aggregateValue"
+	Anomalies             MetadataKey = "anomalies"     // []*Anomaly, set only by the anomaly appender
+	Authorization         MetadataKey = "authorization" // *AuthorizationInfo, set only by the authorizationPolicy appender
+	CBEjections           MetadataKey = "cbEjections"   // float64, set only by the circuitBreakerState appender
+	CBOverflow            MetadataKey = "cbOverflow"    // float64, set only by the circuitBreakerState appender
+	CustomMetrics         MetadataKey = "customMetrics" // map[string]float64 (metric key => value), set only by the customMetrics appender
 	DestPrincipal         MetadataKey = "destPrincipal"
 	DestServices          MetadataKey = "destServices"
+	Diff                  MetadataKey = "diff"           // *DiffInfo, set only for graph diffs
+	FlagBadges            MetadataKey = "flagBadges"     // []string, set only by the responseFlags appender
+	FlagCategories        MetadataKey = "flagCategories" // map[string]float64, set only by the responseFlags appender
 	HasCB                 MetadataKey = "hasCB"
 	HasFaultInjection     MetadataKey = "hasFaultInjection"
 	HasHealthConfig       MetadataKey = "hasHealthConfig"
@@ -26,20 +34,79 @@ const (
 	HasRequestRouting     MetadataKey = "hasRequestRouting"
 	HasRequestTimeout     MetadataKey = "hasRequestTimeout"
 	HasVS                 MetadataKey = "hasVS"
+	IsAnomaly             MetadataKey = "isAnomaly"
+	IsCBTripped           MetadataKey = "isCBTripped" // set only by the circuitBreakerState appender
 	IsDead                MetadataKey = "isDead"
 	IsEgressCluster       MetadataKey = "isEgressCluster" // PassthroughCluster or BlackHoleCluster
+	IsFound               MetadataKey = "isFound"         // set only for elements matching the find expression
 	IsIdle                MetadataKey = "isIdle"
 	IsInaccessible        MetadataKey = "isInaccessible"
 	IsMTLS                MetadataKey = "isMTLS"
 	IsOutside             MetadataKey = "isOutside"
 	IsRoot                MetadataKey = "isRoot"
 	IsServiceEntry        MetadataKey = "isServiceEntry"
+	Labels                MetadataKey = "labels" // map[string]string, set only for the requested boxBy labels
 	ProtocolKey           MetadataKey = "protocol"
 	ResponseTime          MetadataKey = "responseTime"
 	SourcePrincipal       MetadataKey = "sourcePrincipal"
+	Sparklines            MetadataKey = "sparklines" // *SparklinesInfo, set only by the sparklines appender
 	Throughput            MetadataKey = "throughput"
 )
 
+// The possible Anomaly.Metric values
+const (
+	AnomalyErrorRate    string = "errorRate"    // error percentage
+	AnomalyRequestRate  string = "requestRate"  // requests per second
+	AnomalyResponseTime string = "responseTime" // response time, in millis
+)
+
+// The possible AuthorizationInfo.Decision values
+const (
+	AuthorizationAllow   string = "allow"   // explicitly allowed by an ALLOW policy
+	AuthorizationDefault string = "default" // allowed by default, no DENY policy matches and no ALLOW policy applies
+	AuthorizationDeny    string = "deny"    // explicitly denied by a DENY policy, or not allowed by any of the applying ALLOW policies
+	AuthorizationPartial string = "partial" // the matching policy rules depend on request attributes, only some requests may be allowed or denied
+)
+
+// The possible response flag categories, grouping the Envoy response flags
+const (
+	FlagCategoryCircuitBreaker    string = "circuitBreaker"
+	FlagCategoryClientDisconnect  string = "clientDisconnect"
+	FlagCategoryFaultInjected     string = "faultInjected"
+	FlagCategoryNoHealthyUpstream string = "noHealthyUpstream"
+	FlagCategoryNoRoute           string = "noRoute"
+	FlagCategoryRateLimited       string = "rateLimited"
+	FlagCategoryRetryLimit        string = "retryLimit"
+	FlagCategoryTimeout           string = "timeout"
+	FlagCategoryUnauthorized      string = "unauthorized"
+	FlagCategoryUpstreamFailure   string = "upstreamFailure"
+)
+
+// Anomaly describes an edge value deviating from its historical baseline
+type Anomaly struct {
+	Baseline float64 // the mean baseline value
+	Current  float64
+	Metric   string
+	Score    float64 // the deviation from the baseline mean, in standard deviations
+}
+
+// AuthorizationInfo describes the AuthorizationPolicy decision for the requests of an edge
+type AuthorizationInfo struct {
+	Decision string
+	Policies []string // the policies determining the decision, as namespace/name
+}
+
+// SparklinesInfo holds the time series of edge values over the requested duration. Each series has a value
+// for every step, from Start to End inclusive.
+type SparklinesInfo struct {
+	End          int64     // unix time in seconds
+	ErrorRate    []float64 // error percentage
+	RequestRate  []float64 // requests per second
+	ResponseTime []float64 // response time, in millis, 0 when there are no requests
+	Start        int64     // unix time in seconds
+	Step         int64     // in seconds
+}
+
 // DestServicesMetadata key=Service.Key()
 type DestServicesMetadata map[string]ServiceName
 
@@ -22,6 +22,8 @@ import (
 // The supported vendors
 const (
 	VendorCytoscape        string = "cytoscape"
+	VendorDot              string = "dot"
+	VendorGraphML          string = "graphml"
 	VendorIstio            string = "istio"
 	defaultConfigVendor    string = VendorCytoscape
 	defaultTelemetryVendor string = VendorIstio
@@ -30,14 +32,19 @@ const (
 const (
 	BoxByApp                  string = "app"
 	BoxByCluster              string = "cluster"
+	BoxByLabel                string = "label" // the IsBox value for boxes of a configured workload label
 	BoxByNamespace            string = "namespace"
 	BoxByNone                 string = "none"
 	NamespaceIstio            string = "istio-system"
+	defaultBaselineOffset     string = "1h"
 	defaultBoxBy              string = BoxByNone
 	defaultDuration           string = "10m"
 	defaultGraphType          string = GraphTypeWorkload
 	defaultIncludeIdleEdges   bool   = false
 	defaultInjectServiceNodes bool   = false
+	defaultPathResponseTime   string = "95"
+	minRefreshInterval        string = "5s"
+	pathAppenderName          string = "responseTime" // the appender providing path response times
 )
 
 const (
@@ -67,10 +74,22 @@ type CommonOptions struct {
 
 // ConfigOptions are those supplied to Config Vendors
 type ConfigOptions struct {
-	BoxBy string
+	BoxBy string          // the requested boxBy values, comma separated, see IsBoxBy and BoxByLabels
+	Find  *FindExpression // elements to mark, nil if not requested
+	Hide  *FindExpression // elements to remove, nil if not requested
 	CommonOptions
 }
 
+// IsBoxBy returns true if the box is one of the requested boxBy values
+func (o ConfigOptions) IsBoxBy(box string) bool {
+	for _, b := range boxByValues(o.BoxBy) {
+		if b == box {
+			return true
+		}
+	}
+	return false
+}
+
 type RequestedAppenders struct {
 	All           bool
 	AppenderNames []string
@@ -95,6 +114,41 @@ type Options struct {
 	TelemetryOptions
 }
 
+// DiffOptions comprises the options for both graphs of a graph diff. The baseline options
+// are the same as the current options, except for an earlier queryTime.
+type DiffOptions struct {
+	Baseline       Options
+	BaselineOffset time.Duration
+	Options
+}
+
+// PathOptions comprises the options for a path analysis, the paths are found from the Source node to
+// the Dest node, both identified by their node IDs in the generated graph.
+type PathOptions struct {
+	Dest   string
+	Source string
+	Options
+}
+
+// SnapshotOptions comprises the options for a saved graph snapshot, the Name optionally describes the snapshot
+type SnapshotOptions struct {
+	Name string
+	Options
+}
+
+// StreamOptions comprises the options for a graph stream, the graph is regenerated every RefreshInterval
+type StreamOptions struct {
+	RefreshInterval time.Duration
+	Options
+}
+
+// TraceOptions comprises the options for a trace graph, the graph of the requests traversed by the single
+// trace identified by TraceID
+type TraceOptions struct {
+	TraceID string
+	Options
+}
+
 func NewOptions(r *net_http.Request) Options {
 	// path variables (0 or more will be set)
 	vars := mux.Vars(r)
@@ -103,6 +157,7 @@ func NewOptions(r *net_http.Request) Options {
 	app := vars["app"]
 	namespace := vars["namespace"]
 	service := vars["service"]
+	traceID := vars["traceID"]
 	version := vars["version"]
 	workload := vars["workload"]
 
@@ -117,7 +172,9 @@ func NewOptions(r *net_http.Request) Options {
 	cluster := params.Get("cluster")
 	configVendor := params.Get("configVendor")
 	durationString := params.Get("duration")
+	findString := params.Get("find")
 	graphType := params.Get("graphType")
+	hideString := params.Get("hide")
 	includeIdleEdgesString := params.Get("includeIdleEdges")
 	injectServiceNodesString := params.Get("injectServiceNodes")
 	namespaces := params.Get("namespaces") // csl of namespaces
@@ -137,7 +194,7 @@ func NewOptions(r *net_http.Request) Options {
 	}
 	if configVendor == "" {
 		configVendor = defaultConfigVendor
-	} else if configVendor != VendorCytoscape {
+	} else if configVendor != VendorCytoscape && configVendor != VendorDot && configVendor != VendorGraphML {
 		BadRequest(fmt.Sprintf("Invalid configVendor [%s]", configVendor))
 	}
 	if durationString == "" {
@@ -160,9 +217,10 @@ func NewOptions(r *net_http.Request) Options {
 	}
 	if boxBy == "" {
 		boxBy = defaultBoxBy
-	} else {
-		for _, box := range strings.Split(boxBy, ",") {
-			switch strings.TrimSpace(box) {
+	}
+	if boxBy != defaultBoxBy {
+		for _, box := range boxByValues(boxBy) {
+			switch box {
 			case BoxByApp:
 				continue
 			case BoxByCluster:
@@ -170,10 +228,21 @@ func NewOptions(r *net_http.Request) Options {
 			case BoxByNamespace:
 				continue
 			default:
+				if isBoxByLabel(box) {
+					continue
+				}
 				BadRequest(fmt.Sprintf("Invalid boxBy [%s]", boxBy))
 			}
 		}
 	}
+	find, findErr := ParseFindExpression(findString)
+	if findErr != nil {
+		BadRequest(fmt.Sprintf("Invalid find [%s]: %v", findString, findErr))
+	}
+	hide, hideErr := ParseFindExpression(hideString)
+	if hideErr != nil {
+		BadRequest(fmt.Sprintf("Invalid hide [%s]: %v", hideString, hideErr))
+	}
 	if includeIdleEdgesString == "" {
 		includeIdleEdges = defaultIncludeIdleEdges
 	} else {
@@ -226,11 +295,20 @@ func NewOptions(r *net_http.Request) Options {
 
 	// If path variable is set then it is the only relevant namespace (it's a node graph)
 	// Else if namespaces query param is set it specifies the relevant namespaces
+	// Else if it's a trace graph then all accessible namespaces are relevant (the trace determines the nodes)
 	// Else error, at least one namespace is required.
 	if namespace != "" {
 		namespaces = namespace
 	}
 
+	if namespaces == "" && traceID != "" {
+		accessible := make([]string, 0, len(accessibleNamespaces))
+		for namespaceName := range accessibleNamespaces {
+			accessible = append(accessible, namespaceName)
+		}
+		namespaces = strings.Join(accessible, ",")
+	}
+
 	if namespaces == "" {
 		BadRequest(fmt.Sprintf("At least one namespace must be specified via the namespaces query parameter."))
 	}
@@ -258,6 +336,8 @@ func NewOptions(r *net_http.Request) Options {
 		TelemetryVendor: telemetryVendor,
 		ConfigOptions: ConfigOptions{
 			BoxBy: boxBy,
+			Find:  find,
+			Hide:  hide,
 			CommonOptions: CommonOptions{
 				Duration:  time.Duration(duration),
 				GraphType: graphType,
@@ -293,6 +373,163 @@ func NewOptions(r *net_http.Request) Options {
 	return options
 }
 
+// BoxByLabels returns the boxBy values that are configured workload labels, in the requested order
+func BoxByLabels(boxBy string) []string {
+	labels := []string{}
+	for _, box := range boxByValues(boxBy) {
+		if box != BoxByApp && box != BoxByCluster && box != BoxByNamespace && isBoxByLabel(box) {
+			labels = append(labels, box)
+		}
+	}
+	return labels
+}
+
+// boxByValues returns the comma separated boxBy values, in the requested order
+func boxByValues(boxBy string) []string {
+	values := []string{}
+	for _, box := range strings.Split(boxBy, ",") {
+		if box = strings.TrimSpace(box); box != "" {
+			values = append(values, box)
+		}
+	}
+	return values
+}
+
+func isBoxByLabel(box string) bool {
+	for _, label := range config.Get().KialiFeatureFlags.UIDefaults.Graph.BoxByLabels {
+		if box == label {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDiffOptions returns the options for a graph diff. The baselineOffset query param determines the
+// baseline queryTime, it is subtracted from the current queryTime (default: 1h).
+func NewDiffOptions(r *net_http.Request) DiffOptions {
+	o := NewOptions(r)
+
+	var baselineOffset model.Duration
+	baselineOffsetString := o.TelemetryOptions.Params.Get("baselineOffset")
+	if baselineOffsetString == "" {
+		baselineOffset, _ = model.ParseDuration(defaultBaselineOffset)
+	} else {
+		var baselineOffsetErr error
+		baselineOffset, baselineOffsetErr = model.ParseDuration(baselineOffsetString)
+		if baselineOffsetErr != nil || baselineOffset <= 0 {
+			BadRequest(fmt.Sprintf("Invalid baselineOffset [%s]", baselineOffsetString))
+		}
+	}
+
+	return DiffOptions{
+		Baseline:       o.WithQueryTime(o.TelemetryOptions.QueryTime - int64(time.Duration(baselineOffset).Seconds())),
+		BaselineOffset: time.Duration(baselineOffset),
+		Options:        o,
+	}
+}
+
+// NewPathOptions returns the options for a path analysis. The source and dest query params are
+// required. Path response times are always calculated, by default using the 95th percentile.
+func NewPathOptions(r *net_http.Request) PathOptions {
+	o := NewOptions(r)
+
+	if o.ConfigVendor != VendorCytoscape {
+		BadRequest(fmt.Sprintf("Invalid configVendor [%s], path analysis supports only configVendor cytoscape", o.ConfigVendor))
+	}
+
+	source := o.TelemetryOptions.Params.Get("source")
+	dest := o.TelemetryOptions.Params.Get("dest")
+	if source == "" || dest == "" {
+		BadRequest("Path analysis requires both the source and dest query parameters.")
+	}
+
+	if o.TelemetryOptions.Params.Get("responseTime") == "" {
+		o.TelemetryOptions.Params.Set("responseTime", defaultPathResponseTime)
+	}
+	if !o.Appenders.All {
+		o.Appenders.AppenderNames = append(o.Appenders.AppenderNames, pathAppenderName)
+	}
+
+	return PathOptions{
+		Dest:    dest,
+		Source:  source,
+		Options: o,
+	}
+}
+
+// NewSnapshotOptions returns the options for a saved graph snapshot. The name query param optionally
+// describes the snapshot.
+func NewSnapshotOptions(r *net_http.Request) SnapshotOptions {
+	o := NewOptions(r)
+
+	if o.ConfigVendor != VendorCytoscape {
+		BadRequest(fmt.Sprintf("Invalid configVendor [%s], graph snapshots support only configVendor cytoscape", o.ConfigVendor))
+	}
+
+	return SnapshotOptions{
+		Name:    o.TelemetryOptions.Params.Get("name"),
+		Options: o,
+	}
+}
+
+// NewStreamOptions returns the options for a graph stream. The refreshInterval query param determines
+// how often the graph is regenerated (default: the configured UI refresh interval).
+func NewStreamOptions(r *net_http.Request) StreamOptions {
+	o := NewOptions(r)
+
+	if o.ConfigVendor != VendorCytoscape {
+		BadRequest(fmt.Sprintf("Invalid configVendor [%s], graph streams support only configVendor cytoscape", o.ConfigVendor))
+	}
+
+	refreshIntervalString := o.TelemetryOptions.Params.Get("refreshInterval")
+	if refreshIntervalString == "" {
+		refreshIntervalString = config.Get().KialiFeatureFlags.UIDefaults.RefreshInterval
+	}
+	refreshInterval, refreshIntervalErr := model.ParseDuration(refreshIntervalString)
+	minInterval, _ := model.ParseDuration(minRefreshInterval)
+	if refreshIntervalErr != nil || refreshInterval < minInterval {
+		BadRequest(fmt.Sprintf("Invalid refreshInterval [%s], must be at least %s", refreshIntervalString, minRefreshInterval))
+	}
+
+	return StreamOptions{
+		RefreshInterval: time.Duration(refreshInterval),
+		Options:         o,
+	}
+}
+
+// WithQueryTime returns a copy of the options for a different queryTime. Namespace durations are
+// recalculated because the namespaces may not have existed for the entire time range. A namespace
+// created after the queryTime is omitted, it had no traffic (e.g. the baseline of a diff reports
+// every node of a new namespace as added).
+func (o Options) WithQueryTime(queryTime int64) Options {
+	namespaceMap := NewNamespaceInfoMap()
+	for name, namespaceInfo := range o.TelemetryOptions.Namespaces {
+		if creationTime := o.AccessibleNamespaces[name]; !creationTime.IsZero() && !time.Unix(queryTime, 0).After(creationTime) {
+			continue
+		}
+		namespaceInfo.Duration = getSafeNamespaceDuration(name, o.AccessibleNamespaces[name], o.TelemetryOptions.Duration, queryTime)
+		namespaceMap[name] = namespaceInfo
+	}
+
+	result := o
+	result.ConfigOptions.QueryTime = queryTime
+	result.TelemetryOptions.QueryTime = queryTime
+	result.TelemetryOptions.Namespaces = namespaceMap
+
+	return result
+}
+
+// NewTraceOptions returns the options for a trace graph. The trace is identified by the traceID path param,
+// the graph includes the nodes of every namespace traversed by the trace.
+func NewTraceOptions(r *net_http.Request) TraceOptions {
+	o := NewOptions(r)
+
+	return TraceOptions{
+		TraceID: mux.Vars(r)["traceID"],
+		Options: o,
+	}
+}
+
 // GetGraphKind will return the kind of graph represented by the options.
 func (o *TelemetryOptions) GetGraphKind() string {
 	if o.NodeOptions.App != "" ||
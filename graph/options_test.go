@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestWithQueryTime(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(time.Now().Unix(), 0)
+	o := Options{
+		TelemetryOptions: TelemetryOptions{
+			AccessibleNamespaces: map[string]time.Time{
+				"bookinfo": now.Add(-24 * time.Hour),
+				"travels":  now.Add(-10 * time.Minute),
+			},
+			Namespaces: NamespaceInfoMap{
+				"bookinfo": {Name: "bookinfo", Duration: 10 * time.Minute},
+				"travels":  {Name: "travels", Duration: 10 * time.Minute},
+			},
+			CommonOptions: CommonOptions{Duration: 10 * time.Minute, QueryTime: now.Unix()},
+		},
+	}
+
+	// the namespace created after the baseline queryTime is omitted, instead of rejecting the request
+	baseline := o.WithQueryTime(now.Add(-time.Hour).Unix())
+	assert.Equal(now.Add(-time.Hour).Unix(), baseline.TelemetryOptions.QueryTime)
+	assert.Len(baseline.TelemetryOptions.Namespaces, 1)
+	assert.Equal(10*time.Minute, baseline.TelemetryOptions.Namespaces["bookinfo"].Duration)
+	assert.Len(o.TelemetryOptions.Namespaces, 2)
+
+	// the duration is reduced to the namespace lifetime
+	current := o.WithQueryTime(now.Add(-5 * time.Minute).Unix())
+	assert.Len(current.TelemetryOptions.Namespaces, 2)
+	assert.Equal(5*time.Minute, current.TelemetryOptions.Namespaces["travels"].Duration)
+}
+
+func TestBoxBy(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.UIDefaults.Graph.BoxByLabels = []string{"team", "tier"}
+	config.Set(conf)
+
+	o := ConfigOptions{BoxBy: "tier, namespace,team"}
+	assert.True(o.IsBoxBy(BoxByNamespace))
+	assert.True(o.IsBoxBy("team"))
+	assert.False(o.IsBoxBy(BoxByApp))
+	assert.False(o.IsBoxBy("name"))
+	assert.Equal([]string{"tier", "team"}, BoxByLabels(o.BoxBy))
+}
@@ -0,0 +1,124 @@
+package graph
+
+// Path.go provides support for analyzing the request paths between two nodes of a TrafficMap.
+
+import (
+	"sort"
+)
+
+const (
+	maxPathLength int = 20      // limits the number of edges in a path, protecting against very large meshes
+	maxPaths      int = 1000    // limits the number of paths returned
+	maxPathSteps  int = 1000000 // limits the number of edges traversed by the search
+)
+
+// Path is an observed request path, a chain of edges from the source node to the destination node.
+type Path struct {
+	Edges        []*Edge
+	ErrorRate    float64 // compounded error percentage, the chance that a request fails on any edge of the path
+	IsCritical   bool    // true for the slowest path
+	ResponseTime float64 // cumulative response time, in millis
+}
+
+// pathSearch holds the state of a depth-first path search, bounded by the path length, the number of paths found
+// and the number of edges traversed
+type pathSearch struct {
+	destID    string
+	maxLength int
+	maxPaths  int
+	paths     []*Path
+	steps     int  // the edges that can still be traversed
+	stopped   bool // the search stopped at maxPaths or maxSteps
+	truncated bool // the search stopped, or a path was cut at maxLength
+	visited   map[string]bool
+}
+
+// FindPaths returns every path from the source node to the destination node, without cycles. The
+// paths are sorted by descending ResponseTime, so the critical path, if any, is always first. Edge
+// response times are those set by the responseTime appender, missing values are treated as 0.
+// On very large meshes the search stops after maxPaths paths, or maxPathSteps traversed edges, and the
+// paths longer than maxPathLength edges are not searched. Then complete is false: the returned paths
+// are only those found, the critical path the slowest of them.
+func FindPaths(trafficMap TrafficMap, sourceID, destID string) (paths []*Path, complete bool) {
+	return findPathsWithLimits(trafficMap, sourceID, destID, maxPathLength, maxPaths, maxPathSteps)
+}
+
+func findPathsWithLimits(trafficMap TrafficMap, sourceID, destID string, maxLength, maxPaths, maxSteps int) ([]*Path, bool) {
+	source, sourceOk := trafficMap[sourceID]
+	_, destOk := trafficMap[destID]
+	if !sourceOk || !destOk || sourceID == destID {
+		return []*Path{}, true
+	}
+
+	search := &pathSearch{
+		destID:    destID,
+		maxLength: maxLength,
+		maxPaths:  maxPaths,
+		paths:     []*Path{},
+		steps:     maxSteps,
+		visited:   map[string]bool{sourceID: true},
+	}
+	search.findPaths(source, []*Edge{})
+
+	paths := search.paths
+	sort.SliceStable(paths, func(i, j int) bool {
+		return paths[i].ResponseTime > paths[j].ResponseTime
+	})
+	if len(paths) > 0 {
+		paths[0].IsCritical = true
+	}
+
+	return paths, !search.truncated
+}
+
+func (s *pathSearch) findPaths(n *Node, edges []*Edge) {
+	if len(edges) == s.maxLength {
+		// the search is truncated only if the path could go on
+		for _, e := range n.Edges {
+			if !s.visited[e.Dest.ID] {
+				s.truncated = true
+				break
+			}
+		}
+		return
+	}
+
+	for _, e := range n.Edges {
+		if s.stopped {
+			return
+		}
+		if s.visited[e.Dest.ID] {
+			continue
+		}
+		if s.steps == 0 || len(s.paths) == s.maxPaths {
+			s.stopped = true
+			s.truncated = true
+			return
+		}
+		s.steps--
+		// copy, the backing array is shared with sibling paths
+		pathEdges := append(append([]*Edge{}, edges...), e)
+		if e.Dest.ID == s.destID {
+			s.paths = append(s.paths, newPath(pathEdges))
+			continue
+		}
+		s.visited[e.Dest.ID] = true
+		s.findPaths(e.Dest, pathEdges)
+		s.visited[e.Dest.ID] = false
+	}
+}
+
+func newPath(edges []*Edge) *Path {
+	path := &Path{Edges: edges}
+
+	successRate := 1.0
+	for _, e := range edges {
+		path.ResponseTime += metadataValue(e.Metadata, ResponseTime)
+		for _, p := range Protocols {
+			successRate *= 1.0 - percentErr(e.Metadata, p.EdgeRates, func(r Rate) bool { return r.IsTotal })/100.0
+		}
+	}
+	path.ErrorRate = (1.0 - successRate) * 100.0
+
+	return path
+}
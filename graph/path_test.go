@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPaths(t *testing.T) {
+	assert := assert.New(t)
+
+	// a -> b -> d, a -> c -> d, d -> a (cycle)
+	trafficMap := NewTrafficMap()
+	a := NewNode("east", "bookinfo", "", "bookinfo", "a-v1", "a", "v1", GraphTypeWorkload)
+	b := NewNode("east", "bookinfo", "", "bookinfo", "b-v1", "b", "v1", GraphTypeWorkload)
+	c := NewNode("east", "bookinfo", "", "bookinfo", "c-v1", "c", "v1", GraphTypeWorkload)
+	d := NewNode("east", "bookinfo", "", "bookinfo", "d-v1", "d", "v1", GraphTypeWorkload)
+	trafficMap[a.ID] = &a
+	trafficMap[b.ID] = &b
+	trafficMap[c.ID] = &c
+	trafficMap[d.ID] = &d
+
+	e := a.AddEdge(&b)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 10.0
+	AddToMetadata("http", 9.0, "200", "-", "b", a.Metadata, b.Metadata, e.Metadata)
+	AddToMetadata("http", 1.0, "500", "-", "b", a.Metadata, b.Metadata, e.Metadata)
+	e = b.AddEdge(&d)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 20.0
+	AddToMetadata("http", 8.0, "200", "-", "d", b.Metadata, d.Metadata, e.Metadata)
+	AddToMetadata("http", 2.0, "503", "-", "d", b.Metadata, d.Metadata, e.Metadata)
+	e = a.AddEdge(&c)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 5.0
+	AddToMetadata("http", 10.0, "200", "-", "c", a.Metadata, c.Metadata, e.Metadata)
+	e = c.AddEdge(&d)
+	e.Metadata[ProtocolKey] = "http"
+	e.Metadata[ResponseTime] = 5.0
+	AddToMetadata("http", 10.0, "200", "-", "d", c.Metadata, d.Metadata, e.Metadata)
+	e = d.AddEdge(&a)
+	e.Metadata[ProtocolKey] = "http"
+	AddToMetadata("http", 1.0, "200", "-", "a", d.Metadata, a.Metadata, e.Metadata)
+
+	paths, complete := FindPaths(trafficMap, a.ID, d.ID)
+	assert.True(complete)
+	assert.Equal(2, len(paths))
+
+	// a -> b -> d is the critical path, error rate is 1 - (0.9 * 0.8)
+	assert.True(paths[0].IsCritical)
+	assert.Equal(30.0, paths[0].ResponseTime)
+	assert.InDelta(28.0, paths[0].ErrorRate, 0.0001)
+	assert.Equal(2, len(paths[0].Edges))
+	assert.Equal(b.ID, paths[0].Edges[0].Dest.ID)
+	assert.Equal(d.ID, paths[0].Edges[1].Dest.ID)
+
+	assert.False(paths[1].IsCritical)
+	assert.Equal(10.0, paths[1].ResponseTime)
+	assert.Equal(0.0, paths[1].ErrorRate)
+	assert.Equal(c.ID, paths[1].Edges[0].Dest.ID)
+
+	// b -> d -> a -> c, the cycle is traversed only once
+	paths, _ = FindPaths(trafficMap, b.ID, c.ID)
+	assert.Equal(1, len(paths))
+	assert.Equal(3, len(paths[0].Edges))
+	paths, complete = FindPaths(trafficMap, a.ID, "unknown")
+	assert.True(complete)
+	assert.Equal(0, len(paths))
+
+	// the search stops at the path limit, or when the edges to traverse are exhausted
+	paths, complete = findPathsWithLimits(trafficMap, a.ID, d.ID, 20, 1, 100)
+	assert.False(complete)
+	assert.Equal(1, len(paths))
+	assert.True(paths[0].IsCritical)
+	paths, complete = findPathsWithLimits(trafficMap, a.ID, d.ID, 20, 100, 1)
+	assert.False(complete)
+	assert.Equal(0, len(paths))
+
+	// the paths longer than the length limit are not searched
+	paths, complete = findPathsWithLimits(trafficMap, a.ID, d.ID, 2, 100, 100)
+	assert.True(complete)
+	assert.Equal(2, len(paths))
+	paths, complete = findPathsWithLimits(trafficMap, b.ID, c.ID, 2, 100, 100)
+	assert.False(complete)
+	assert.Equal(0, len(paths))
+	paths, complete = findPathsWithLimits(trafficMap, b.ID, c.ID, 3, 100, 100)
+	assert.True(complete)
+	assert.Equal(1, len(paths))
+}
@@ -124,12 +124,18 @@ var HTTP = Protocol{
 // TCP Protocol
 //
 const (
-	tcp            = "tcp"
-	tcpResponses   = "tcpResponses"
-	tcpIn          = "tcpIn"
-	tcpOut         = "tcpOut"
-	bytesPerSecond = "bytes per second"
-	bps            = "bps"
+	tcp             = "tcp"
+	tcpConnActive   = "tcpConnActive" // estimated connections currently open, set only by the tcpConnections appender
+	tcpConnClosed   = "tcpConnClosed" // connections closed per second, set only by the tcpConnections appender
+	tcpConnOpened   = "tcpConnOpened" // connections opened per second, set only by the tcpConnections appender
+	tcpResponses    = "tcpResponses"
+	tcpIn           = "tcpIn"
+	tcpInConnActive = "tcpInConnActive"
+	tcpInConnClosed = "tcpInConnClosed"
+	tcpInConnOpened = "tcpInConnOpened"
+	tcpOut          = "tcpOut"
+	bytesPerSecond  = "bytes per second"
+	bps             = "bps"
 )
 
 // TCP Protocol
@@ -137,10 +143,16 @@ var TCP = Protocol{
 	Name: tcp,
 	EdgeRates: []Rate{
 		{Name: tcp, IsTotal: true, Precision: 2},
+		{Name: tcpConnActive, Precision: 0},
+		{Name: tcpConnClosed, Precision: 2},
+		{Name: tcpConnOpened, Precision: 2},
 	},
 	EdgeResponses: tcpResponses,
 	NodeRates: []Rate{
 		{Name: tcpIn, IsIn: true, Precision: 2},
+		{Name: tcpInConnActive, Precision: 0},
+		{Name: tcpInConnClosed, Precision: 2},
+		{Name: tcpInConnOpened, Precision: 2},
 		{Name: tcpOut, IsOut: true, Precision: 2},
 	},
 	Unit:      bytesPerSecond,
@@ -225,6 +237,26 @@ func addToMetadataTCP(val float64, flags, host string, sourceMetadata, destMetad
 	addToMetadataResponses(edgeMetadata, tcpResponses, "-", flags, host, val)
 }
 
+// AddTCPConnectionsToMetadata takes the TCP connection values of an edge and adds them as dest and edge traffic
+func AddTCPConnectionsToMetadata(opened, closed, active float64, destMetadata, edgeMetadata Metadata) {
+	addToMetadataValue(destMetadata, tcpInConnOpened, opened)
+	addToMetadataValue(destMetadata, tcpInConnClosed, closed)
+	addToMetadataValue(destMetadata, tcpInConnActive, active)
+	addToMetadataValue(edgeMetadata, tcpConnOpened, opened)
+	addToMetadataValue(edgeMetadata, tcpConnClosed, closed)
+	addToMetadataValue(edgeMetadata, tcpConnActive, active)
+}
+
+// HasTCPConnections returns true if the edge metadata has any TCP connection values
+func HasTCPConnections(edgeMetadata Metadata) bool {
+	for _, k := range []MetadataKey{tcpConnOpened, tcpConnClosed, tcpConnActive} {
+		if _, ok := edgeMetadata[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // IsHTTPErr return true if code is 4xx or 5xx
 func IsHTTPErr(code string) bool {
 	return strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5")
@@ -305,6 +337,11 @@ func AggregateEdgeTraffic(edge, aggregateEdge *Edge) {
 		if val, ok := edge.Metadata[tcp]; ok {
 			addToMetadataValue(aggregateEdge.Metadata, tcp, val.(float64))
 		}
+		for _, k := range []MetadataKey{tcpConnOpened, tcpConnClosed, tcpConnActive} {
+			if val, ok := edge.Metadata[k]; ok {
+				addToMetadataValue(aggregateEdge.Metadata, k, val.(float64))
+			}
+		}
 		if responses, ok := edge.Metadata[tcpResponses]; ok {
 			addToResponses(aggregateEdge.Metadata, tcpResponses, responses.(Responses))
 		}
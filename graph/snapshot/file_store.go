@@ -0,0 +1,129 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kiali/kiali/log"
+)
+
+const snapshotFileExtension = ".json"
+
+// FileStore persists each snapshot as a json file in Directory
+type FileStore struct {
+	Directory string
+}
+
+// NewFileStore returns a FileStore for the directory, creating the directory if necessary
+func NewFileStore(directory string) (*FileStore, error) {
+	if directory == "" {
+		return nil, errors.New("graph snapshot file store requires a directory")
+	}
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+	return &FileStore{Directory: directory}, nil
+}
+
+// Delete implements Store
+func (s *FileStore) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Get implements Store
+func (s *FileStore) Get(id string) (*Snapshot, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err = json.Unmarshal(raw, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid graph snapshot [%s]: %v", id, err)
+	}
+	return snapshot, nil
+}
+
+// List implements Store. Files that can not be read as a snapshot are skipped.
+func (s *FileStore) List() ([]Info, error) {
+	files, err := ioutil.ReadDir(s.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []Info{}
+	for _, file := range files {
+		id := strings.TrimSuffix(file.Name(), snapshotFileExtension)
+		if file.IsDir() || !IsValidID(id) || !strings.HasSuffix(file.Name(), snapshotFileExtension) {
+			continue
+		}
+		raw, err := ioutil.ReadFile(filepath.Join(s.Directory, file.Name()))
+		if err != nil {
+			log.Warningf("Skipping graph snapshot [%s]: %v", id, err)
+			continue
+		}
+		info := Info{}
+		if err = json.Unmarshal(raw, &info); err != nil {
+			log.Warningf("Skipping graph snapshot [%s]: %v", id, err)
+			continue
+		}
+		infos = append(infos, info)
+	}
+	sortInfos(infos)
+
+	return infos, nil
+}
+
+// Save implements Store. The snapshot is written to a temporary file first, so that a partially
+// written snapshot is never visible.
+func (s *FileStore) Save(snapshot *Snapshot) error {
+	path, err := s.path(snapshot.ID)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.Directory, snapshot.ID+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileStore) path(id string) (string, error) {
+	if !IsValidID(id) {
+		return "", fmt.Errorf("invalid graph snapshot ID [%s]", id)
+	}
+	return filepath.Join(s.Directory, id+snapshotFileExtension), nil
+}
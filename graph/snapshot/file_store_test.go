@@ -0,0 +1,91 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+func TestFileStore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kiali-graph-snapshots")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileStore(filepath.Join(dir, "snapshots"))
+	assert.NoError(err)
+
+	infos, err := store.List()
+	assert.NoError(err)
+	assert.Equal(0, len(infos))
+
+	older := &Snapshot{
+		Info: Info{
+			ID:      "0123456789abcdef0123456789abcdef",
+			Created: 1000,
+			Name:    "older",
+			Options: Options{GraphType: "workload", Namespaces: []string{"bookinfo"}},
+			Owner:   "alice",
+		},
+		Config: cytoscape.Config{GraphType: "workload"},
+	}
+	newer := &Snapshot{
+		Info: Info{
+			ID:      "fedcba9876543210fedcba9876543210",
+			Created: 2000,
+			Name:    "newer",
+			Options: Options{GraphType: "app", Namespaces: []string{"bookinfo", "istio-system"}},
+		},
+		Config: cytoscape.Config{GraphType: "app"},
+	}
+	assert.NoError(store.Save(older))
+	assert.NoError(store.Save(newer))
+
+	// files that are not snapshots are ignored
+	assert.NoError(ioutil.WriteFile(filepath.Join(store.Directory, "README"), []byte("readme"), 0600))
+	assert.NoError(ioutil.WriteFile(filepath.Join(store.Directory, "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.json"), []byte("{"), 0600))
+
+	infos, err = store.List()
+	assert.NoError(err)
+	assert.Equal(2, len(infos))
+	assert.Equal(newer.ID, infos[0].ID)
+	assert.Equal("newer", infos[0].Name)
+	assert.Equal([]string{"bookinfo", "istio-system"}, infos[0].Options.Namespaces)
+	assert.Equal(older.ID, infos[1].ID)
+
+	s, err := store.Get(older.ID)
+	assert.NoError(err)
+	assert.Equal(older.Info, s.Info)
+	assert.Equal("workload", s.Config.GraphType)
+
+	_, err = store.Get("00000000000000000000000000000000")
+	assert.Equal(ErrNotFound, err)
+
+	_, err = store.Get("../../etc/passwd")
+	assert.Error(err)
+	assert.NotEqual(ErrNotFound, err)
+
+	assert.NoError(store.Delete(older.ID))
+	assert.Equal(ErrNotFound, store.Delete(older.ID))
+
+	infos, err = store.List()
+	assert.NoError(err)
+	assert.Equal(1, len(infos))
+	assert.Equal(newer.ID, infos[0].ID)
+}
+
+func TestIsValidID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsValidID("0123456789abcdef0123456789abcdef"))
+	assert.False(IsValidID(""))
+	assert.False(IsValidID("0123456789ABCDEF0123456789ABCDEF"))
+	assert.False(IsValidID("0123456789abcdef"))
+	assert.False(IsValidID("../0123456789abcdef0123456789abcd"))
+}
@@ -0,0 +1,132 @@
+// Package snapshot provides the persistence of generated graphs. A snapshot holds the cytoscape config
+// of a graph along with the options used to generate it, so that the graph can be retrieved exactly
+// as it was, regardless of the telemetry retention.
+//
+// The store is pluggable, see config.GraphSnapshotsConfig. The supported store is 'file', where each
+// snapshot is a json file in the configured directory.
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/util"
+)
+
+// The supported stores
+const (
+	StoreFile string = "file"
+)
+
+// ErrNotFound is returned when the requested snapshot does not exist
+var ErrNotFound = errors.New("snapshot not found")
+
+var idRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// Options are the options used to generate the snapshot graph
+type Options struct {
+	BoxBy              string     `json:"boxBy"`
+	Duration           int64      `json:"duration"` // in seconds
+	GraphType          string     `json:"graphType"`
+	InjectServiceNodes bool       `json:"injectServiceNodes"`
+	Namespaces         []string   `json:"namespaces"`
+	Params             url.Values `json:"params"`    // the raw query params of the graph request
+	QueryTime          int64      `json:"queryTime"` // unix time in seconds
+	TelemetryVendor    string     `json:"telemetryVendor"`
+}
+
+// Info describes a snapshot, without its graph
+type Info struct {
+	ID      string  `json:"id"`
+	Created int64   `json:"created"` // unix time in seconds
+	Name    string  `json:"name,omitempty"`
+	Options Options `json:"options"`
+	Owner   string  `json:"owner,omitempty"` // the user that saved the snapshot, empty if not authenticated
+}
+
+// Snapshot is a saved graph
+type Snapshot struct {
+	Info
+	Config cytoscape.Config `json:"config"`
+}
+
+// Store persists snapshots
+type Store interface {
+	// Delete removes the snapshot, it returns ErrNotFound if the snapshot does not exist
+	Delete(id string) error
+	// Get returns the snapshot, it returns ErrNotFound if the snapshot does not exist
+	Get(id string) (*Snapshot, error)
+	// List returns every snapshot, most recent first
+	List() ([]Info, error)
+	// Save persists the snapshot, replacing any snapshot with the same ID
+	Save(snapshot *Snapshot) error
+}
+
+// NewSnapshot returns a new snapshot, with a unique ID, for the graph generated with the provided options.
+// The owner is the user saving the snapshot.
+func NewSnapshot(name, owner string, o graph.Options, graphConfig cytoscape.Config) (*Snapshot, error) {
+	randomBytes, err := util.CryptoRandomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := []string{}
+	for namespace := range o.TelemetryOptions.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	return &Snapshot{
+		Info: Info{
+			ID:      fmt.Sprintf("%x", randomBytes),
+			Created: time.Now().Unix(),
+			Name:    name,
+			Options: Options{
+				BoxBy:              o.BoxBy,
+				Duration:           int64(o.TelemetryOptions.Duration.Seconds()),
+				GraphType:          o.TelemetryOptions.GraphType,
+				InjectServiceNodes: o.InjectServiceNodes,
+				Namespaces:         namespaces,
+				Params:             o.TelemetryOptions.Params,
+				QueryTime:          o.TelemetryOptions.QueryTime,
+				TelemetryVendor:    o.TelemetryVendor,
+			},
+			Owner: owner,
+		},
+		Config: graphConfig,
+	}, nil
+}
+
+// NewStore returns the configured store, or an error if snapshots are not enabled
+func NewStore() (Store, error) {
+	cfg := config.Get().GraphSnapshots
+	switch cfg.Store {
+	case StoreFile:
+		return NewFileStore(cfg.Directory)
+	case "":
+		return nil, errors.New("graph snapshots are not enabled, no store is configured")
+	default:
+		return nil, fmt.Errorf("graph snapshot store [%s] not supported", cfg.Store)
+	}
+}
+
+// IsValidID returns true if the ID is well-formed. Stores should reject any other ID.
+func IsValidID(id string) bool {
+	return idRegexp.MatchString(id)
+}
+
+func sortInfos(infos []Info) {
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Created != infos[j].Created {
+			return infos[i].Created > infos[j].Created
+		}
+		return infos[i].ID < infos[j].ID
+	})
+}
@@ -0,0 +1,458 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// AnomalyAppenderName uniquely identifies the appender: anomaly
+	AnomalyAppenderName = "anomaly"
+
+	baselineDayLabel      = "kiali_baseline_day"
+	minErrorRateDeviation = 1.0 // in percentage points, protects against a steady error rate flagging any change
+	minRelativeDeviation  = 0.1 // in relation to the baseline mean, protects against a steady value flagging any change
+)
+
+// AnomalyAppender is responsible for flagging edges whose current request rate, error percentage
+// or response time deviates from a historical baseline. The baseline is the same time window (i.e.
+// the same duration ending at the same time of day) on each of the previous BaselineDays days. A value
+// is anomalous when it deviates from the baseline mean by more than Threshold standard deviations.
+// Only HTTP and GRPC edges are evaluated, and only the baseline days with traffic on the edge are
+// considered, so new edges are never flagged. Response time requires the responseTime appender, which
+// must run first, and uses the same quantile. Nodes are flagged when any incoming edge is anomalous.
+// Because the baseline requires additional, relatively expensive queries, the appender runs only
+// when explicitly requested.
+// Name: anomaly
+type AnomalyAppender struct {
+	BaselineDays       int
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	Quantile           float64
+	QueryTime          int64   // unix time in seconds
+	Threshold          float64 // in standard deviations
+}
+
+// anomalyBaseline holds, for a single key, the value on each baseline day with traffic
+type anomalyBaseline map[string]graph.Metadata
+
+// Name implements Appender
+func (a AnomalyAppender) Name() string {
+	return AnomalyAppenderName
+}
+
+// AppendGraph implements Appender
+func (a AnomalyAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a AnomalyAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	log.Tracef("Generating anomalies for [%d] baseline days; namespace = %v", a.BaselineDays, namespace)
+
+	duration := a.Namespaces[namespace].Duration
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision"
+
+	// key=edgeKey, the baseline traffic metadata for each day
+	rateMap := make(map[string]anomalyBaseline)
+	// key=edgeKey, the baseline response time for each day (stored as metadata to share the map type)
+	responseTimeMap := make(map[string]anomalyBaseline)
+
+	// query prometheus for the baseline request traffic in two queries, the same as for the responseTime appender,
+	// the query order is important as both queries may have overlapping results for edges within the namespace.
+	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
+	query := a.baselineQuery(func(offset string) string {
+		return fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"}[%vs] %s)) by (%s,request_protocol,response_code,grpc_response_status) > 0`,
+			"istio_requests_total",
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			offset,
+			groupBy)
+	})
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	incoming := make(map[string]anomalyBaseline)
+	a.populateBaselineMap(incoming, &vector, true)
+
+	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
+	query = a.baselineQuery(func(offset string) string {
+		return fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"}[%vs] %s)) by (%s,request_protocol,response_code,grpc_response_status) > 0`,
+			"istio_requests_total",
+			namespace,
+			int(duration.Seconds()), // range duration for the query
+			offset,
+			groupBy)
+	})
+	vector = promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	outgoing := make(map[string]anomalyBaseline)
+	a.populateBaselineMap(outgoing, &vector, true)
+	mergeBaselineMaps(rateMap, incoming, outgoing)
+
+	// the response time baseline is generated only if the current response time is available
+	if hasResponseTime(trafficMap) {
+		if a.Quantile == 0.0 {
+			query = a.baselineQuery(func(offset string) string {
+				return fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"}[%vs] %s)) by (%s) / sum(rate(%s{reporter="destination",destination_service_namespace="%s"}[%vs] %s)) by (%s) > 0`,
+					"istio_request_duration_milliseconds_sum",
+					namespace,
+					int(duration.Seconds()), // range duration for the query
+					offset,
+					groupBy,
+					"istio_request_duration_milliseconds_count",
+					namespace,
+					int(duration.Seconds()), // range duration for the query
+					offset,
+					groupBy)
+			})
+		} else {
+			query = a.baselineQuery(func(offset string) string {
+				return fmt.Sprintf(`histogram_quantile(%.2f, sum(rate(%s{reporter="destination",destination_service_namespace="%s"}[%vs] %s)) by (le,%s)) > 0`,
+					a.Quantile,
+					"istio_request_duration_milliseconds_bucket",
+					namespace,
+					int(duration.Seconds()), // range duration for the query
+					offset,
+					groupBy)
+			})
+		}
+		vector = promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+		incoming = make(map[string]anomalyBaseline)
+		a.populateBaselineMap(incoming, &vector, false)
+
+		if a.Quantile == 0.0 {
+			query = a.baselineQuery(func(offset string) string {
+				return fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"}[%vs] %s)) by (%s) / sum(rate(%s{reporter="source",source_workload_namespace="%s"}[%vs] %s)) by (%s) > 0`,
+					"istio_request_duration_milliseconds_sum",
+					namespace,
+					int(duration.Seconds()), // range duration for the query
+					offset,
+					groupBy,
+					"istio_request_duration_milliseconds_count",
+					namespace,
+					int(duration.Seconds()), // range duration for the query
+					offset,
+					groupBy)
+			})
+		} else {
+			query = a.baselineQuery(func(offset string) string {
+				return fmt.Sprintf(`histogram_quantile(%.2f, sum(rate(%s{reporter="source",source_workload_namespace="%s"}[%vs] %s)) by (le,%s)) > 0`,
+					a.Quantile,
+					"istio_request_duration_milliseconds_bucket",
+					namespace,
+					int(duration.Seconds()), // range duration for the query
+					offset,
+					groupBy)
+			})
+		}
+		vector = promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+		outgoing = make(map[string]anomalyBaseline)
+		a.populateBaselineMap(outgoing, &vector, false)
+		mergeBaselineMaps(responseTimeMap, incoming, outgoing)
+	}
+
+	a.applyAnomalies(trafficMap, rateMap, responseTimeMap)
+}
+
+// baselineQuery returns a single query for all of the baseline days, each result labeled with its day
+func (a AnomalyAppender) baselineQuery(dayQuery func(offset string) string) string {
+	queries := make([]string, a.BaselineDays)
+	for day := 1; day <= a.BaselineDays; day++ {
+		queries[day-1] = fmt.Sprintf(`label_replace(%s, "%s", "%d", "", ".*")`, dayQuery(fmt.Sprintf("offset %dd", day)), baselineDayLabel, day)
+	}
+	return strings.Join(queries, " or ")
+}
+
+func hasResponseTime(trafficMap graph.TrafficMap) bool {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			if _, ok := e.Metadata[graph.ResponseTime]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeBaselineMaps adds the results of each query to the baseline map, for a given key and day the first
+// reported value is preferred (i.e. defer to query order)
+func mergeBaselineMaps(baselineMap map[string]anomalyBaseline, queryMaps ...map[string]anomalyBaseline) {
+	for _, queryMap := range queryMaps {
+		for key, baseline := range queryMap {
+			if _, ok := baselineMap[key]; !ok {
+				baselineMap[key] = anomalyBaseline{}
+			}
+			for day, md := range baseline {
+				if _, ok := baselineMap[key][day]; !ok {
+					baselineMap[key][day] = md
+				}
+			}
+		}
+	}
+}
+
+func (a AnomalyAppender) populateBaselineMap(baselineMap map[string]anomalyBaseline, vector *model.Vector, isRequests bool) {
+	for _, s := range *vector {
+		m := s.Metric
+		lDay, dayOk := m[baselineDayLabel]
+		lSourceCluster, sourceClusterOk := m["source_cluster"]
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lDestCluster, destClusterOk := m["destination_cluster"]
+		lDestSvcNs, destSvcNsOk := m["destination_service_namespace"]
+		lDestSvc, destSvcOk := m["destination_service"]
+		lDestSvcName, destSvcNameOk := m["destination_service_name"]
+		lDestWlNs, destWlNsOk := m["destination_workload_namespace"]
+		lDestWl, destWlOk := m["destination_workload"]
+		lDestApp, destAppOk := m["destination_canonical_service"]
+		lDestVer, destVerOk := m["destination_canonical_revision"]
+
+		if !dayOk || !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcNameOk || !destSvcOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk {
+			log.Warningf("populateBaselineMap: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		day := string(lDay)
+		sourceWlNs := string(lSourceWlNs)
+		sourceWl := string(lSourceWl)
+		sourceApp := string(lSourceApp)
+		sourceVer := string(lSourceVer)
+		destSvc := string(lDestSvc)
+
+		// handle clusters
+		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
+
+		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+			continue
+		}
+
+		val := float64(s.Value)
+
+		// handle unusual destinations
+		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
+
+		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+			continue
+		}
+
+		// Should not happen but if NaN for any reason, Just skip it
+		if math.IsNaN(val) {
+			continue
+		}
+
+		// don't inject a service node if destSvcName is not set or the dest node is already a service node.
+		inject := false
+		if a.InjectServiceNodes && graph.IsOK(destSvcName) {
+			_, destNodeType := graph.Id(destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, a.GraphType)
+			inject = (graph.NodeTypeService != destNodeType)
+		}
+
+		if !isRequests {
+			// Only set response time on the outgoing edge. On the incoming edge, we can't validly aggregate response times of the outgoing edges (kiali-2297)
+			if inject {
+				a.addBaselineResponseTime(baselineMap, day, val, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+			} else {
+				a.addBaselineResponseTime(baselineMap, day, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+			}
+			continue
+		}
+
+		lProtocol, protocolOk := m["request_protocol"]
+		lCode, codeOk := m["response_code"]
+		lGrpc, grpcOk := m["grpc_response_status"]
+		if !protocolOk || !codeOk {
+			log.Warningf("populateBaselineMap: Skipping %s, missing expected HTTP/GRPC labels", m.String())
+			continue
+		}
+		protocol := string(lProtocol)
+		code := util.HandleResponseCode(protocol, string(lCode), grpcOk, string(lGrpc))
+
+		if inject {
+			a.addBaselineTraffic(baselineMap, day, protocol, code, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, "", "", "", "")
+			a.addBaselineTraffic(baselineMap, day, protocol, code, val, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		} else {
+			a.addBaselineTraffic(baselineMap, day, protocol, code, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		}
+	}
+}
+
+func (a AnomalyAppender) addBaselineTraffic(baselineMap map[string]anomalyBaseline, day, protocol, code string, val float64, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s %s", sourceID, destID, protocol)
+
+	md := baselineMetadata(baselineMap, key, day)
+	graph.AddToMetadata(protocol, val, code, "", "", nil, nil, md)
+}
+
+func (a AnomalyAppender) addBaselineResponseTime(baselineMap map[string]anomalyBaseline, day string, val float64, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s", sourceID, destID)
+
+	md := baselineMetadata(baselineMap, key, day)
+	if _, found := md[graph.ResponseTime]; !found {
+		md[graph.ResponseTime] = val
+	}
+}
+
+func baselineMetadata(baselineMap map[string]anomalyBaseline, key, day string) graph.Metadata {
+	baseline, ok := baselineMap[key]
+	if !ok {
+		baseline = anomalyBaseline{}
+		baselineMap[key] = baseline
+	}
+	md, ok := baseline[day]
+	if !ok {
+		md = graph.NewMetadata()
+		baseline[day] = md
+	}
+	return md
+}
+
+func (a AnomalyAppender) applyAnomalies(trafficMap graph.TrafficMap, rateMap, responseTimeMap map[string]anomalyBaseline) {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			protocol, ok := e.Metadata[graph.ProtocolKey].(string)
+			if !ok || (protocol != graph.HTTP.Name && protocol != graph.GRPC.Name) {
+				continue
+			}
+
+			var anomalies []*graph.Anomaly
+			if baseline, ok := rateMap[fmt.Sprintf("%s %s %s", e.Source.ID, e.Dest.ID, protocol)]; ok {
+				rate, errorRate := requestRates(e.Metadata)
+				rates := []float64{}
+				errorRates := []float64{}
+				for _, md := range baseline {
+					baselineRate, baselineErrorRate := requestRates(md)
+					rates = append(rates, baselineRate)
+					errorRates = append(errorRates, baselineErrorRate)
+				}
+				if anomaly := a.newAnomaly(graph.AnomalyRequestRate, rate, rates, 0.0); anomaly != nil {
+					anomalies = append(anomalies, anomaly)
+				}
+				if anomaly := a.newAnomaly(graph.AnomalyErrorRate, errorRate, errorRates, minErrorRateDeviation); anomaly != nil {
+					anomalies = append(anomalies, anomaly)
+				}
+			}
+			if responseTime, ok := e.Metadata[graph.ResponseTime]; ok {
+				if baseline, ok := responseTimeMap[fmt.Sprintf("%s %s", e.Source.ID, e.Dest.ID)]; ok {
+					responseTimes := []float64{}
+					for _, md := range baseline {
+						responseTimes = append(responseTimes, md[graph.ResponseTime].(float64))
+					}
+					if anomaly := a.newAnomaly(graph.AnomalyResponseTime, responseTime.(float64), responseTimes, 0.0); anomaly != nil {
+						anomalies = append(anomalies, anomaly)
+					}
+				}
+			}
+
+			if len(anomalies) > 0 {
+				e.Metadata[graph.Anomalies] = anomalies
+				e.Metadata[graph.IsAnomaly] = true
+				e.Dest.Metadata[graph.IsAnomaly] = true
+			}
+		}
+	}
+}
+
+// requestRates returns the total request rate and the error percentage reported in the edge metadata
+func requestRates(md graph.Metadata) (rate, errorRate float64) {
+	var errRate float64
+	for _, p := range graph.Protocols {
+		for _, r := range p.EdgeRates {
+			val, ok := md[r.Name].(float64)
+			switch {
+			case !ok:
+				continue
+			case r.IsTotal:
+				rate += val
+			case r.IsErr:
+				errRate += val
+			}
+		}
+	}
+	if rate > 0.0 {
+		errorRate = errRate / rate * 100.0
+	}
+	return rate, errorRate
+}
+
+// newAnomaly returns an Anomaly if the current value deviates from the baseline values by more than the
+// threshold, nil otherwise. The standard deviation is never considered less than minDeviation, or than
+// minRelativeDeviation of the mean.
+func (a AnomalyAppender) newAnomaly(metric string, current float64, baseline []float64, minDeviation float64) *graph.Anomaly {
+	if len(baseline) == 0 {
+		return nil
+	}
+
+	mean := 0.0
+	for _, val := range baseline {
+		mean += val
+	}
+	mean /= float64(len(baseline))
+
+	variance := 0.0
+	for _, val := range baseline {
+		variance += (val - mean) * (val - mean)
+	}
+	stdDev := math.Max(math.Sqrt(variance/float64(len(baseline))), math.Max(minDeviation, mean*minRelativeDeviation))
+	if stdDev == 0.0 {
+		return nil
+	}
+
+	score := (current - mean) / stdDev
+	if math.Abs(score) <= a.Threshold {
+		return nil
+	}
+
+	return &graph.Anomaly{
+		Baseline: mean,
+		Current:  current,
+		Metric:   metric,
+		Score:    math.Round(score*100) / 100,
+	}
+}
+
+// parseAnomalyOptions returns the validated baseline days and threshold query params
+func parseAnomalyOptions(baselineDaysString, thresholdString string) (int, float64) {
+	baselineDays := defaultAnomalyBaselineDays
+	if baselineDaysString != "" {
+		var err error
+		baselineDays, err = strconv.Atoi(baselineDaysString)
+		if err != nil || baselineDays < 1 || baselineDays > maxAnomalyBaselineDays {
+			graph.BadRequest(fmt.Sprintf("Invalid anomalyBaselineDays, must be between 1 and %d: [%s]", maxAnomalyBaselineDays, baselineDaysString))
+		}
+	}
+
+	threshold := defaultAnomalyThreshold
+	if thresholdString != "" {
+		var err error
+		threshold, err = strconv.ParseFloat(thresholdString, 64)
+		if err != nil || threshold <= 0.0 {
+			graph.BadRequest(fmt.Sprintf("Invalid anomalyThreshold, must be a positive number: [%s]", thresholdString))
+		}
+	}
+
+	return baselineDays, threshold
+}
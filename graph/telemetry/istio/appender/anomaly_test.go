@@ -0,0 +1,145 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestAnomaly(t *testing.T) {
+	assert := assert.New(t)
+
+	q0 := `round(label_replace(sum(rate(istio_requests_total{reporter="destination",destination_service_namespace="bookinfo"}[60s] offset 1d)) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status) > 0, "kiali_baseline_day", "1", "", ".*") or label_replace(sum(rate(istio_requests_total{reporter="destination",destination_service_namespace="bookinfo"}[60s] offset 2d)) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status) > 0, "kiali_baseline_day", "2", "", ".*"),0.001)`
+	q0m0 := anomalyTestMetric("1", "reviews", "200")
+	q0m1 := anomalyTestMetric("2", "reviews", "200")
+	q0m2 := anomalyTestMetric("1", "details", "200")
+	q0m3 := anomalyTestMetric("2", "details", "200")
+	v0 := model.Vector{
+		&model.Sample{
+			Metric: q0m0,
+			Value:  10.0},
+		&model.Sample{
+			Metric: q0m1,
+			Value:  12.0},
+		&model.Sample{
+			Metric: q0m2,
+			Value:  10.0},
+		&model.Sample{
+			Metric: q0m3,
+			Value:  10.0}}
+
+	q1 := `round(label_replace(sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"}[60s] offset 1d)) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status) > 0, "kiali_baseline_day", "1", "", ".*") or label_replace(sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"}[60s] offset 2d)) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status) > 0, "kiali_baseline_day", "2", "", ".*"),0.001)`
+	v1 := model.Vector{}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQuery(api, q0, &v0)
+	mockQuery(api, q1, &v1)
+
+	// productpage -> reviews: steady rate, but 50% errors
+	// productpage -> details: steady errors, but 10x the rate
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	details := graph.NewNode(graph.Unknown, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[details.ID] = &details
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 5.0, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 5.0, "500", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e = productpage.AddEdge(&details)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 100.0, "200", "-", "details", productpage.Metadata, details.Metadata, e.Metadata)
+
+	duration, _ := time.ParseDuration("60s")
+	appender := AnomalyAppender{
+		BaselineDays: 2,
+		GraphType:    graph.GraphTypeWorkload,
+		Namespaces: map[string]graph.NamespaceInfo{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		Quantile:  defaultQuantile,
+		QueryTime: time.Now().Unix(),
+		Threshold: defaultAnomalyThreshold,
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	assert.Equal(nil, productpage.Metadata[graph.IsAnomaly])
+	assert.Equal(true, reviews.Metadata[graph.IsAnomaly])
+	assert.Equal(true, details.Metadata[graph.IsAnomaly])
+
+	for _, e := range productpage.Edges {
+		assert.Equal(true, e.Metadata[graph.IsAnomaly])
+		anomalies := e.Metadata[graph.Anomalies].([]*graph.Anomaly)
+		assert.Equal(1, len(anomalies))
+
+		switch e.Dest.ID {
+		case reviews.ID:
+			assert.Equal(graph.AnomalyErrorRate, anomalies[0].Metric)
+			assert.Equal(0.0, anomalies[0].Baseline)
+			assert.Equal(50.0, anomalies[0].Current)
+			assert.Equal(50.0, anomalies[0].Score)
+		case details.ID:
+			assert.Equal(graph.AnomalyRequestRate, anomalies[0].Metric)
+			assert.Equal(10.0, anomalies[0].Baseline)
+			assert.Equal(100.0, anomalies[0].Current)
+			assert.Equal(90.0, anomalies[0].Score)
+		default:
+			assert.Fail("Unexpected edge")
+		}
+	}
+}
+
+func TestAnomalyDeviation(t *testing.T) {
+	assert := assert.New(t)
+
+	a := AnomalyAppender{Threshold: 2.0}
+
+	// mean 100, std dev 10
+	baseline := []float64{90.0, 110.0, 90.0, 110.0}
+	assert.Nil(a.newAnomaly(graph.AnomalyResponseTime, 115.0, baseline, 0.0))
+	assert.Nil(a.newAnomaly(graph.AnomalyResponseTime, 80.0, baseline, 0.0))
+
+	anomaly := a.newAnomaly(graph.AnomalyResponseTime, 130.0, baseline, 0.0)
+	assert.NotNil(anomaly)
+	assert.Equal(100.0, anomaly.Baseline)
+	assert.Equal(3.0, anomaly.Score)
+
+	anomaly = a.newAnomaly(graph.AnomalyResponseTime, 70.0, baseline, 0.0)
+	assert.NotNil(anomaly)
+	assert.Equal(-3.0, anomaly.Score)
+
+	// no baseline, never anomalous
+	assert.Nil(a.newAnomaly(graph.AnomalyResponseTime, 1000.0, []float64{}, 0.0))
+}
+
+func anomalyTestMetric(day, destination, code string) model.Metric {
+	return model.Metric{
+		"kiali_baseline_day":             model.LabelValue(day),
+		"source_workload_namespace":      "bookinfo",
+		"source_workload":                "productpage-v1",
+		"source_canonical_service":       "productpage",
+		"source_canonical_revision":      "v1",
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            model.LabelValue(destination + ".bookinfo.svc.cluster.local"),
+		"destination_service_name":       model.LabelValue(destination),
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           model.LabelValue(destination + "-v1"),
+		"destination_canonical_service":  model.LabelValue(destination),
+		"destination_canonical_revision": "v1",
+		"request_protocol":               "http",
+		"response_code":                  model.LabelValue(code)}
+}
@@ -9,9 +9,14 @@ import (
 )
 
 const (
-	defaultAggregate      = "request_operation"
-	defaultQuantile       = 0.95
-	defaultThroughputType = "response"
+	defaultAggregate           = "request_operation"
+	defaultAnomalyBaselineDays = 7
+	defaultAnomalyThreshold    = 3.0
+	defaultQuantile            = 0.95
+	defaultSparklinePoints     = 30
+	defaultThroughputType      = "response"
+	maxAnomalyBaselineDays     = 28
+	maxSparklinePoints         = 300
 )
 
 // ParseAppenders determines which appenders should run for this graphing request
@@ -23,6 +28,16 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 			switch appenderName {
 			case AggregateNodeAppenderName:
 				requestedAppenders[AggregateNodeAppenderName] = true
+			case AnomalyAppenderName:
+				requestedAppenders[AnomalyAppenderName] = true
+			case AuthorizationPolicyAppenderName:
+				// the policies are evaluated against the edge principals
+				requestedAppenders[AuthorizationPolicyAppenderName] = true
+				requestedAppenders[SecurityPolicyAppenderName] = true
+			case CircuitBreakerStateAppenderName:
+				requestedAppenders[CircuitBreakerStateAppenderName] = true
+			case CustomMetricsAppenderName:
+				requestedAppenders[CustomMetricsAppenderName] = true
 			case DeadNodeAppenderName:
 				requestedAppenders[DeadNodeAppenderName] = true
 			case HealthConfigAppenderName:
@@ -31,6 +46,8 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 				requestedAppenders[IdleNodeAppenderName] = true
 			case IstioAppenderName:
 				requestedAppenders[IstioAppenderName] = true
+			case ResponseFlagsAppenderName:
+				requestedAppenders[ResponseFlagsAppenderName] = true
 			case ResponseTimeAppenderName:
 				requestedAppenders[ResponseTimeAppenderName] = true
 			case SecurityPolicyAppenderName:
@@ -39,6 +56,10 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 				requestedAppenders[ServiceEntryAppenderName] = true
 			case SidecarsCheckAppenderName:
 				requestedAppenders[SidecarsCheckAppenderName] = true
+			case SparklinesAppenderName:
+				requestedAppenders[SparklinesAppenderName] = true
+			case TCPConnectionsAppenderName:
+				requestedAppenders[TCPConnectionsAppenderName] = true
 			case ThroughputAppenderName:
 				requestedAppenders[ThroughputAppenderName] = true
 			case "":
@@ -70,24 +91,8 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		appenders = append(appenders, a)
 	}
 	if _, ok := requestedAppenders[ResponseTimeAppenderName]; ok || o.Appenders.All {
-		quantile := defaultQuantile
-		responseTimeString := o.Params.Get("responseTime")
-		if responseTimeString != "" {
-			switch responseTimeString {
-			case "avg":
-				quantile = 0.0
-			case "50":
-				quantile = 0.5
-			case "95":
-				quantile = 0.95
-			case "99":
-				quantile = 0.99
-			default:
-				graph.BadRequest(fmt.Sprintf(`Invalid responseTime, must be one of: avg | 50 | 95 | 99: [%s]`, responseTimeString))
-			}
-		}
 		a := ResponseTimeAppender{
-			Quantile:           quantile,
+			Quantile:           parseQuantile(o.Params.Get("responseTime")),
 			GraphType:          o.GraphType,
 			InjectServiceNodes: o.InjectServiceNodes,
 			Namespaces:         o.Namespaces,
@@ -144,6 +149,10 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		a := HealthConfigAppender{}
 		appenders = append(appenders, a)
 	}
+	if _, ok := requestedAppenders[ResponseFlagsAppenderName]; ok || o.Appenders.All {
+		a := ResponseFlagsAppender{}
+		appenders = append(appenders, a)
+	}
 	if _, ok := requestedAppenders[IdleNodeAppenderName]; ok || o.Appenders.All {
 		hasNodeOptions := o.App != "" || o.Workload != "" || o.Service != ""
 		a := IdleNodeAppender{
@@ -163,10 +172,98 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		}
 		appenders = append(appenders, a)
 	}
+	// The workloadLabels appender is required to box by workload label, it runs only for that purpose
+	if labels := graph.BoxByLabels(o.Params.Get("boxBy")); len(labels) > 0 {
+		a := WorkloadLabelsAppender{
+			AccessibleNamespaces: o.AccessibleNamespaces,
+			Labels:               labels,
+		}
+		appenders = append(appenders, a)
+	}
+	// The anomaly appender is expensive, it runs only when requested. It must run after the responseTime appender.
+	if _, ok := requestedAppenders[AnomalyAppenderName]; ok {
+		baselineDays, threshold := parseAnomalyOptions(o.Params.Get("anomalyBaselineDays"), o.Params.Get("anomalyThreshold"))
+		a := AnomalyAppender{
+			BaselineDays:       baselineDays,
+			GraphType:          o.GraphType,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			Quantile:           parseQuantile(o.Params.Get("responseTime")),
+			QueryTime:          o.QueryTime,
+			Threshold:          threshold,
+		}
+		appenders = append(appenders, a)
+	}
+	// The authorizationPolicy appender fetches the policies of every namespace, it runs only when requested
+	if _, ok := requestedAppenders[AuthorizationPolicyAppenderName]; ok {
+		a := AuthorizationPolicyAppender{
+			AccessibleNamespaces: o.AccessibleNamespaces,
+		}
+		appenders = append(appenders, a)
+	}
+	// The tcpConnections appender adds queries for every namespace, it runs only when requested
+	if _, ok := requestedAppenders[TCPConnectionsAppenderName]; ok {
+		a := TCPConnectionsAppender{
+			GraphType:          o.GraphType,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			QueryTime:          o.QueryTime,
+		}
+		appenders = append(appenders, a)
+	}
+	// The circuitBreakerState appender adds a query for every namespace, it runs only when requested
+	if _, ok := requestedAppenders[CircuitBreakerStateAppenderName]; ok {
+		a := CircuitBreakerStateAppender{
+			Namespaces: o.Namespaces,
+			QueryTime:  o.QueryTime,
+		}
+		appenders = append(appenders, a)
+	}
+	// The sparklines appender is expensive, it runs only when requested
+	if _, ok := requestedAppenders[SparklinesAppenderName]; ok {
+		a := SparklinesAppender{
+			GraphType:          o.GraphType,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			Quantile:           parseQuantile(o.Params.Get("responseTime")),
+			QueryTime:          o.QueryTime,
+			Step:               parseSparklineStep(o.Params.Get("sparklineStep"), o.Duration),
+		}
+		appenders = append(appenders, a)
+	}
+	// The customMetrics appender queries for every node and edge, it runs only when requested
+	if _, ok := requestedAppenders[CustomMetricsAppenderName]; ok {
+		a := CustomMetricsAppender{
+			Metrics:    config.Get().GraphCustomMetrics,
+			Namespaces: o.Namespaces,
+			QueryTime:  o.QueryTime,
+		}
+		appenders = append(appenders, a)
+	}
 
 	return appenders
 }
 
+// parseQuantile returns the quantile for the responseTime query param
+func parseQuantile(responseTimeString string) float64 {
+	quantile := defaultQuantile
+	if responseTimeString != "" {
+		switch responseTimeString {
+		case "avg":
+			quantile = 0.0
+		case "50":
+			quantile = 0.5
+		case "95":
+			quantile = 0.95
+		case "99":
+			quantile = 0.99
+		default:
+			graph.BadRequest(fmt.Sprintf(`Invalid responseTime, must be one of: avg | 50 | 95 | 99: [%s]`, responseTimeString))
+		}
+	}
+	return quantile
+}
+
 const (
 	serviceDefinitionListKey = "serviceDefinitionListKey" // global vendor info map[namespace]serviceDefinitionList
 	serviceEntryHostsKey     = "serviceEntryHostsKey"     // global vendor info service entries for all accessible namespaces
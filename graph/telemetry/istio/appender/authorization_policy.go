@@ -0,0 +1,370 @@
+package appender
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	// AuthorizationPolicyAppenderName uniquely identifies the appender: authorizationPolicy
+	AuthorizationPolicyAppenderName = "authorizationPolicy"
+
+	authorizationActionAllow = "ALLOW"
+	authorizationActionDeny  = "DENY"
+	rootAuthorizationKey     = "rootAuthorizationKey" // global vendor info []authorizationPolicy of the root namespace
+)
+
+// AuthorizationPolicyAppender is responsible for evaluating the AuthorizationPolicies against the edges, marking
+// each edge as explicitly allowed, explicitly denied, partially allowed or denied, or allowed by default (see
+// graph.AuthorizationInfo). The policies of the destination namespace, and the mesh-wide policies of the root
+// namespace, apply to the edges whose destination workloads match the policy selector.
+//
+// The evaluation uses what the telemetry reports about the request source: the source principal, provided by
+// the securityPolicy appender, and the source namespace, taken from the principal. A rule with constraints that
+// can't be evaluated from the telemetry (request principals, ip blocks, operations and conditions) applies to
+// only some of the edge requests, an edge matching only such rules is reported as partial. AUDIT and CUSTOM
+// policies are ignored. The appender is expensive, it runs only when requested.
+// Name: authorizationPolicy
+type AuthorizationPolicyAppender struct {
+	AccessibleNamespaces map[string]time.Time
+}
+
+// authorizationPolicy is the part of an AuthorizationPolicy that can be evaluated against an edge
+type authorizationPolicy struct {
+	action    string
+	name      string
+	namespace string
+	rules     []authorizationRule
+	selector  labels.Selector // nil selects every workload
+}
+
+// authorizationRule matches an edge when any of its sources matches, a rule without sources matches every edge.
+// A conditional rule has constraints that can't be evaluated against the edge, it matches only some requests.
+type authorizationRule struct {
+	conditional bool
+	sources     []authorizationSource
+}
+
+type authorizationSource struct {
+	conditional   bool
+	namespaces    []string
+	notNamespaces []string
+	notPrincipals []string
+	principals    []string
+}
+
+// unevaluatedSourceFields are the source fields that can't be evaluated from the telemetry
+var unevaluatedSourceFields = []string{"requestPrincipals", "notRequestPrincipals", "ipBlocks", "notIpBlocks", "remoteIpBlocks", "notRemoteIpBlocks"}
+
+// Name implements Appender
+func (a AuthorizationPolicyAppender) Name() string {
+	return AuthorizationPolicyAppenderName
+}
+
+// AppendGraph implements Appender
+func (a AuthorizationPolicyAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	policies := getRootAuthorizationPolicies(a.AccessibleNamespaces, globalInfo)
+	if namespaceInfo.Namespace != config.Get().IstioNamespace {
+		policies = append(policies, getAuthorizationPolicies(namespaceInfo.Namespace, globalInfo)...)
+	}
+
+	applyAuthorizationPolicies(trafficMap, namespaceInfo.Namespace, policies, globalInfo)
+}
+
+func applyAuthorizationPolicies(trafficMap graph.TrafficMap, namespace string, policies []authorizationPolicy, globalInfo *graph.AppenderGlobalInfo) {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			// the policies are enforced by the destination proxies, limit the edges to destinations in the namespace
+			if e.Dest.Namespace != namespace {
+				continue
+			}
+
+			var workloads []models.WorkloadListItem
+			switch e.Dest.NodeType {
+			case graph.NodeTypeWorkload:
+				if workload, found := getWorkload(e.Dest.Namespace, e.Dest.Workload, globalInfo); found {
+					workloads = []models.WorkloadListItem{*workload}
+				}
+			case graph.NodeTypeApp:
+				workloads = getAppWorkloads(e.Dest.Namespace, e.Dest.App, e.Dest.Version, globalInfo)
+			default:
+				continue
+			}
+			if len(workloads) == 0 {
+				continue
+			}
+
+			sourcePrincipal := ""
+			if principal, ok := e.Metadata[graph.SourcePrincipal]; ok {
+				sourcePrincipal = principal.(string)
+			}
+			e.Metadata[graph.Authorization] = authorize(sourcePrincipal, workloads, policies)
+		}
+	}
+}
+
+// authorize returns the decision of the policies for requests from the source principal to the workloads. DENY
+// policies are evaluated first, a request that no DENY policy matches is allowed if no ALLOW policy applies to
+// the workloads, or if an ALLOW policy matches. Policies matching only through conditional rules make the
+// decision partial, unless another policy fully denies the requests.
+func authorize(sourcePrincipal string, workloads []models.WorkloadListItem, policies []authorizationPolicy) *graph.AuthorizationInfo {
+	// principals are reported as spiffe://<trust domain>/ns/<namespace>/sa/<service account> but configured without
+	// the spiffe:// prefix
+	principal := strings.TrimPrefix(sourcePrincipal, "spiffe://")
+	namespace := ""
+	if parts := strings.Split(principal, "/"); len(parts) == 5 && parts[1] == "ns" {
+		namespace = parts[2]
+	}
+
+	denied := []string{}
+	allowed := []string{}
+	applying := []string{}
+	partiallyDenied := []string{}
+	partiallyAllowed := []string{}
+	for _, p := range policies {
+		if !p.selects(workloads) {
+			continue
+		}
+		matches, conditional := p.matches(principal, namespace)
+		switch p.action {
+		case authorizationActionDeny:
+			if matches && conditional {
+				partiallyDenied = append(partiallyDenied, p.key())
+			} else if matches {
+				denied = append(denied, p.key())
+			}
+		case authorizationActionAllow:
+			applying = append(applying, p.key())
+			if matches && conditional {
+				partiallyAllowed = append(partiallyAllowed, p.key())
+			} else if matches {
+				allowed = append(allowed, p.key())
+			}
+		}
+	}
+
+	switch {
+	case len(denied) > 0:
+		sort.Strings(denied)
+		return &graph.AuthorizationInfo{Decision: graph.AuthorizationDeny, Policies: denied}
+	case len(partiallyDenied) > 0 && (len(applying) == 0 || len(allowed) > 0):
+		sort.Strings(partiallyDenied)
+		return &graph.AuthorizationInfo{Decision: graph.AuthorizationPartial, Policies: partiallyDenied}
+	case len(applying) == 0:
+		return &graph.AuthorizationInfo{Decision: graph.AuthorizationDefault, Policies: []string{}}
+	case len(allowed) > 0:
+		sort.Strings(allowed)
+		return &graph.AuthorizationInfo{Decision: graph.AuthorizationAllow, Policies: allowed}
+	case len(partiallyAllowed) > 0:
+		partial := append(partiallyDenied, partiallyAllowed...)
+		sort.Strings(partial)
+		return &graph.AuthorizationInfo{Decision: graph.AuthorizationPartial, Policies: partial}
+	default:
+		sort.Strings(applying)
+		return &graph.AuthorizationInfo{Decision: graph.AuthorizationDeny, Policies: applying}
+	}
+}
+
+func (p authorizationPolicy) key() string {
+	return fmt.Sprintf("%s/%s", p.namespace, p.name)
+}
+
+// selects returns true if the policy applies to any of the workloads
+func (p authorizationPolicy) selects(workloads []models.WorkloadListItem) bool {
+	if p.selector == nil {
+		return true
+	}
+	for _, w := range workloads {
+		if p.selector.Matches(labels.Set(w.Labels)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches returns true if any policy rule matches the source, a policy without rules matches nothing. The match is
+// conditional when only conditional rules or sources match, i.e. it may apply to only some of the requests.
+func (p authorizationPolicy) matches(principal, namespace string) (matches, conditional bool) {
+	for _, r := range p.rules {
+		if len(r.sources) == 0 {
+			if !r.conditional {
+				return true, false
+			}
+			matches = true
+			continue
+		}
+		for _, s := range r.sources {
+			if s.matches(principal, namespace) {
+				if !r.conditional && !s.conditional {
+					return true, false
+				}
+				matches = true
+			}
+		}
+	}
+	return matches, matches
+}
+
+// matches returns true if the source fields all match. The principal and namespace are empty for requests without
+// mutual TLS, they never match a principals or namespaces field.
+func (s authorizationSource) matches(principal, namespace string) bool {
+	if len(s.principals) > 0 && !matchesAnyValue(principal, s.principals) {
+		return false
+	}
+	if len(s.notPrincipals) > 0 && matchesAnyValue(principal, s.notPrincipals) {
+		return false
+	}
+	if len(s.namespaces) > 0 && !matchesAnyValue(namespace, s.namespaces) {
+		return false
+	}
+	if len(s.notNamespaces) > 0 && matchesAnyValue(namespace, s.notNamespaces) {
+		return false
+	}
+	return true
+}
+
+// matchesAnyValue returns true if the value matches any of the AuthorizationPolicy string values, which support
+// exact, prefix (e.g. "abc*"), suffix (e.g. "*abc") and presence ("*") matching
+func matchesAnyValue(value string, policyValues []string) bool {
+	if value == "" {
+		return false
+	}
+	for _, pv := range policyValues {
+		switch {
+		case pv == "*":
+			return true
+		case strings.HasSuffix(pv, "*") && strings.HasPrefix(value, strings.TrimSuffix(pv, "*")):
+			return true
+		case strings.HasPrefix(pv, "*") && strings.HasSuffix(value, strings.TrimPrefix(pv, "*")):
+			return true
+		case pv == value:
+			return true
+		}
+	}
+	return false
+}
+
+// getRootAuthorizationPolicies returns the mesh-wide policies of the root namespace, if accessible
+func getRootAuthorizationPolicies(accessibleNamespaces map[string]time.Time, gi *graph.AppenderGlobalInfo) []authorizationPolicy {
+	if policies, ok := gi.Vendor[rootAuthorizationKey]; ok {
+		return policies.([]authorizationPolicy)
+	}
+
+	policies := []authorizationPolicy{}
+	rootNamespace := config.Get().IstioNamespace
+	if _, ok := accessibleNamespaces[rootNamespace]; ok {
+		policies = getAuthorizationPolicies(rootNamespace, gi)
+	}
+	gi.Vendor[rootAuthorizationKey] = policies
+
+	return policies
+}
+
+func getAuthorizationPolicies(namespace string, gi *graph.AppenderGlobalInfo) []authorizationPolicy {
+	istioCfg, err := gi.Business.IstioConfig.GetIstioConfigList(business.IstioConfigCriteria{
+		IncludeAuthorizationPolicies: true,
+		Namespace:                    namespace,
+	})
+	graph.CheckError(err)
+
+	policies := []authorizationPolicy{}
+	for _, ap := range istioCfg.AuthorizationPolicies {
+		if policy, ok := parseAuthorizationPolicy(ap); ok {
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
+
+// parseAuthorizationPolicy returns the ALLOW or DENY policy, it returns false for other actions or an invalid selector
+func parseAuthorizationPolicy(ap models.AuthorizationPolicy) (authorizationPolicy, bool) {
+	policy := authorizationPolicy{
+		action:    authorizationActionAllow,
+		name:      ap.Metadata.Name,
+		namespace: ap.Metadata.Namespace,
+		rules:     []authorizationRule{},
+	}
+
+	if action, ok := ap.Spec.Action.(string); ok && action != "" {
+		policy.action = action
+	}
+	if policy.action != authorizationActionAllow && policy.action != authorizationActionDeny {
+		return policy, false
+	}
+
+	if selector, ok := ap.Spec.Selector.(map[string]interface{}); ok {
+		if matchLabels, ok := selector["matchLabels"].(map[string]interface{}); ok && len(matchLabels) > 0 {
+			labelSet := labels.Set{}
+			for k, v := range matchLabels {
+				labelSet[k] = fmt.Sprintf("%v", v)
+			}
+			selector, err := labels.ValidatedSelectorFromSet(labelSet)
+			if err != nil {
+				log.Warningf("Skipping AuthorizationPolicy [%s], invalid selector: %v", policy.key(), err)
+				return policy, false
+			}
+			policy.selector = selector
+		}
+	}
+
+	if rules, ok := ap.Spec.Rules.([]interface{}); ok {
+		for _, r := range rules {
+			rule := authorizationRule{sources: []authorizationSource{}}
+			if rMap, ok := r.(map[string]interface{}); ok {
+				_, hasTo := rMap["to"]
+				_, hasWhen := rMap["when"]
+				rule.conditional = hasTo || hasWhen
+				if from, ok := rMap["from"].([]interface{}); ok {
+					for _, f := range from {
+						if fMap, ok := f.(map[string]interface{}); ok {
+							if source, ok := fMap["source"].(map[string]interface{}); ok {
+								conditional := false
+								for _, field := range unevaluatedSourceFields {
+									if _, ok := source[field]; ok {
+										conditional = true
+									}
+								}
+								rule.sources = append(rule.sources, authorizationSource{
+									conditional:   conditional,
+									namespaces:    stringValues(source["namespaces"]),
+									notNamespaces: stringValues(source["notNamespaces"]),
+									notPrincipals: stringValues(source["notPrincipals"]),
+									principals:    stringValues(source["principals"]),
+								})
+							}
+						}
+					}
+				}
+			}
+			policy.rules = append(policy.rules, rule)
+		}
+	}
+
+	return policy, true
+}
+
+func stringValues(values interface{}) []string {
+	result := []string{}
+	if vSlice, ok := values.([]interface{}); ok {
+		for _, v := range vSlice {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package appender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+)
+
+func TestAuthorizationPolicy(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	trafficMap := graph.NewTrafficMap()
+	gateway := graph.NewNode(graph.Unknown, "istio-system", "", "istio-system", "istio-ingressgateway", "istio-ingressgateway", "latest", graph.GraphTypeVersionedApp)
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	ratings := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "ratings-v1", "ratings", "v1", graph.GraphTypeVersionedApp)
+	details := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeVersionedApp)
+	trafficMap[gateway.ID] = &gateway
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[ratings.ID] = &ratings
+	trafficMap[details.ID] = &details
+
+	gatewayEdge := gateway.AddEdge(&productpage)
+	gatewayEdge.Metadata[graph.SourcePrincipal] = "spiffe://cluster.local/ns/istio-system/sa/istio-ingressgateway-service-account"
+	reviewsEdge := productpage.AddEdge(&reviews)
+	reviewsEdge.Metadata[graph.SourcePrincipal] = "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-productpage"
+	detailsEdge := productpage.AddEdge(&details)
+	detailsEdge.Metadata[graph.SourcePrincipal] = "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-productpage"
+	ratingsEdge := reviews.AddEdge(&ratings)
+	ratingsEdge.Metadata[graph.SourcePrincipal] = "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-reviews"
+
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Vendor[workloadListKey] = map[string]*models.WorkloadList{
+		"bookinfo": {
+			Namespace: models.Namespace{Name: "bookinfo"},
+			Workloads: []models.WorkloadListItem{
+				authorizationTestWorkload("details-v1", "details", "v1"),
+				authorizationTestWorkload("productpage-v1", "productpage", "v1"),
+				authorizationTestWorkload("ratings-v1", "ratings", "v1"),
+				authorizationTestWorkload("reviews-v1", "reviews", "v1"),
+			},
+		},
+	}
+
+	policies := []authorizationPolicy{}
+	for _, ap := range []models.AuthorizationPolicy{
+		// productpage only accepts requests from the ingress gateway
+		authorizationTestPolicy("productpage-viewer", "bookinfo", "", map[string]interface{}{"app": "productpage"}, []interface{}{
+			map[string]interface{}{
+				"from": []interface{}{
+					map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{"cluster.local/ns/istio-system/sa/istio-ingressgateway-*"}}},
+				},
+			},
+		}),
+		// ratings denies requests from the bookinfo namespace
+		authorizationTestPolicy("ratings-deny", "bookinfo", "DENY", map[string]interface{}{"app": "ratings"}, []interface{}{
+			map[string]interface{}{
+				"from": []interface{}{
+					map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"bookinfo"}}},
+				},
+			},
+		}),
+		// reviews only accepts requests from outside of the bookinfo namespace
+		authorizationTestPolicy("reviews-viewer", "bookinfo", "ALLOW", map[string]interface{}{"app": "reviews"}, []interface{}{
+			map[string]interface{}{
+				"from": []interface{}{
+					map[string]interface{}{"source": map[string]interface{}{"notNamespaces": []interface{}{"bookinfo"}}},
+				},
+			},
+		}),
+		// audit policies are ignored
+		authorizationTestPolicy("details-audit", "bookinfo", "AUDIT", nil, []interface{}{map[string]interface{}{}}),
+	} {
+		if policy, ok := parseAuthorizationPolicy(ap); ok {
+			policies = append(policies, policy)
+		}
+	}
+	assert.Equal(3, len(policies))
+
+	applyAuthorizationPolicies(trafficMap, "bookinfo", policies, globalInfo)
+
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationAllow, Policies: []string{"bookinfo/productpage-viewer"}}, gatewayEdge.Metadata[graph.Authorization])
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationDeny, Policies: []string{"bookinfo/reviews-viewer"}}, reviewsEdge.Metadata[graph.Authorization])
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationDefault, Policies: []string{}}, detailsEdge.Metadata[graph.Authorization])
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationDeny, Policies: []string{"bookinfo/ratings-deny"}}, ratingsEdge.Metadata[graph.Authorization])
+}
+
+func TestAuthorizeWithoutPrincipal(t *testing.T) {
+	assert := assert.New(t)
+
+	workloads := []models.WorkloadListItem{authorizationTestWorkload("reviews-v1", "reviews", "v1")}
+	allowAll, _ := parseAuthorizationPolicy(authorizationTestPolicy("allow-all", "bookinfo", "", nil, []interface{}{map[string]interface{}{}}))
+	allowMTLS, _ := parseAuthorizationPolicy(authorizationTestPolicy("allow-mtls", "bookinfo", "", nil, []interface{}{
+		map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{"*"}}},
+			},
+		},
+	}))
+	denyNothing, _ := parseAuthorizationPolicy(authorizationTestPolicy("deny-nothing", "bookinfo", "DENY", nil, nil))
+
+	// requests without mutual TLS have no principal, they only match rules without principals or namespaces
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationAllow, Policies: []string{"bookinfo/allow-all"}}, authorize("", workloads, []authorizationPolicy{allowAll, allowMTLS, denyNothing}))
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationDeny, Policies: []string{"bookinfo/allow-mtls"}}, authorize("", workloads, []authorizationPolicy{allowMTLS, denyNothing}))
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationDefault, Policies: []string{}}, authorize("", workloads, []authorizationPolicy{denyNothing}))
+}
+
+func TestAuthorizeConditionalRules(t *testing.T) {
+	assert := assert.New(t)
+
+	principal := "spiffe://cluster.local/ns/bookinfo/sa/bookinfo-productpage"
+	workloads := []models.WorkloadListItem{authorizationTestWorkload("reviews-v1", "reviews", "v1")}
+	denyAdmin, _ := parseAuthorizationPolicy(authorizationTestPolicy("deny-admin", "bookinfo", "DENY", nil, []interface{}{
+		map[string]interface{}{
+			"to": []interface{}{
+				map[string]interface{}{"operation": map[string]interface{}{"paths": []interface{}{"/admin"}}},
+			},
+		},
+	}))
+	allowJWT, _ := parseAuthorizationPolicy(authorizationTestPolicy("allow-jwt", "bookinfo", "", nil, []interface{}{
+		map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"source": map[string]interface{}{"requestPrincipals": []interface{}{"*"}}},
+			},
+		},
+	}))
+	denyAll, _ := parseAuthorizationPolicy(authorizationTestPolicy("deny-all", "bookinfo", "DENY", nil, []interface{}{map[string]interface{}{}}))
+
+	// a rule without sources but with operations only denies some of the requests
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationPartial, Policies: []string{"bookinfo/deny-admin"}}, authorize(principal, workloads, []authorizationPolicy{denyAdmin}))
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationPartial, Policies: []string{"bookinfo/allow-jwt", "bookinfo/deny-admin"}}, authorize(principal, workloads, []authorizationPolicy{denyAdmin, allowJWT}))
+	assert.Equal(&graph.AuthorizationInfo{Decision: graph.AuthorizationDeny, Policies: []string{"bookinfo/deny-all"}}, authorize(principal, workloads, []authorizationPolicy{denyAdmin, denyAll}))
+}
+
+func TestMatchesAnyValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(matchesAnyValue("bookinfo", []string{"default", "bookinfo"}))
+	assert.True(matchesAnyValue("bookinfo", []string{"book*"}))
+	assert.True(matchesAnyValue("bookinfo", []string{"*info"}))
+	assert.True(matchesAnyValue("bookinfo", []string{"*"}))
+	assert.False(matchesAnyValue("bookinfo", []string{"default", "book"}))
+	assert.False(matchesAnyValue("", []string{"*"}))
+}
+
+func authorizationTestWorkload(name, app, version string) models.WorkloadListItem {
+	return models.WorkloadListItem{
+		Name:   name,
+		Labels: map[string]string{"app": app, "version": version},
+	}
+}
+
+func authorizationTestPolicy(name, namespace, action string, matchLabels map[string]interface{}, rules interface{}) models.AuthorizationPolicy {
+	ap := models.AuthorizationPolicy{}
+	ap.Metadata = meta_v1.ObjectMeta{Name: name, Namespace: namespace}
+	if action != "" {
+		ap.Spec.Action = action
+	}
+	if matchLabels != nil {
+		ap.Spec.Selector = map[string]interface{}{"matchLabels": matchLabels}
+	}
+	ap.Spec.Rules = rules
+	return ap
+}
@@ -0,0 +1,270 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// CircuitBreakerStateAppenderName uniquely identifies the appender: circuitBreakerState
+	CircuitBreakerStateAppenderName = "circuitBreakerState"
+
+	cbStateLabel     = "kiali_cb_state"
+	cbStateEjections = "ejections" // hosts currently ejected by outlier detection
+	cbStateOverflow  = "overflow"  // requests per second rejected because the pending requests limit was reached
+)
+
+// CircuitBreakerStateAppender is responsible for reporting the live state of the circuit breakers (i.e. the
+// DestinationRule outlier detection and connection pool settings) on the edges. The state is read from the
+// Envoy cluster stats of the source proxies, which must be configured to report them (see the proxy
+// statsInclusionPrefixes for cluster.outbound):
+// - CBEjections: the upstream hosts currently ejected by outlier detection, at queryTime
+// - CBOverflow:  the requests per second rejected because the pending requests limit was reached
+// The proxies of a source share the same view of the destination endpoints, each one ejects them on its own. The
+// ejections are therefore the maximum reported by any proxy of the source (including its versions in an app graph),
+// summed over the disjoint endpoints of the host subsets. The ports of a host, and the hosts of a destination workload,
+// share the same endpoints, so the maximum over them is reported. The overflow, a rate of rejected requests, is the
+// sum over every proxy, host, port and subset.
+// A destination node reports the maximum ejections and the total overflow of its incoming edges. Nodes and
+// edges with ejections or overflow are marked with IsCBTripped.
+// Name: circuitBreakerState
+type CircuitBreakerStateAppender struct {
+	Namespaces graph.NamespaceInfoMap
+	QueryTime  int64 // unix time in seconds
+}
+
+// cbState is the circuit breaker state reported by the proxies of a source app (version) for a destination host
+type cbState struct {
+	app       string
+	ejections map[string]*cbPortEjections // keyed by port
+	host      string
+	namespace string
+	overflow  float64
+	version   string
+}
+
+// cbPortEjections are the ejections of a host port, for the cluster without subset and summed over the subsets
+type cbPortEjections struct {
+	all     float64
+	subsets float64
+}
+
+// ejectedHosts returns the ejected endpoints of the host. The cluster without subset holds every endpoint of the
+// port, the subsets hold disjoint parts of them.
+func (s *cbState) ejectedHosts() float64 {
+	ejections := 0.0
+	for _, port := range s.ejections {
+		ejections = math.Max(ejections, math.Max(port.all, port.subsets))
+	}
+	return ejections
+}
+
+// Name implements Appender
+func (a CircuitBreakerStateAppender) Name() string {
+	return CircuitBreakerStateAppenderName
+}
+
+// AppendGraph implements Appender
+func (a CircuitBreakerStateAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a CircuitBreakerStateAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	log.Tracef("Generating circuitBreakerState; namespace = %v", namespace)
+
+	duration := a.Namespaces[namespace].Duration
+	namespaceLabel, appLabel, versionLabel := envoyStatsLabels()
+	selector := fmt.Sprintf(`%s="%s",cluster_name=~"outbound\\|.*"`, namespaceLabel, namespace)
+	groupBy := fmt.Sprintf("%s,%s,%s,cluster_name", namespaceLabel, appLabel, versionLabel)
+
+	// query prometheus for the state reported by the namespace proxies, in a single query, each result labeled
+	// with its state value. The ejections are the maximum reported by the proxies of an app version, the overflow
+	// is their sum.
+	query := fmt.Sprintf(`label_replace(max(%s{%s}) by (%s) > 0, "%s", "%s", "", ".*") or label_replace(sum(rate(%s{%s}[%vs])) by (%s) > 0, "%s", "%s", "", ".*")`,
+		"envoy_cluster_outlier_detection_ejections_active",
+		selector,
+		groupBy,
+		cbStateLabel,
+		cbStateEjections,
+		"envoy_cluster_upstream_rq_pending_overflow",
+		selector,
+		int(duration.Seconds()), // range duration for the query
+		groupBy,
+		cbStateLabel,
+		cbStateOverflow)
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	states := populateCBStates(&vector, namespaceLabel, appLabel, versionLabel)
+
+	applyCBStates(trafficMap, namespace, states)
+}
+
+// envoyStatsLabels returns the names of the labels identifying the proxy of an Envoy stat, the same as for the
+// Envoy custom dashboard
+func envoyStatsLabels() (namespaceLabel, appLabel, versionLabel string) {
+	cfg := config.Get()
+	namespaceLabel = cfg.ExternalServices.CustomDashboards.NamespaceLabel
+	if namespaceLabel == "" {
+		namespaceLabel = "kubernetes_namespace"
+	}
+	appLabel = prometheus.SanitizeLabelName(cfg.IstioLabels.AppLabelName)
+	versionLabel = prometheus.SanitizeLabelName(cfg.IstioLabels.VersionLabelName)
+	return namespaceLabel, appLabel, versionLabel
+}
+
+func populateCBStates(vector *model.Vector, namespaceLabel, appLabel, versionLabel string) map[string]*cbState {
+	states := make(map[string]*cbState)
+	for _, s := range *vector {
+		m := s.Metric
+		lState, stateOk := m[cbStateLabel]
+		lNamespace, namespaceOk := m[model.LabelName(namespaceLabel)]
+		lApp, appOk := m[model.LabelName(appLabel)]
+		lVersion := m[model.LabelName(versionLabel)]
+		lClusterName, clusterNameOk := m["cluster_name"]
+
+		if !stateOk || !namespaceOk || !appOk || !clusterNameOk {
+			log.Warningf("populateCBStates: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		// cluster_name is direction|port|subset|host
+		clusterName := strings.Split(string(lClusterName), "|")
+		if len(clusterName) != 4 {
+			log.Warningf("populateCBStates: Skipping %s, unexpected cluster_name", m.String())
+			continue
+		}
+
+		val := float64(s.Value)
+
+		// Should not happen but if NaN for any reason, Just skip it
+		if math.IsNaN(val) {
+			continue
+		}
+
+		key := fmt.Sprintf("%s %s %s %s", lNamespace, lApp, lVersion, clusterName[3])
+		state, ok := states[key]
+		if !ok {
+			state = &cbState{
+				app:       string(lApp),
+				ejections: make(map[string]*cbPortEjections),
+				host:      clusterName[3],
+				namespace: string(lNamespace),
+				version:   string(lVersion),
+			}
+			states[key] = state
+		}
+		switch string(lState) {
+		case cbStateEjections:
+			port, ok := state.ejections[clusterName[1]]
+			if !ok {
+				port = &cbPortEjections{}
+				state.ejections[clusterName[1]] = port
+			}
+			if clusterName[2] == "" {
+				port.all += val
+			} else {
+				port.subsets += val
+			}
+		case cbStateOverflow:
+			state.overflow += val
+		}
+	}
+	return states
+}
+
+func applyCBStates(trafficMap graph.TrafficMap, namespace string, states map[string]*cbState) {
+	for _, n := range trafficMap {
+		// the state is reported by the source proxies, limit the edges to sources in the namespace
+		if n.Namespace != namespace || (n.NodeType != graph.NodeTypeApp && n.NodeType != graph.NodeTypeWorkload) || !graph.IsOK(n.App) {
+			continue
+		}
+		for _, e := range n.Edges {
+			// the ejections of the destination, per source version
+			versionEjections := make(map[string]float64)
+			overflow := 0.0
+			destServices := edgeDestServices(e)
+			for _, state := range states {
+				if state.namespace != n.Namespace || state.app != n.App || (graph.IsOK(n.Version) && state.version != n.Version) {
+					continue
+				}
+				for _, ds := range destServices {
+					if isServiceHost(state.host, ds) {
+						versionEjections[state.version] = math.Max(versionEjections[state.version], state.ejectedHosts())
+						overflow += state.overflow
+						break
+					}
+				}
+			}
+			ejections := 0.0
+			for _, versionEjections := range versionEjections {
+				ejections = math.Max(ejections, versionEjections)
+			}
+			if ejections > 0.0 || overflow > 0.0 {
+				e.Metadata[graph.CBEjections] = ejections
+				e.Metadata[graph.CBOverflow] = overflow
+				e.Metadata[graph.IsCBTripped] = true
+			}
+		}
+	}
+
+	// the nodes report the state of all of their incoming edges, some of which may have been set for another
+	// namespace, so the node state is recalculated from scratch
+	for _, n := range trafficMap {
+		delete(n.Metadata, graph.CBEjections)
+		delete(n.Metadata, graph.CBOverflow)
+		delete(n.Metadata, graph.IsCBTripped)
+	}
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			if _, ok := e.Metadata[graph.IsCBTripped]; !ok {
+				continue
+			}
+			ejections := e.Metadata[graph.CBEjections].(float64)
+			overflow := e.Metadata[graph.CBOverflow].(float64)
+			if current, ok := e.Dest.Metadata[graph.CBEjections]; ok {
+				ejections = math.Max(ejections, current.(float64))
+				overflow += e.Dest.Metadata[graph.CBOverflow].(float64)
+			}
+			e.Dest.Metadata[graph.CBEjections] = ejections
+			e.Dest.Metadata[graph.CBOverflow] = overflow
+			e.Dest.Metadata[graph.IsCBTripped] = true
+		}
+	}
+}
+
+// edgeDestServices returns the services of the edge destination
+func edgeDestServices(e *graph.Edge) []graph.ServiceName {
+	if e.Dest.NodeType == graph.NodeTypeService {
+		return []graph.ServiceName{{Cluster: e.Dest.Cluster, Namespace: e.Dest.Namespace, Name: e.Dest.Service}}
+	}
+	destServices := []graph.ServiceName{}
+	if val, ok := e.Dest.Metadata[graph.DestServices]; ok {
+		for _, ds := range val.(graph.DestServicesMetadata) {
+			destServices = append(destServices, ds)
+		}
+	}
+	return destServices
+}
+
+// isServiceHost returns true if the host is the service FQDN, or the service name itself (e.g. a ServiceEntry host)
+func isServiceHost(host string, service graph.ServiceName) bool {
+	return host == service.Name || strings.HasPrefix(host, fmt.Sprintf("%s.%s.", service.Name, service.Namespace))
+}
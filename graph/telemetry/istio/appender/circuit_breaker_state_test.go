@@ -0,0 +1,151 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestCircuitBreakerState(t *testing.T) {
+	assert := assert.New(t)
+
+	q0 := `round(label_replace(max(envoy_cluster_outlier_detection_ejections_active{kubernetes_namespace="bookinfo",cluster_name=~"outbound\\|.*"}) by (kubernetes_namespace,app,version,cluster_name) > 0, "kiali_cb_state", "ejections", "", ".*") or label_replace(sum(rate(envoy_cluster_upstream_rq_pending_overflow{kubernetes_namespace="bookinfo",cluster_name=~"outbound\\|.*"}[60s])) by (kubernetes_namespace,app,version,cluster_name) > 0, "kiali_cb_state", "overflow", "", ".*"),0.001)`
+	v0 := model.Vector{
+		&model.Sample{
+			Metric: cbStateTestMetric("ejections", "productpage", "v1", "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"),
+			Value:  1.0},
+		&model.Sample{
+			Metric: cbStateTestMetric("ejections", "productpage", "v1", "outbound|9080|v2|reviews.bookinfo.svc.cluster.local"),
+			Value:  2.0},
+		&model.Sample{
+			Metric: cbStateTestMetric("ejections", "productpage", "v1", "outbound|9090|v2|reviews.bookinfo.svc.cluster.local"),
+			Value:  1.0},
+		&model.Sample{
+			Metric: cbStateTestMetric("overflow", "productpage", "v1", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  0.5},
+		&model.Sample{
+			Metric: cbStateTestMetric("overflow", "productpage", "v1", "outbound|9090|v2|reviews.bookinfo.svc.cluster.local"),
+			Value:  0.25},
+		&model.Sample{
+			Metric: cbStateTestMetric("ejections", "reviews", "v2", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  3.0},
+		&model.Sample{
+			Metric: cbStateTestMetric("overflow", "productpage", "v1", "unexpected"),
+			Value:  1.0}}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQuery(api, q0, &v0)
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviewsService := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "", "", "", "", graph.GraphTypeWorkload)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	details := graph.NewNode(graph.Unknown, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeWorkload)
+	details.Metadata[graph.DestServices] = graph.NewDestServicesMetadata().Add("bookinfo details", graph.ServiceName{Namespace: "bookinfo", Name: "details"})
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviewsService.ID] = &reviewsService
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[details.ID] = &details
+	productpage.AddEdge(&reviewsService)
+	productpage.AddEdge(&details)
+	reviewsService.AddEdge(&reviews)
+
+	duration, _ := time.ParseDuration("60s")
+	appender := CircuitBreakerStateAppender{
+		Namespaces: map[string]graph.NamespaceInfo{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	for _, e := range productpage.Edges {
+		switch e.Dest.ID {
+		case reviewsService.ID:
+			assert.Equal(true, e.Metadata[graph.IsCBTripped])
+			// the subset ejections are summed, the maximum over the ports is reported
+			assert.Equal(3.0, e.Metadata[graph.CBEjections])
+			assert.Equal(0.75, e.Metadata[graph.CBOverflow])
+		case details.ID:
+			assert.Equal(nil, e.Metadata[graph.IsCBTripped])
+		default:
+			assert.Fail("Unexpected edge dest: " + e.Dest.ID)
+		}
+	}
+	// the service edge source is not a proxy
+	assert.Equal(nil, reviewsService.Edges[0].Metadata[graph.IsCBTripped])
+
+	assert.Equal(true, reviewsService.Metadata[graph.IsCBTripped])
+	assert.Equal(3.0, reviewsService.Metadata[graph.CBEjections])
+	assert.Equal(0.75, reviewsService.Metadata[graph.CBOverflow])
+	assert.Equal(nil, productpage.Metadata[graph.IsCBTripped])
+	assert.Equal(nil, details.Metadata[graph.IsCBTripped])
+	assert.Equal(nil, reviews.Metadata[graph.IsCBTripped])
+
+	// the node state is not accumulated when the appender runs for another namespace
+	appender.appendGraph(trafficMap, "bookinfo", client)
+	assert.Equal(0.75, reviewsService.Metadata[graph.CBOverflow])
+}
+
+func TestCircuitBreakerStateVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	v := model.Vector{
+		&model.Sample{
+			Metric: cbStateTestMetric("ejections", "productpage", "v1", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  2.0},
+		&model.Sample{
+			Metric: cbStateTestMetric("ejections", "productpage", "v2", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  1.0},
+		&model.Sample{
+			Metric: cbStateTestMetric("overflow", "productpage", "v1", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  0.5},
+		&model.Sample{
+			Metric: cbStateTestMetric("overflow", "productpage", "v2", "outbound|9080||reviews.bookinfo.svc.cluster.local"),
+			Value:  0.5}}
+	states := populateCBStates(&v, "kubernetes_namespace", "app", "version")
+
+	// in an app graph the versions are proxies of the same source, their ejections are not summed
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "", "productpage", "", graph.GraphTypeApp)
+	reviewsService := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "", "", "", "", graph.GraphTypeApp)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviewsService.ID] = &reviewsService
+	e := productpage.AddEdge(&reviewsService)
+
+	applyCBStates(trafficMap, "bookinfo", states)
+	assert.Equal(2.0, e.Metadata[graph.CBEjections])
+	assert.Equal(1.0, e.Metadata[graph.CBOverflow])
+}
+
+func TestIsServiceHost(t *testing.T) {
+	assert := assert.New(t)
+
+	reviews := graph.ServiceName{Namespace: "bookinfo", Name: "reviews"}
+	assert.True(isServiceHost("reviews.bookinfo.svc.cluster.local", reviews))
+	assert.True(isServiceHost("reviews", reviews))
+	assert.False(isServiceHost("reviews.tutorial.svc.cluster.local", reviews))
+	assert.False(isServiceHost("reviews2.bookinfo.svc.cluster.local", reviews))
+	assert.True(isServiceHost("www.google.com", graph.ServiceName{Namespace: "bookinfo", Name: "www.google.com"}))
+}
+
+func cbStateTestMetric(state, app, version, clusterName string) model.Metric {
+	return model.Metric{
+		"kiali_cb_state":       model.LabelValue(state),
+		"kubernetes_namespace": "bookinfo",
+		"app":                  model.LabelValue(app),
+		"version":              model.LabelValue(version),
+		"cluster_name":         model.LabelValue(clusterName)}
+}
@@ -0,0 +1,178 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// CustomMetricsAppenderName uniquely identifies the appender: customMetrics
+	CustomMetricsAppenderName = "customMetrics"
+
+	customMetricTargetEdge = "edge"
+	customMetricTargetNode = "node"
+)
+
+var customMetricPlaceholder = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// CustomMetricsAppender is responsible for reporting the metrics defined in the graph_custom_metrics config
+// on the nodes and edges (see config.GraphCustomMetric). Each query is evaluated, at queryTime, for every node
+// in the namespace, or every edge with a source node in the namespace, providing values for all of the query
+// placeholders. Identical queries are evaluated once.
+// Name: customMetrics
+type CustomMetricsAppender struct {
+	Metrics    []config.GraphCustomMetric
+	Namespaces graph.NamespaceInfoMap
+	QueryTime  int64 // unix time in seconds
+}
+
+// Name implements Appender
+func (a CustomMetricsAppender) Name() string {
+	return CustomMetricsAppenderName
+}
+
+// AppendGraph implements Appender
+func (a CustomMetricsAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 || len(a.Metrics) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a CustomMetricsAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	log.Tracef("Generating customMetrics; namespace = %v", namespace)
+
+	duration := fmt.Sprintf("%vs", int(a.Namespaces[namespace].Duration.Seconds()))
+
+	// query results by query, a query may apply to several nodes or edges (e.g. the app nodes of a versioned app graph)
+	values := make(map[string]*float64)
+	evaluate := func(template string, placeholders map[string]string) (float64, bool) {
+		query, ok := expandCustomMetricQuery(template, placeholders)
+		if !ok {
+			return 0.0, false
+		}
+		value, ok := values[query]
+		if !ok {
+			value = a.query(query, client)
+			values[query] = value
+		}
+		if value == nil {
+			return 0.0, false
+		}
+		return *value, true
+	}
+
+	for _, metric := range a.Metrics {
+		switch metric.Target {
+		case customMetricTargetNode:
+			for _, n := range trafficMap {
+				if n.Namespace != namespace {
+					continue
+				}
+				placeholders := customMetricNodePlaceholders(n, "")
+				placeholders["duration"] = duration
+				if value, ok := evaluate(metric.Query, placeholders); ok {
+					setCustomMetric(n.Metadata, metric.Key, value)
+				}
+			}
+		case customMetricTargetEdge:
+			for _, n := range trafficMap {
+				if n.Namespace != namespace {
+					continue
+				}
+				for _, e := range n.Edges {
+					placeholders := customMetricNodePlaceholders(n, "source_")
+					for k, v := range customMetricNodePlaceholders(e.Dest, "dest_") {
+						placeholders[k] = v
+					}
+					placeholders["duration"] = duration
+					if value, ok := evaluate(metric.Query, placeholders); ok {
+						setCustomMetric(e.Metadata, metric.Key, value)
+					}
+				}
+			}
+		default:
+			log.Warningf("Skipping graph custom metric [%s], invalid target [%s], expecting one of (node, edge)", metric.Key, metric.Target)
+		}
+	}
+}
+
+// query returns the sum of the query results, or nil if there are no results
+func (a CustomMetricsAppender) query(query string, client *prometheus.Client) *float64 {
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+
+	var result *float64
+	for _, s := range vector {
+		val := float64(s.Value)
+
+		// Should not happen but if NaN for any reason, Just skip it
+		if math.IsNaN(val) {
+			continue
+		}
+
+		if result == nil {
+			result = new(float64)
+		}
+		*result += val
+	}
+	return result
+}
+
+// customMetricNodePlaceholders returns the placeholder values of the node, unknown values are omitted
+func customMetricNodePlaceholders(n *graph.Node, prefix string) map[string]string {
+	placeholders := make(map[string]string)
+	if graph.IsOK(n.Namespace) {
+		placeholders[prefix+"namespace"] = n.Namespace
+	}
+	if graph.IsOK(n.Workload) {
+		placeholders[prefix+"workload"] = n.Workload
+	}
+	if graph.IsOK(n.App) {
+		placeholders[prefix+"app"] = n.App
+	}
+	if graph.IsOKVersion(n.Version) {
+		placeholders[prefix+"version"] = n.Version
+	}
+	if graph.IsOK(n.Service) {
+		placeholders[prefix+"service"] = n.Service
+	}
+	return placeholders
+}
+
+// expandCustomMetricQuery replaces the query placeholders, it returns false if a placeholder has no value
+func expandCustomMetricQuery(template string, placeholders map[string]string) (string, bool) {
+	ok := true
+	query := customMetricPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := strings.TrimSuffix(strings.TrimPrefix(placeholder, "${"), "}")
+		value, found := placeholders[name]
+		if !found {
+			ok = false
+		}
+		return value
+	})
+	return query, ok
+}
+
+func setCustomMetric(md graph.Metadata, key string, value float64) {
+	customMetrics, ok := md[graph.CustomMetrics]
+	if !ok {
+		customMetrics = make(map[string]float64)
+		md[graph.CustomMetrics] = customMetrics
+	}
+	customMetrics.(map[string]float64)[key] = value
+}
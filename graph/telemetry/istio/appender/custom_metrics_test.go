@@ -0,0 +1,109 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+)
+
+func TestCustomMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	q0 := `round(sum(rate(container_cpu_usage_seconds_total{namespace="bookinfo",pod=~"productpage-v1-.*"}[60s])),0.001)`
+	v0 := model.Vector{
+		&model.Sample{
+			Metric: model.Metric{"pod": "productpage-v1-6b746f74dc-9stvs"},
+			Value:  0.25},
+		&model.Sample{
+			Metric: model.Metric{"pod": "productpage-v1-6b746f74dc-xc8r2"},
+			Value:  0.5}}
+	q1 := `round(sum(rate(container_cpu_usage_seconds_total{namespace="bookinfo",pod=~"reviews-v1-.*"}[60s])),0.001)`
+	v1 := model.Vector{}
+	q2 := `round(sum(cache_hit_ratio{source="productpage",destination="reviews"}),0.001)`
+	v2 := model.Vector{
+		&model.Sample{
+			Metric: model.Metric{},
+			Value:  0.8}}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQuery(api, q0, &v0)
+	mockQuery(api, q1, &v1)
+	mockQuery(api, q2, &v2)
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviewsService := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "", "", "", "", graph.GraphTypeWorkload)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviewsService.ID] = &reviewsService
+	trafficMap[reviews.ID] = &reviews
+	productpage.AddEdge(&reviewsService)
+	reviewsService.AddEdge(&reviews)
+
+	duration, _ := time.ParseDuration("60s")
+	appender := CustomMetricsAppender{
+		Metrics: []config.GraphCustomMetric{
+			{
+				Key:    "cpu",
+				Query:  `sum(rate(container_cpu_usage_seconds_total{namespace="${namespace}",pod=~"${workload}-.*"}[${duration}]))`,
+				Target: "node",
+			},
+			{
+				Key:    "cacheHitRatio",
+				Query:  `sum(cache_hit_ratio{source="${source_app}",destination="${dest_service}"})`,
+				Target: "edge",
+			},
+			{
+				Key:    "invalid",
+				Query:  `sum(up)`,
+				Target: "box",
+			},
+		},
+		Namespaces: graph.NamespaceInfoMap{
+			"bookinfo": graph.NamespaceInfo{
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	assert.Equal(map[string]float64{"cpu": 0.75}, productpage.Metadata[graph.CustomMetrics])
+	// the query has no results
+	assert.Equal(nil, reviews.Metadata[graph.CustomMetrics])
+	// the service node has no workload
+	assert.Equal(nil, reviewsService.Metadata[graph.CustomMetrics])
+
+	assert.Equal(map[string]float64{"cacheHitRatio": 0.8}, productpage.Edges[0].Metadata[graph.CustomMetrics])
+	// the service node has no app
+	assert.Equal(nil, reviewsService.Edges[0].Metadata[graph.CustomMetrics])
+}
+
+func TestExpandCustomMetricQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	placeholders := map[string]string{"namespace": "bookinfo", "app": "reviews"}
+
+	query, ok := expandCustomMetricQuery(`queue_depth{namespace="${namespace}",app="${app}"}`, placeholders)
+	assert.True(ok)
+	assert.Equal(`queue_depth{namespace="bookinfo",app="reviews"}`, query)
+
+	_, ok = expandCustomMetricQuery(`queue_depth{namespace="${namespace}",workload="${workload}"}`, placeholders)
+	assert.False(ok)
+
+	// label_replace replacements are not placeholders
+	query, ok = expandCustomMetricQuery(`label_replace(up{app="${app}"}, "pod", "$1", "instance", "(.*)")`, placeholders)
+	assert.True(ok)
+	assert.Equal(`label_replace(up{app="reviews"}, "pod", "$1", "instance", "(.*)")`, query)
+}
@@ -0,0 +1,123 @@
+package appender
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+)
+
+const (
+	// ResponseFlagsAppenderName uniquely identifies the appender: responseFlags
+	ResponseFlagsAppenderName = "responseFlags"
+)
+
+// envoyFlagCategories maps the Envoy response flags to their category. Flags not listed here are not categorized.
+// See https://www.envoyproxy.io/docs/envoy/latest/configuration/observability/access_log/usage#config-access-log-format-response-flags
+var envoyFlagCategories = map[string]string{
+	"DC":   graph.FlagCategoryClientDisconnect,
+	"DI":   graph.FlagCategoryFaultInjected,
+	"FI":   graph.FlagCategoryFaultInjected,
+	"LR":   graph.FlagCategoryUpstreamFailure,
+	"NC":   graph.FlagCategoryNoRoute,
+	"NR":   graph.FlagCategoryNoRoute,
+	"RL":   graph.FlagCategoryRateLimited,
+	"RLSE": graph.FlagCategoryRateLimited,
+	"SI":   graph.FlagCategoryTimeout,
+	"UAEX": graph.FlagCategoryUnauthorized,
+	"UC":   graph.FlagCategoryUpstreamFailure,
+	"UF":   graph.FlagCategoryUpstreamFailure,
+	"UH":   graph.FlagCategoryNoHealthyUpstream,
+	"UO":   graph.FlagCategoryCircuitBreaker,
+	"UR":   graph.FlagCategoryUpstreamFailure,
+	"URX":  graph.FlagCategoryRetryLimit,
+	"UT":   graph.FlagCategoryTimeout,
+}
+
+// ResponseFlagsAppender is responsible for classifying the Envoy response flags reported for each edge. Each
+// edge is decorated with the percentage of its traffic in each flag category, and each node is decorated with
+// the flag categories reported for its incoming traffic.
+// Name: responseFlags
+type ResponseFlagsAppender struct{}
+
+// Name implements Appender
+func (a ResponseFlagsAppender) Name() string {
+	return ResponseFlagsAppenderName
+}
+
+// AppendGraph implements Appender
+func (a ResponseFlagsAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	a.applyResponseFlags(trafficMap)
+}
+
+func (a ResponseFlagsAppender) applyResponseFlags(trafficMap graph.TrafficMap) {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			categories := flagCategories(e.Metadata)
+			if len(categories) == 0 {
+				continue
+			}
+			e.Metadata[graph.FlagCategories] = categories
+			addFlagBadges(e.Dest.Metadata, categories)
+		}
+	}
+}
+
+// flagCategories returns the percentage of the edge traffic in each flag category, nil if no flags are categorized
+func flagCategories(md graph.Metadata) map[string]float64 {
+	var categories map[string]float64
+
+	for _, p := range graph.Protocols {
+		total, totalOk := md[graph.MetadataKey(p.Name)].(float64)
+		responses, responsesOk := md[p.EdgeResponses].(graph.Responses)
+		if !totalOk || !responsesOk || total <= 0.0 {
+			continue
+		}
+
+		for _, detail := range responses {
+			for flags, val := range detail.Flags {
+				for category := range flagsToCategories(flags) {
+					if categories == nil {
+						categories = make(map[string]float64)
+					}
+					categories[category] += val / total * 100.0
+				}
+			}
+		}
+	}
+
+	return categories
+}
+
+// flagsToCategories returns the distinct categories for a comma-separated list of flags
+func flagsToCategories(flags string) map[string]bool {
+	categories := make(map[string]bool)
+	for _, flag := range strings.Split(flags, ",") {
+		if category, ok := envoyFlagCategories[strings.TrimSpace(flag)]; ok {
+			categories[category] = true
+		}
+	}
+	return categories
+}
+
+func addFlagBadges(md graph.Metadata, categories map[string]float64) {
+	badges, _ := md[graph.FlagBadges].([]string)
+	for category := range categories {
+		found := false
+		for _, badge := range badges {
+			if badge == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			badges = append(badges, category)
+		}
+	}
+	sort.Strings(badges)
+	md[graph.FlagBadges] = badges
+}
@@ -0,0 +1,67 @@
+package appender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestResponseFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	details := graph.NewNode(graph.Unknown, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeVersionedApp)
+	ratings := graph.NewNode(graph.Unknown, "bookinfo", "ratings", "bookinfo", "ratings-v1", "ratings", "v1", graph.GraphTypeVersionedApp)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[details.ID] = &details
+	trafficMap[ratings.ID] = &ratings
+
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 70.0, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 10.0, "503", "UO", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 10.0, "503", "UF,URX", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 10.0, "504", "UT", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+
+	e = productpage.AddEdge(&details)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 100.0, "200", "-", "details", productpage.Metadata, details.Metadata, e.Metadata)
+
+	e = reviews.AddEdge(&ratings)
+	e.Metadata[graph.ProtocolKey] = "grpc"
+	graph.AddToMetadata("grpc", 8.0, "0", "-", "ratings", reviews.Metadata, ratings.Metadata, e.Metadata)
+	graph.AddToMetadata("grpc", 2.0, "14", "UO", "ratings", reviews.Metadata, ratings.Metadata, e.Metadata)
+
+	a := ResponseFlagsAppender{}
+	a.AppendGraph(trafficMap, nil, nil)
+
+	for _, e := range productpage.Edges {
+		switch e.Dest.ID {
+		case reviews.ID:
+			categories := e.Metadata[graph.FlagCategories].(map[string]float64)
+			assert.Equal(4, len(categories))
+			assert.Equal(10.0, categories[graph.FlagCategoryCircuitBreaker])
+			assert.Equal(10.0, categories[graph.FlagCategoryRetryLimit])
+			assert.Equal(10.0, categories[graph.FlagCategoryTimeout])
+			assert.Equal(10.0, categories[graph.FlagCategoryUpstreamFailure])
+		case details.ID:
+			assert.Nil(e.Metadata[graph.FlagCategories])
+		default:
+			assert.Fail("Unexpected edge")
+		}
+	}
+
+	categories := reviews.Edges[0].Metadata[graph.FlagCategories].(map[string]float64)
+	assert.Equal(1, len(categories))
+	assert.Equal(20.0, categories[graph.FlagCategoryCircuitBreaker])
+
+	assert.Nil(productpage.Metadata[graph.FlagBadges])
+	assert.Nil(details.Metadata[graph.FlagBadges])
+	assert.Equal([]string{graph.FlagCategoryCircuitBreaker}, ratings.Metadata[graph.FlagBadges])
+	assert.Equal([]string{graph.FlagCategoryCircuitBreaker, graph.FlagCategoryRetryLimit, graph.FlagCategoryTimeout, graph.FlagCategoryUpstreamFailure}, reviews.Metadata[graph.FlagBadges])
+}
@@ -0,0 +1,355 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// SparklinesAppenderName uniquely identifies the appender: sparklines
+	SparklinesAppenderName = "sparklines"
+
+	minSparklineRateInterval = time.Minute // protects against rates calculated from too few samples
+)
+
+// SparklinesAppender is responsible for adding, to each HTTP and GRPC edge, the time series of its request rate,
+// error percentage and response time over the requested duration. The series have a value for every Step, each
+// value calculated over the preceding Step (or a minute, if longer). Response time uses the same quantile as the
+// responseTime appender. Because the series require range queries, the appender runs only when explicitly
+// requested.
+// Name: sparklines
+type SparklinesAppender struct {
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	Quantile           float64
+	QueryTime          int64         // unix time in seconds
+	Step               time.Duration // time between series values
+}
+
+// sparklineSeries holds, for a single key, the metadata for each series time (unix time in seconds)
+type sparklineSeries map[int64]graph.Metadata
+
+// Name implements Appender
+func (a SparklinesAppender) Name() string {
+	return SparklinesAppenderName
+}
+
+// AppendGraph implements Appender
+func (a SparklinesAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a SparklinesAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	log.Tracef("Generating sparklines with step [%v]; namespace = %v", a.Step, namespace)
+
+	queryRange := a.queryRange(a.Namespaces[namespace].Duration)
+	rateInterval := a.Step
+	if rateInterval < minSparklineRateInterval {
+		rateInterval = minSparklineRateInterval
+	}
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision"
+
+	// key=edgeKey, the request traffic metadata for each series time
+	rateMap := make(map[string]sparklineSeries)
+	// key=edgeKey, the response time for each series time (stored as metadata to share the map type), for a
+	// given edge and time the first reported value is preferred (i.e. defer to query order)
+	responseTimeMap := make(map[string]sparklineSeries)
+
+	// query prometheus for the request traffic in two queries, the same as for the responseTime appender, the
+	// query order is important as both queries may have overlapping results for edges within the namespace.
+	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
+	query := fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"}[%vs])) by (%s,request_protocol,response_code,grpc_response_status) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(rateInterval.Seconds()), // range duration for the query
+		groupBy)
+	matrix := promQueryRange(query, queryRange, client.GetContext(), client.API(), a)
+	incoming := make(map[string]sparklineSeries)
+	a.populateSeriesMap(incoming, &matrix, true)
+
+	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
+	query = fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"}[%vs])) by (%s,request_protocol,response_code,grpc_response_status) > 0`,
+		"istio_requests_total",
+		namespace,
+		int(rateInterval.Seconds()), // range duration for the query
+		groupBy)
+	matrix = promQueryRange(query, queryRange, client.GetContext(), client.API(), a)
+	outgoing := make(map[string]sparklineSeries)
+	a.populateSeriesMap(outgoing, &matrix, true)
+	mergeSeriesMaps(rateMap, incoming, outgoing)
+
+	// query prometheus for the response time in two queries, in the same order
+	for _, reporter := range []string{"destination", "source"} {
+		namespaceLabel := "destination_service_namespace"
+		if reporter == "source" {
+			namespaceLabel = "source_workload_namespace"
+		}
+		if a.Quantile == 0.0 {
+			query = fmt.Sprintf(`sum(rate(%s{reporter="%s",%s="%s"}[%vs])) by (%s) / sum(rate(%s{reporter="%s",%s="%s"}[%vs])) by (%s) > 0`,
+				"istio_request_duration_milliseconds_sum",
+				reporter,
+				namespaceLabel,
+				namespace,
+				int(rateInterval.Seconds()), // range duration for the query
+				groupBy,
+				"istio_request_duration_milliseconds_count",
+				reporter,
+				namespaceLabel,
+				namespace,
+				int(rateInterval.Seconds()), // range duration for the query
+				groupBy)
+		} else {
+			query = fmt.Sprintf(`histogram_quantile(%.2f, sum(rate(%s{reporter="%s",%s="%s"}[%vs])) by (le,%s)) > 0`,
+				a.Quantile,
+				"istio_request_duration_milliseconds_bucket",
+				reporter,
+				namespaceLabel,
+				namespace,
+				int(rateInterval.Seconds()), // range duration for the query
+				groupBy)
+		}
+		matrix = promQueryRange(query, queryRange, client.GetContext(), client.API(), a)
+		a.populateSeriesMap(responseTimeMap, &matrix, false)
+	}
+
+	a.applySparklines(trafficMap, queryRange, rateMap, responseTimeMap)
+}
+
+// mergeSeriesMaps adds the results of each query to the series map, for a given key and time the first
+// reported value is preferred (i.e. defer to query order)
+func mergeSeriesMaps(seriesMap map[string]sparklineSeries, queryMaps ...map[string]sparklineSeries) {
+	for _, queryMap := range queryMaps {
+		for key, series := range queryMap {
+			if _, ok := seriesMap[key]; !ok {
+				seriesMap[key] = sparklineSeries{}
+			}
+			for t, md := range series {
+				if _, ok := seriesMap[key][t]; !ok {
+					seriesMap[key][t] = md
+				}
+			}
+		}
+	}
+}
+
+// queryRange returns the range ending at QueryTime, with a value for every Step of the duration
+func (a SparklinesAppender) queryRange(duration time.Duration) prom_v1.Range {
+	points := int64(duration / a.Step)
+	if points < 1 {
+		points = 1
+	}
+	end := time.Unix(a.QueryTime, 0)
+	return prom_v1.Range{
+		Start: end.Add(-time.Duration(points-1) * a.Step),
+		End:   end,
+		Step:  a.Step,
+	}
+}
+
+func (a SparklinesAppender) populateSeriesMap(seriesMap map[string]sparklineSeries, matrix *model.Matrix, isRequests bool) {
+	for _, s := range *matrix {
+		m := s.Metric
+		lSourceCluster, sourceClusterOk := m["source_cluster"]
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lDestCluster, destClusterOk := m["destination_cluster"]
+		lDestSvcNs, destSvcNsOk := m["destination_service_namespace"]
+		lDestSvc, destSvcOk := m["destination_service"]
+		lDestSvcName, destSvcNameOk := m["destination_service_name"]
+		lDestWlNs, destWlNsOk := m["destination_workload_namespace"]
+		lDestWl, destWlOk := m["destination_workload"]
+		lDestApp, destAppOk := m["destination_canonical_service"]
+		lDestVer, destVerOk := m["destination_canonical_revision"]
+
+		if !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcNameOk || !destSvcOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk {
+			log.Warningf("populateSeriesMap: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		sourceWlNs := string(lSourceWlNs)
+		sourceWl := string(lSourceWl)
+		sourceApp := string(lSourceApp)
+		sourceVer := string(lSourceVer)
+		destSvc := string(lDestSvc)
+
+		// handle clusters
+		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
+
+		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+			continue
+		}
+
+		// handle unusual destinations
+		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
+
+		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+			continue
+		}
+
+		// don't inject a service node if destSvcName is not set or the dest node is already a service node.
+		inject := false
+		if a.InjectServiceNodes && graph.IsOK(destSvcName) {
+			_, destNodeType := graph.Id(destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, a.GraphType)
+			inject = (graph.NodeTypeService != destNodeType)
+		}
+
+		if !isRequests {
+			for _, v := range s.Values {
+				val := float64(v.Value)
+				// Should not happen but if NaN for any reason, Just skip it
+				if math.IsNaN(val) {
+					continue
+				}
+				// Only set response time on the outgoing edge. On the incoming edge, we can't validly aggregate response times of the outgoing edges (kiali-2297)
+				if inject {
+					a.addSeriesResponseTime(seriesMap, v.Timestamp.Unix(), val, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+				} else {
+					a.addSeriesResponseTime(seriesMap, v.Timestamp.Unix(), val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+				}
+			}
+			continue
+		}
+
+		lProtocol, protocolOk := m["request_protocol"]
+		lCode, codeOk := m["response_code"]
+		lGrpc, grpcOk := m["grpc_response_status"]
+		if !protocolOk || !codeOk {
+			log.Warningf("populateSeriesMap: Skipping %s, missing expected HTTP/GRPC labels", m.String())
+			continue
+		}
+		protocol := string(lProtocol)
+		code := util.HandleResponseCode(protocol, string(lCode), grpcOk, string(lGrpc))
+
+		for _, v := range s.Values {
+			val := float64(v.Value)
+			// Should not happen but if NaN for any reason, Just skip it
+			if math.IsNaN(val) {
+				continue
+			}
+			if inject {
+				a.addSeriesTraffic(seriesMap, v.Timestamp.Unix(), protocol, code, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, "", "", "", "")
+				a.addSeriesTraffic(seriesMap, v.Timestamp.Unix(), protocol, code, val, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+			} else {
+				a.addSeriesTraffic(seriesMap, v.Timestamp.Unix(), protocol, code, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+			}
+		}
+	}
+}
+
+func (a SparklinesAppender) addSeriesTraffic(seriesMap map[string]sparklineSeries, t int64, protocol, code string, val float64, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s %s", sourceID, destID, protocol)
+
+	series, ok := seriesMap[key]
+	if !ok {
+		series = sparklineSeries{}
+		seriesMap[key] = series
+	}
+	md, ok := series[t]
+	if !ok {
+		md = graph.NewMetadata()
+		series[t] = md
+	}
+	graph.AddToMetadata(protocol, val, code, "", "", nil, nil, md)
+}
+
+func (a SparklinesAppender) addSeriesResponseTime(seriesMap map[string]sparklineSeries, t int64, val float64, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s", sourceID, destID)
+
+	series, ok := seriesMap[key]
+	if !ok {
+		series = sparklineSeries{}
+		seriesMap[key] = series
+	}
+	if _, found := series[t]; !found {
+		series[t] = graph.Metadata{graph.ResponseTime: val}
+	}
+}
+
+func (a SparklinesAppender) applySparklines(trafficMap graph.TrafficMap, queryRange prom_v1.Range, rateMap, responseTimeMap map[string]sparklineSeries) {
+	start := queryRange.Start.Unix()
+	end := queryRange.End.Unix()
+	step := int64(queryRange.Step.Seconds())
+	points := int((end-start)/step) + 1
+
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			protocol, ok := e.Metadata[graph.ProtocolKey].(string)
+			if !ok || (protocol != graph.HTTP.Name && protocol != graph.GRPC.Name) {
+				continue
+			}
+			rates, ok := rateMap[fmt.Sprintf("%s %s %s", e.Source.ID, e.Dest.ID, protocol)]
+			if !ok {
+				continue
+			}
+			responseTimes := responseTimeMap[fmt.Sprintf("%s %s", e.Source.ID, e.Dest.ID)]
+
+			sparklines := &graph.SparklinesInfo{
+				End:          end,
+				ErrorRate:    make([]float64, points),
+				RequestRate:  make([]float64, points),
+				ResponseTime: make([]float64, points),
+				Start:        start,
+				Step:         step,
+			}
+			for i := 0; i < points; i++ {
+				t := start + int64(i)*step
+				if md, ok := rates[t]; ok {
+					rate, errorRate := requestRates(md)
+					sparklines.RequestRate[i] = rate
+					sparklines.ErrorRate[i] = math.Round(errorRate*100.0) / 100.0
+				}
+				if md, ok := responseTimes[t]; ok && sparklines.RequestRate[i] > 0.0 {
+					sparklines.ResponseTime[i] = md[graph.ResponseTime].(float64)
+				}
+			}
+			e.Metadata[graph.Sparklines] = sparklines
+		}
+	}
+}
+
+// parseSparklineStep returns the step for the sparklineStep query param, by default the duration is divided
+// into defaultSparklinePoints steps
+func parseSparklineStep(stepString string, duration time.Duration) time.Duration {
+	if stepString == "" {
+		step := (duration / defaultSparklinePoints).Truncate(time.Second)
+		if step < time.Second {
+			step = time.Second
+		}
+		return step
+	}
+
+	step, err := time.ParseDuration(stepString)
+	if err != nil || step < time.Second || step%time.Second != 0 {
+		graph.BadRequest(fmt.Sprintf("Invalid sparklineStep, must be a whole number of seconds, at least 1s: [%s]", stepString))
+	}
+	if duration/step > maxSparklinePoints {
+		graph.BadRequest(fmt.Sprintf("Invalid sparklineStep, must not produce more than %d values for the duration: [%s]", maxSparklinePoints, stepString))
+	}
+	return step
+}
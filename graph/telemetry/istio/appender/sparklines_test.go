@@ -0,0 +1,143 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestSparklines(t *testing.T) {
+	assert := assert.New(t)
+
+	queryTime := int64(1600000000)
+	t0 := model.TimeFromUnix(queryTime - 60)
+	t1 := model.TimeFromUnix(queryTime)
+
+	q0 := `round(sum(rate(istio_requests_total{reporter="destination",destination_service_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status) > 0,0.001)`
+	m0 := sparklinesTestMetric("reviews")
+	m0["request_protocol"] = "http"
+	m0["response_code"] = "200"
+	m1 := sparklinesTestMetric("reviews")
+	m1["request_protocol"] = "http"
+	m1["response_code"] = "500"
+	v0 := model.Matrix{
+		&model.SampleStream{
+			Metric: m0,
+			Values: []model.SamplePair{{Timestamp: t0, Value: 10.0}, {Timestamp: t1, Value: 6.0}}},
+		&model.SampleStream{
+			Metric: m1,
+			Values: []model.SamplePair{{Timestamp: t1, Value: 2.0}}}}
+
+	// the outgoing query reports the same edge, the incoming (destination) values are preferred
+	q1 := `round(sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol,response_code,grpc_response_status) > 0,0.001)`
+	v1 := model.Matrix{
+		&model.SampleStream{
+			Metric: m0,
+			Values: []model.SamplePair{{Timestamp: t0, Value: 100.0}, {Timestamp: t1, Value: 100.0}}}}
+
+	q2 := `round(histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{reporter="destination",destination_service_namespace="bookinfo"}[60s])) by (le,source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision)) > 0,0.001)`
+	v2 := model.Matrix{
+		&model.SampleStream{
+			Metric: sparklinesTestMetric("reviews"),
+			Values: []model.SamplePair{{Timestamp: t0, Value: 20.0}, {Timestamp: t1, Value: 40.0}}}}
+
+	q3 := `round(histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{reporter="source",source_workload_namespace="bookinfo"}[60s])) by (le,source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision)) > 0,0.001)`
+	v3 := model.Matrix{}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQueryRange(api, q0, &v0)
+	mockQueryRange(api, q1, &v1)
+	mockQueryRange(api, q2, &v2)
+	mockQueryRange(api, q3, &v3)
+
+	trafficMap := graph.NewTrafficMap()
+	productpage := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews := graph.NewNode(graph.Unknown, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeWorkload)
+	details := graph.NewNode(graph.Unknown, "bookinfo", "details", "bookinfo", "details-v1", "details", "v1", graph.GraphTypeWorkload)
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[details.ID] = &details
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	e = productpage.AddEdge(&details)
+	e.Metadata[graph.ProtocolKey] = "http"
+
+	duration, _ := time.ParseDuration("120s")
+	appender := SparklinesAppender{
+		GraphType: graph.GraphTypeWorkload,
+		Namespaces: map[string]graph.NamespaceInfo{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		Quantile:  defaultQuantile,
+		QueryTime: queryTime,
+		Step:      time.Minute,
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	assert.Equal(2, len(productpage.Edges))
+	for _, e := range productpage.Edges {
+		switch e.Dest.ID {
+		case reviews.ID:
+			sparklines := e.Metadata[graph.Sparklines].(*graph.SparklinesInfo)
+			assert.Equal(queryTime-60, sparklines.Start)
+			assert.Equal(queryTime, sparklines.End)
+			assert.Equal(int64(60), sparklines.Step)
+			assert.Equal([]float64{10.0, 8.0}, sparklines.RequestRate)
+			assert.Equal([]float64{0.0, 25.0}, sparklines.ErrorRate)
+			assert.Equal([]float64{20.0, 40.0}, sparklines.ResponseTime)
+		case details.ID:
+			assert.Equal(nil, e.Metadata[graph.Sparklines])
+		default:
+			assert.Fail("Unexpected edge dest: " + e.Dest.ID)
+		}
+	}
+}
+
+func TestParseSparklineStep(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(20*time.Second, parseSparklineStep("", 10*time.Minute))
+	assert.Equal(time.Second, parseSparklineStep("", 10*time.Second))
+	assert.Equal(time.Minute, parseSparklineStep("1m", 10*time.Minute))
+	assert.Panics(func() { parseSparklineStep("foo", 10*time.Minute) })
+	assert.Panics(func() { parseSparklineStep("500ms", 10*time.Minute) })
+	assert.Panics(func() { parseSparklineStep("1s", 10*time.Minute) })
+}
+
+func sparklinesTestMetric(destApp string) model.Metric {
+	return model.Metric{
+		"source_workload_namespace":      "bookinfo",
+		"source_workload":                "productpage-v1",
+		"source_canonical_service":       "productpage",
+		"source_canonical_revision":      "v1",
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            model.LabelValue(destApp + ".bookinfo.svc.cluster.local"),
+		"destination_service_name":       model.LabelValue(destApp),
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           model.LabelValue(destApp + "-v1"),
+		"destination_canonical_service":  model.LabelValue(destApp),
+		"destination_canonical_revision": "v1"}
+}
+
+func mockQueryRange(api *prometheustest.PromAPIMock, query string, ret *model.Matrix) {
+	api.On(
+		"QueryRange",
+		mock.AnythingOfType("*context.emptyCtx"),
+		query,
+		mock.AnythingOfType("v1.Range"),
+	).Return(*ret, nil)
+}
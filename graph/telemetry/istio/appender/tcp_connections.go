@@ -0,0 +1,233 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// TCPConnectionsAppenderName uniquely identifies the appender: tcpConnections
+	TCPConnectionsAppenderName = "tcpConnections"
+
+	tcpConnectionsLabel = "kiali_tcp_connections"
+)
+
+// The values reported by the tcpConnections query, identified by the tcpConnectionsLabel
+const (
+	tcpConnectionsClosed      = "closed"      // connections closed per second
+	tcpConnectionsClosedTotal = "closedTotal" // connections closed since the proxy started
+	tcpConnectionsOpened      = "opened"      // connections opened per second
+	tcpConnectionsOpenedTotal = "openedTotal" // connections opened since the proxy started
+)
+
+// TCPConnectionsAppender is responsible for adding TCP connection information to the TCP edges, and the
+// incoming TCP connection information to the nodes. The information is the rate of connections opened and
+// closed over the requested duration, and an estimate of the connections currently open. The estimate is
+// the difference between the opened and closed connection totals reported by the proxies, at queryTime.
+// Name: tcpConnections
+type TCPConnectionsAppender struct {
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	QueryTime          int64 // unix time in seconds
+}
+
+// tcpConnections holds the values reported for a single key
+type tcpConnections map[string]float64
+
+// Name implements Appender
+func (a TCPConnectionsAppender) Name() string {
+	return TCPConnectionsAppenderName
+}
+
+// AppendGraph implements Appender
+func (a TCPConnectionsAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a TCPConnectionsAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	log.Tracef("Generating tcpConnections; namespace = %v", namespace)
+
+	duration := a.Namespaces[namespace].Duration
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision"
+
+	// key=edgeKey, the connection values for the edge
+	connectionsMap := make(map[string]tcpConnections)
+
+	// query prometheus for the connection info in two queries, the same as for the TCP traffic. The query order
+	// is important as both queries may have overlapping results for edges within the namespace.
+	// 1) Incoming: query destination telemetry to capture namespace workloads' incoming traffic
+	query := a.connectionsQuery(fmt.Sprintf(`reporter="destination",destination_workload_namespace="%s"`, namespace), duration, groupBy)
+	vector := promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	incoming := make(map[string]tcpConnections)
+	a.populateConnectionsMap(incoming, &vector)
+
+	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
+	query = a.connectionsQuery(fmt.Sprintf(`reporter="source",source_workload_namespace="%s"`, namespace), duration, groupBy)
+	vector = promQuery(query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+	outgoing := make(map[string]tcpConnections)
+	a.populateConnectionsMap(outgoing, &vector)
+
+	for _, queryMap := range []map[string]tcpConnections{incoming, outgoing} {
+		for key, connections := range queryMap {
+			if _, found := connectionsMap[key]; !found {
+				connectionsMap[key] = connections
+			}
+		}
+	}
+
+	applyTCPConnections(trafficMap, connectionsMap)
+}
+
+// connectionsQuery returns a single query for all of the connection values, each result labeled with its value name
+func (a TCPConnectionsAppender) connectionsQuery(selector string, duration time.Duration, groupBy string) string {
+	queries := []string{
+		fmt.Sprintf(`label_replace(sum(rate(%s{%s}[%vs])) by (%s), "%s", "%s", "", ".*")`,
+			"istio_tcp_connections_opened_total",
+			selector,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			tcpConnectionsLabel,
+			tcpConnectionsOpened),
+		fmt.Sprintf(`label_replace(sum(rate(%s{%s}[%vs])) by (%s), "%s", "%s", "", ".*")`,
+			"istio_tcp_connections_closed_total",
+			selector,
+			int(duration.Seconds()), // range duration for the query
+			groupBy,
+			tcpConnectionsLabel,
+			tcpConnectionsClosed),
+		fmt.Sprintf(`label_replace(sum(%s{%s}) by (%s), "%s", "%s", "", ".*")`,
+			"istio_tcp_connections_opened_total",
+			selector,
+			groupBy,
+			tcpConnectionsLabel,
+			tcpConnectionsOpenedTotal),
+		fmt.Sprintf(`label_replace(sum(%s{%s}) by (%s), "%s", "%s", "", ".*")`,
+			"istio_tcp_connections_closed_total",
+			selector,
+			groupBy,
+			tcpConnectionsLabel,
+			tcpConnectionsClosedTotal),
+	}
+	return strings.Join(queries, " or ")
+}
+
+func (a TCPConnectionsAppender) populateConnectionsMap(connectionsMap map[string]tcpConnections, vector *model.Vector) {
+	for _, s := range *vector {
+		m := s.Metric
+		lValueName, valueNameOk := m[tcpConnectionsLabel]
+		lSourceCluster, sourceClusterOk := m["source_cluster"]
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lDestCluster, destClusterOk := m["destination_cluster"]
+		lDestSvcNs, destSvcNsOk := m["destination_service_namespace"]
+		lDestSvc, destSvcOk := m["destination_service"]
+		lDestSvcName, destSvcNameOk := m["destination_service_name"]
+		lDestWlNs, destWlNsOk := m["destination_workload_namespace"]
+		lDestWl, destWlOk := m["destination_workload"]
+		lDestApp, destAppOk := m["destination_canonical_service"]
+		lDestVer, destVerOk := m["destination_canonical_revision"]
+
+		if !valueNameOk || !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcNameOk || !destSvcOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk {
+			log.Warningf("populateConnectionsMap: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		valueName := string(lValueName)
+		sourceWlNs := string(lSourceWlNs)
+		sourceWl := string(lSourceWl)
+		sourceApp := string(lSourceApp)
+		sourceVer := string(lSourceVer)
+		destSvc := string(lDestSvc)
+
+		// handle clusters
+		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
+
+		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+			continue
+		}
+
+		val := float64(s.Value)
+
+		// handle unusual destinations
+		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
+
+		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+			continue
+		}
+
+		// Should not happen but if NaN for any reason, Just skip it
+		if math.IsNaN(val) {
+			continue
+		}
+
+		// don't inject a service node if destSvcName is not set or the dest node is already a service node.
+		inject := false
+		if a.InjectServiceNodes && graph.IsOK(destSvcName) {
+			_, destNodeType := graph.Id(destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, a.GraphType)
+			inject = (graph.NodeTypeService != destNodeType)
+		}
+
+		if inject {
+			a.addConnections(connectionsMap, valueName, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, "", "", "", "")
+			a.addConnections(connectionsMap, valueName, val, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		} else {
+			a.addConnections(connectionsMap, valueName, val, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		}
+	}
+}
+
+func (a TCPConnectionsAppender) addConnections(connectionsMap map[string]tcpConnections, valueName string, val float64, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s", sourceID, destID)
+
+	connections, ok := connectionsMap[key]
+	if !ok {
+		connections = tcpConnections{}
+		connectionsMap[key] = connections
+	}
+	connections[valueName] += val
+}
+
+func applyTCPConnections(trafficMap graph.TrafficMap, connectionsMap map[string]tcpConnections) {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			if protocol, ok := e.Metadata[graph.ProtocolKey]; !ok || protocol != graph.TCP.Name {
+				continue
+			}
+			// skip if already processed for another namespace
+			if graph.HasTCPConnections(e.Metadata) {
+				continue
+			}
+			connections, ok := connectionsMap[fmt.Sprintf("%s %s", e.Source.ID, e.Dest.ID)]
+			if !ok {
+				continue
+			}
+			// the totals are reset together, when the proxy restarts, so the difference is not affected
+			active := math.Max(0.0, connections[tcpConnectionsOpenedTotal]-connections[tcpConnectionsClosedTotal])
+			graph.AddTCPConnectionsToMetadata(connections[tcpConnectionsOpened], connections[tcpConnectionsClosed], active, e.Dest.Metadata, e.Metadata)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestTCPConnections(t *testing.T) {
+	assert := assert.New(t)
+
+	q0 := `round(label_replace(sum(rate(istio_tcp_connections_opened_total{reporter="destination",destination_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "opened", "", ".*") or label_replace(sum(rate(istio_tcp_connections_closed_total{reporter="destination",destination_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "closed", "", ".*") or label_replace(sum(istio_tcp_connections_opened_total{reporter="destination",destination_workload_namespace="bookinfo"}) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "openedTotal", "", ".*") or label_replace(sum(istio_tcp_connections_closed_total{reporter="destination",destination_workload_namespace="bookinfo"}) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "closedTotal", "", ".*"),0.001)`
+	v0 := model.Vector{
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("opened", "mongodb"),
+			Value:  2.0},
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("closed", "mongodb"),
+			Value:  1.5},
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("openedTotal", "mongodb"),
+			Value:  1000.0},
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("closedTotal", "mongodb"),
+			Value:  990.0}}
+
+	// the outgoing query reports the same edge, the incoming (destination) values are preferred
+	q1 := `round(label_replace(sum(rate(istio_tcp_connections_opened_total{reporter="source",source_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "opened", "", ".*") or label_replace(sum(rate(istio_tcp_connections_closed_total{reporter="source",source_workload_namespace="bookinfo"}[60s])) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "closed", "", ".*") or label_replace(sum(istio_tcp_connections_opened_total{reporter="source",source_workload_namespace="bookinfo"}) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "openedTotal", "", ".*") or label_replace(sum(istio_tcp_connections_closed_total{reporter="source",source_workload_namespace="bookinfo"}) by (source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision), "kiali_tcp_connections", "closedTotal", "", ".*"),0.001)`
+	v1 := model.Vector{
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("opened", "mongodb"),
+			Value:  20.0},
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("openedTotal", "mysqldb"),
+			Value:  5.0},
+		&model.Sample{
+			Metric: tcpConnectionsTestMetric("closedTotal", "mysqldb"),
+			Value:  5.0}}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mockQuery(api, q0, &v0)
+	mockQuery(api, q1, &v1)
+
+	trafficMap := graph.NewTrafficMap()
+	ratings := graph.NewNode(graph.Unknown, "bookinfo", "", "bookinfo", "ratings-v2", "ratings", "v2", graph.GraphTypeWorkload)
+	mongodb := graph.NewNode(graph.Unknown, "bookinfo", "mongodb", "bookinfo", "mongodb-v1", "mongodb", "v1", graph.GraphTypeWorkload)
+	mysqldb := graph.NewNode(graph.Unknown, "bookinfo", "mysqldb", "bookinfo", "mysqldb-v1", "mysqldb", "v1", graph.GraphTypeWorkload)
+	trafficMap[ratings.ID] = &ratings
+	trafficMap[mongodb.ID] = &mongodb
+	trafficMap[mysqldb.ID] = &mysqldb
+	e := ratings.AddEdge(&mongodb)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+	graph.AddToMetadata("tcp", 500.0, "", "-", "mongodb", ratings.Metadata, mongodb.Metadata, e.Metadata)
+	e = ratings.AddEdge(&mysqldb)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+	graph.AddToMetadata("tcp", 100.0, "", "-", "mysqldb", ratings.Metadata, mysqldb.Metadata, e.Metadata)
+
+	duration, _ := time.ParseDuration("60s")
+	appender := TCPConnectionsAppender{
+		GraphType: graph.GraphTypeWorkload,
+		Namespaces: map[string]graph.NamespaceInfo{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	assert.Equal(2, len(ratings.Edges))
+	for _, e := range ratings.Edges {
+		switch e.Dest.ID {
+		case mongodb.ID:
+			assert.Equal(2.0, e.Metadata["tcpConnOpened"])
+			assert.Equal(1.5, e.Metadata["tcpConnClosed"])
+			assert.Equal(10.0, e.Metadata["tcpConnActive"])
+		case mysqldb.ID:
+			assert.False(graph.HasTCPConnections(e.Metadata))
+		default:
+			assert.Fail("Unexpected edge dest: " + e.Dest.ID)
+		}
+	}
+	assert.Equal(2.0, mongodb.Metadata["tcpInConnOpened"])
+	assert.Equal(1.5, mongodb.Metadata["tcpInConnClosed"])
+	assert.Equal(10.0, mongodb.Metadata["tcpInConnActive"])
+	assert.Equal(500.0, mongodb.Metadata["tcpIn"])
+	assert.Equal(nil, mysqldb.Metadata["tcpInConnActive"])
+	assert.Equal(nil, ratings.Metadata["tcpInConnOpened"])
+
+	// a second namespace reporting the same edges does not add to the values
+	appender.appendGraph(trafficMap, "bookinfo", client)
+	assert.Equal(2.0, mongodb.Metadata["tcpInConnOpened"])
+}
+
+func tcpConnectionsTestMetric(valueName, destination string) model.Metric {
+	return model.Metric{
+		"kiali_tcp_connections":          model.LabelValue(valueName),
+		"source_workload_namespace":      "bookinfo",
+		"source_workload":                "ratings-v2",
+		"source_canonical_service":       "ratings",
+		"source_canonical_revision":      "v2",
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            model.LabelValue(destination + ".bookinfo.svc.cluster.local"),
+		"destination_service_name":       model.LabelValue(destination),
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           model.LabelValue(destination + "-v1"),
+		"destination_canonical_service":  model.LabelValue(destination),
+		"destination_canonical_revision": "v1"}
+}
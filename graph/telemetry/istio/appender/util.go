@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
@@ -18,7 +19,7 @@ import (
 
 func promQuery(query string, queryTime time.Time, ctx context.Context, api prom_v1.API, a graph.Appender) model.Vector {
 	// wrap with a round() to be in line with metrics api
-	query = fmt.Sprintf("round(%s,0.001)", query)
+	query = fmt.Sprintf("round(%s,0.001)", util.MapQuery(query))
 	log.Tracef("Appender query:\n%s&time=%v (now=%v, %v)\n", query, queryTime.Format(graph.TF), time.Now().Format(graph.TF), queryTime.Unix())
 
 	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Graph-Appender-" + a.Name())
@@ -31,7 +32,30 @@ func promQuery(query string, queryTime time.Time, ctx context.Context, api prom_
 
 	switch t := value.Type(); t {
 	case model.ValVector: // Instant Vector
-		return value.(model.Vector)
+		return util.UnmapVector(value.(model.Vector))
+	default:
+		graph.Error(fmt.Sprintf("No handling for type %v!\n", t))
+	}
+
+	return nil
+}
+
+func promQueryRange(query string, queryRange prom_v1.Range, ctx context.Context, api prom_v1.API, a graph.Appender) model.Matrix {
+	// wrap with a round() to be in line with metrics api
+	query = fmt.Sprintf("round(%s,0.001)", util.MapQuery(query))
+	log.Tracef("Appender range query:\n%s&start=%v&end=%v&step=%v (now=%v)\n", query, queryRange.Start.Format(graph.TF), queryRange.End.Format(graph.TF), queryRange.Step, time.Now().Format(graph.TF))
+
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Graph-Appender-" + a.Name())
+	value, warnings, err := api.QueryRange(ctx, query, queryRange)
+	if warnings != nil && len(warnings) > 0 {
+		log.Warningf("promQueryRange. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
+	}
+	graph.CheckUnavailable(err)
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+
+	switch t := value.Type(); t {
+	case model.ValMatrix: // Range Vector
+		return util.UnmapMatrix(value.(model.Matrix))
 	default:
 		graph.Error(fmt.Sprintf("No handling for type %v!\n", t))
 	}
@@ -0,0 +1,87 @@
+package appender
+
+import (
+	"time"
+
+	"github.com/kiali/kiali/graph"
+)
+
+const WorkloadLabelsAppenderName = "workloadLabels"
+
+// WorkloadLabelsAppender sets the values of the requested labels on each node, as needed to box the graph by
+// workload label. App nodes are given the label values shared by all of their backing workloads, service nodes
+// are given the label values of the service definition.
+// Name: workloadLabels
+type WorkloadLabelsAppender struct {
+	AccessibleNamespaces map[string]time.Time
+	Labels               []string
+}
+
+// Name implements Appender
+func (a WorkloadLabelsAppender) Name() string {
+	return WorkloadLabelsAppenderName
+}
+
+// AppendGraph implements Appender
+func (a WorkloadLabelsAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 || len(a.Labels) == 0 {
+		return
+	}
+
+	a.applyWorkloadLabels(trafficMap, globalInfo)
+}
+
+func (a WorkloadLabelsAppender) applyWorkloadLabels(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo) {
+	for _, n := range trafficMap {
+		// skip if already processed for another namespace
+		if _, ok := n.Metadata[graph.Labels]; ok {
+			continue
+		}
+
+		// skip if the node's namespace is outside of the accessible namespaces
+		if _, ok := a.AccessibleNamespaces[n.Namespace]; !ok {
+			continue
+		}
+
+		var labels map[string]string
+		switch n.NodeType {
+		case graph.NodeTypeWorkload:
+			if workload, found := getWorkload(n.Namespace, n.Workload, globalInfo); found {
+				labels = a.selectLabels(workload.Labels)
+			}
+		case graph.NodeTypeApp:
+			for i, workload := range getAppWorkloads(n.Namespace, n.App, n.Version, globalInfo) {
+				if i == 0 {
+					labels = a.selectLabels(workload.Labels)
+					continue
+				}
+				for k, v := range labels {
+					if workload.Labels[k] != v {
+						delete(labels, k)
+					}
+				}
+			}
+		case graph.NodeTypeService:
+			if service, found := getServiceDefinition(n.Namespace, n.Service, globalInfo); found {
+				labels = a.selectLabels(service.Labels)
+			}
+		default:
+			continue
+		}
+
+		if len(labels) > 0 {
+			n.Metadata[graph.Labels] = labels
+		}
+	}
+}
+
+// selectLabels returns the requested labels present in the provided labels
+func (a WorkloadLabelsAppender) selectLabels(labels map[string]string) map[string]string {
+	selected := make(map[string]string)
+	for _, label := range a.Labels {
+		if val, ok := labels[label]; ok {
+			selected[label] = val
+		}
+	}
+	return selected
+}
@@ -0,0 +1,36 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+)
+
+func TestWorkloadLabels(t *testing.T) {
+	config.Set(config.NewConfig())
+	trafficMap := buildWorkloadTrafficMap()
+	inaccessibleTrafficMap := buildInaccessibleWorkloadTrafficMap()
+	businessLayer := setupSidecarsCheckWorkloads(buildFakeWorkloadDeployments(), buildFakeWorkloadPods())
+
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = businessLayer
+	namespaceInfo := graph.NewAppenderNamespaceInfo("testNamespace")
+
+	a := WorkloadLabelsAppender{
+		AccessibleNamespaces: map[string]time.Time{"testNamespace": time.Now()},
+		Labels:               []string{"team", "wk"},
+	}
+	a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
+	a.AppendGraph(inaccessibleTrafficMap, globalInfo, namespaceInfo)
+
+	for _, node := range trafficMap {
+		assert.Equal(t, map[string]string{"wk": "wk-1"}, node.Metadata[graph.Labels])
+	}
+	for _, node := range inaccessibleTrafficMap {
+		assert.Nil(t, node.Metadata[graph.Labels])
+	}
+}
@@ -0,0 +1,155 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// telemetryClients are the Prometheus clients holding the mesh telemetry, keyed by cluster name
+type telemetryClients map[string]*prometheus.Client
+
+// clusterClient is a cached client of a cluster Prometheus, rebuilt when its config changes
+type clusterClient struct {
+	client *prometheus.Client
+	config config.PrometheusConfig
+}
+
+var (
+	clusterClients      = make(map[string]clusterClient)
+	clusterClientsMutex sync.Mutex
+)
+
+// newTelemetryClients returns the default client or, when external_services.prometheus.clusters is configured, a
+// client for each cluster Prometheus. The cluster clients are created once, each one holds its own connections.
+func newTelemetryClients(client *prometheus.Client) telemetryClients {
+	clusters := config.Get().ExternalServices.Prometheus.Clusters
+	if len(clusters) == 0 {
+		return telemetryClients{graph.Unknown: client}
+	}
+
+	clusterClientsMutex.Lock()
+	defer clusterClientsMutex.Unlock()
+
+	clients := make(telemetryClients, len(clusters))
+	for cluster, prometheusConfig := range clusters {
+		cached, ok := clusterClients[cluster]
+		if !ok || !equalPrometheusConfig(cached.config, prometheusConfig) {
+			newClient, err := prometheus.NewClientForConfig(prometheusConfig)
+			graph.CheckError(err)
+			cached = clusterClient{client: newClient, config: prometheusConfig}
+			clusterClients[cluster] = cached
+		}
+		clients[cluster] = cached.client
+	}
+	return clients
+}
+
+// equalPrometheusConfig compares the settings of a cluster Prometheus used to create its client
+func equalPrometheusConfig(a, b config.PrometheusConfig) bool {
+	return a.URL == b.URL && a.Auth == b.Auth
+}
+
+// setAppenderClient sets the client of the appenders. With a Prometheus per cluster the appender queries are run
+// on every cluster Prometheus, like the traffic map queries, otherwise the appenders use the default client.
+func (in telemetryClients) setAppenderClient(globalInfo *graph.AppenderGlobalInfo, client *prometheus.Client) {
+	if _, ok := in[graph.Unknown]; ok && len(in) == 1 {
+		return
+	}
+
+	appenderClient := *client
+	appenderClient.Inject(clustersAPI{API: client.API(), clients: in})
+	globalInfo.PromClient = &appenderClient
+}
+
+// clustersAPI runs the queries on every cluster Prometheus and merges the results, ignoring the duplicate
+// time series. The other calls are served by the default Prometheus.
+type clustersAPI struct {
+	prom_v1.API
+	clients telemetryClients
+}
+
+// Query is required by the prom_v1.API interface
+func (in clustersAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, prom_v1.Warnings, error) {
+	return in.query(func(api prom_v1.API) (model.Value, prom_v1.Warnings, error) {
+		return api.Query(ctx, query, ts)
+	})
+}
+
+// QueryRange is required by the prom_v1.API interface
+func (in clustersAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, prom_v1.Warnings, error) {
+	return in.query(func(api prom_v1.API) (model.Value, prom_v1.Warnings, error) {
+		return api.QueryRange(ctx, query, r)
+	})
+}
+
+func (in clustersAPI) query(query func(api prom_v1.API) (model.Value, prom_v1.Warnings, error)) (model.Value, prom_v1.Warnings, error) {
+	type clusterResult struct {
+		cluster  string
+		value    model.Value
+		warnings prom_v1.Warnings
+		err      error
+	}
+
+	results := make(chan clusterResult, len(in.clients))
+	for cluster, client := range in.clients {
+		go func(cluster string, api prom_v1.API) {
+			value, warnings, err := query(api)
+			results <- clusterResult{cluster: cluster, value: value, warnings: warnings, err: err}
+		}(cluster, client.API())
+	}
+
+	var vector model.Vector
+	var matrix model.Matrix
+	var warnings prom_v1.Warnings
+	var err error
+	seen := make(map[model.Fingerprint]bool)
+	for range in.clients {
+		result := <-results
+		warnings = append(warnings, result.warnings...)
+		if result.err != nil {
+			log.Errorf("Appender query failed for the Prometheus of cluster [%s]: %v", result.cluster, result.err)
+			err = result.err
+			continue
+		}
+		switch value := result.value.(type) {
+		case model.Vector:
+			for _, sample := range value {
+				if fp := sample.Metric.Fingerprint(); !seen[fp] {
+					seen[fp] = true
+					vector = append(vector, sample)
+				}
+			}
+		case model.Matrix:
+			for _, stream := range value {
+				if fp := stream.Metric.Fingerprint(); !seen[fp] {
+					seen[fp] = true
+					matrix = append(matrix, stream)
+				}
+			}
+		default:
+			err = fmt.Errorf("no handling for type %v in cluster [%s]", result.value.Type(), result.cluster)
+		}
+	}
+	// fail as the query would fail on a single Prometheus
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	if matrix != nil {
+		return matrix, warnings, nil
+	}
+	if vector == nil {
+		vector = model.Vector{}
+	}
+	return vector, warnings, nil
+}
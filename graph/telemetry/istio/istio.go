@@ -10,11 +10,17 @@ package istio
 //
 //   Second Pass: Apply any requested appenders to alter or append to the graph.
 //
-// Supports three vendor-specific query parameters:
+// Supports five vendor-specific query parameters:
 //   aggregate: Must be a valid metric attribute (default: request_operation)
+//   anomalyBaselineDays: Used only with the anomaly appender, the number of previous days in the baseline (default: 7)
+//   anomalyThreshold: Used only with the anomaly appender, the deviation in standard deviations (default: 3)
 //   responseTime: Must be one of: avg | 50 | 95 | 99
 //   throughputType: request | response (default: response)
 //
+// Queries use the Istio standard metric and label names. Telemetry stored under different names (e.g. exported
+// through an OpenTelemetry Collector) is supported by configuring external_services.istio.telemetry_mapping.
+// Telemetry held by a Prometheus per cluster is supported by configuring external_services.prometheus.clusters.
+//
 import (
 	"context"
 	"crypto/md5"
@@ -44,19 +50,29 @@ func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Clie
 	log.Tracef("Build [%s] graph for [%d] namespaces [%v]", o.GraphType, len(o.Namespaces), o.Namespaces)
 
 	appenders := appender.ParseAppenders(o)
+	clients := newTelemetryClients(client)
+	clients.setAppenderClient(globalInfo, client)
 	trafficMap := graph.NewTrafficMap()
 
+	// a failed namespace is reported as a warning, and omitted from the graph
+	var failed []graph.Warning
 	for _, namespace := range o.Namespaces {
 		log.Tracef("Build traffic map for namespace [%v]", namespace)
-		namespaceTrafficMap := buildNamespaceTrafficMap(namespace.Name, o, client)
-		namespaceInfo := graph.NewAppenderNamespaceInfo(namespace.Name)
-		for _, a := range appenders {
-			appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
-			a.AppendGraph(namespaceTrafficMap, globalInfo, namespaceInfo)
-			appenderTimer.ObserveDuration()
+		var namespaceTrafficMap graph.TrafficMap
+		if !globalInfo.CatchWarning(namespace.Name, "", func() {
+			namespaceTrafficMap = buildNamespaceTrafficMap(namespace.Name, o, clients, globalInfo)
+		}) {
+			failed = append(failed, globalInfo.Warnings[len(globalInfo.Warnings)-1])
+			continue
 		}
+		namespaceInfo := graph.NewAppenderNamespaceInfo(namespace.Name)
+		appendGraph(appenders, namespaceTrafficMap, globalInfo, namespaceInfo)
 		telemetry.MergeTrafficMaps(trafficMap, namespace.Name, namespaceTrafficMap)
 	}
+	// there is no partial graph to return if every namespace failed
+	if len(failed) > 0 && len(failed) == len(o.Namespaces) {
+		graph.Panic(failed[0].Message, failed[0].Code)
+	}
 
 	// The appenders can add/remove/alter nodes. After the manipulations are complete
 	// we can make some final adjustments:
@@ -72,9 +88,20 @@ func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Clie
 	return trafficMap
 }
 
+// appendGraph runs the appenders for the namespace, a failed appender is reported as a warning
+func appendGraph(appenders []graph.Appender, trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	for _, a := range appenders {
+		appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
+		if globalInfo.CatchWarning(namespaceInfo.Namespace, a.Name(), func() { a.AppendGraph(trafficMap, globalInfo, namespaceInfo) }) {
+			appenderTimer.ObserveDuration() // notice we only collect metrics for successful appenders
+		}
+	}
+}
+
 // buildNamespaceTrafficMap returns a map of all namespace nodes (key=id).  All
 // nodes either directly send and/or receive requests from a node in the namespace.
-func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client *prometheus.Client) graph.TrafficMap {
+func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, clients telemetryClients, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	dropped := globalInfo.DroppedTelemetry
 	// create map to aggregate traffic by protocol and response code
 	trafficMap := graph.NewTrafficMap()
 	duration := o.Namespaces[namespace].Duration
@@ -95,8 +122,8 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, false, o)
+	incomingVector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &incomingVector, false, o, dropped)
 
 	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic
 	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_workload_namespace="%s"} [%vs])) by (%s) %s`,
@@ -105,8 +132,8 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, false, o)
+	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &incomingVector, false, o, dropped)
 
 	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
 	query = fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"} [%vs])) by (%s) %s`,
@@ -115,8 +142,8 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &outgoingVector, false, o)
+	outgoingVector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &outgoingVector, false, o, dropped)
 
 	// TCP traffic
 	metric = "istio_tcp_sent_bytes_total"
@@ -130,8 +157,8 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, true, o)
+	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &incomingVector, true, o, dropped)
 
 	// 1) Incoming: query destination telemetry to capture namespace services' incoming traffic	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_service_namespace="%s"} [%vs])) by (%s) %s`,
 	query = fmt.Sprintf(`sum(rate(%s{reporter="destination",destination_workload_namespace="%s"} [%vs])) by (%s) %s`,
@@ -140,8 +167,8 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &incomingVector, true, o)
+	incomingVector = promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &incomingVector, true, o, dropped)
 
 	// 2) Outgoing: query source telemetry to capture namespace workloads' outgoing traffic
 	query = fmt.Sprintf(`sum(rate(%s{reporter="source",source_workload_namespace="%s"} [%vs])) by (%s) %s`,
@@ -150,13 +177,14 @@ func buildNamespaceTrafficMap(namespace string, o graph.TelemetryOptions, client
 		int(duration.Seconds()), // range duration for the query
 		groupBy,
 		idleCondition)
-	outgoingVector = promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &outgoingVector, true, o)
+	outgoingVector = promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &outgoingVector, true, o, dropped)
 
 	return trafficMap
 }
 
-func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, isTCP bool, o graph.TelemetryOptions) {
+// populateTrafficMap adds the traffic of the vector samples, a sample with missing or bad labels is dropped and reported
+func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, isTCP bool, o graph.TelemetryOptions, dropped graph.DroppedTelemetryReport) {
 	for _, s := range *vector {
 		val := float64(s.Value)
 
@@ -178,6 +206,7 @@ func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, isTCP
 		lFlags, flagsOk := m["response_flags"]
 		if !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcOk || !destSvcNameOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk || !flagsOk {
 			log.Warningf("Skipping %s, missing expected TS labels", m.String())
+			dropTelemetry(dropped, m, util.DroppedMissingLabels)
 			continue
 		}
 
@@ -191,14 +220,16 @@ func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, isTCP
 		// handle clusters
 		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
 
-		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+		if reason := util.BadSourceTelemetryReason(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp); reason != "" {
+			dropTelemetry(dropped, m, reason)
 			continue
 		}
 
 		// handle unusual destinations
 		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
 
-		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+		if reason := util.BadDestTelemetryReason(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl); reason != "" {
+			dropTelemetry(dropped, m, reason)
 			continue
 		}
 
@@ -211,6 +242,7 @@ func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, isTCP
 
 			if !protocolOk || !codeOk {
 				log.Warningf("Skipping %s, missing expected HTTP/GRPC TS labels", m.String())
+				dropTelemetry(dropped, m, util.DroppedMissingHTTPLabels)
 				continue
 			}
 
@@ -233,6 +265,28 @@ func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, isTCP
 	}
 }
 
+// dropTelemetry reports a sample dropped for the reason. The sample is reported under the namespace of the
+// bad labels, the destination service namespace for a bad destination, the source workload namespace otherwise.
+func dropTelemetry(dropped graph.DroppedTelemetryReport, m model.Metric, reason string) {
+	namespaces := []model.LabelValue{m["source_workload_namespace"], m["destination_service_namespace"]}
+	if reason == util.DroppedBadDestCluster || reason == util.DroppedBadDestService {
+		namespaces = []model.LabelValue{m["destination_service_namespace"], m["source_workload_namespace"]}
+	}
+	namespace := graph.Unknown
+	for _, ns := range namespaces {
+		if graph.IsOK(string(ns)) {
+			namespace = string(ns)
+			break
+		}
+	}
+
+	labels := make(map[string]string, len(m))
+	for k, v := range m {
+		labels[string(k)] = string(v)
+	}
+	dropped.Drop(namespace, reason, labels)
+}
+
 func addTraffic(trafficMap graph.TrafficMap, inject bool, val float64, protocol, code, flags, host, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer string, o graph.TelemetryOptions) {
 	source, _ := addNode(trafficMap, sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, o)
 	dest, _ := addNode(trafficMap, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, o)
@@ -328,15 +382,13 @@ func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, gl
 	log.Tracef("Build graph for node [%+v]", n)
 
 	appenders := appender.ParseAppenders(o)
-	trafficMap := buildNodeTrafficMap(o.Cluster, o.NodeOptions.Namespace, n, o, client)
+	clients := newTelemetryClients(client)
+	clients.setAppenderClient(globalInfo, client)
+	trafficMap := buildNodeTrafficMap(o.Cluster, o.NodeOptions.Namespace, n, o, clients, globalInfo)
 
 	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
 
-	for _, a := range appenders {
-		appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
-		a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
-		appenderTimer.ObserveDuration()
-	}
+	appendGraph(appenders, trafficMap, globalInfo, namespaceInfo)
 
 	// The appenders can add/remove/alter nodes. After the manipulations are complete
 	// we can make some final adjustments:
@@ -355,7 +407,8 @@ func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, gl
 // buildNodeTrafficMap returns a map of all nodes requesting or requested by the target node (key=id). Node graphs
 // are from the perspective of the node, as such we use destination telemetry for incoming traffic and source telemetry
 // for outgoing traffic.
-func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.TelemetryOptions, client *prometheus.Client) graph.TrafficMap {
+func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.TelemetryOptions, clients telemetryClients, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	dropped := globalInfo.DroppedTelemetry
 	duration := o.Namespaces[namespace].Duration
 
 	// create map to aggregate traffic by response code
@@ -421,8 +474,8 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 			int(duration.Seconds()), // range duration for the query
 			groupBy,
 			idleCondition)
-		vector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-		populateTrafficMap(trafficMap, &vector, false, o)
+		vector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+		populateTrafficMap(trafficMap, &vector, false, o, dropped)
 
 		// 1.b) query dest telemetry for requests to the service, serviced by service workloads
 		query = fmt.Sprintf(`sum(rate(%s{reporter="destination"%s,destination_service_namespace="%s",destination_service=~"^%s\\.%s\\..*$"} [%vs])) by (%s) %s`,
@@ -437,8 +490,8 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	inVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &inVector, false, o)
+	inVector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &inVector, false, o, dropped)
 
 	// 2) query for outbound traffic
 	switch n.NodeType {
@@ -477,8 +530,8 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	outVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &outVector, false, o)
+	outVector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &outVector, false, o, dropped)
 
 	// TCP traffic
 	metric = "istio_tcp_sent_bytes_total"
@@ -529,8 +582,8 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	tcpInVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &tcpInVector, true, o)
+	tcpInVector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &tcpInVector, true, o, dropped)
 
 	// 2) query for outbound traffic
 	switch n.NodeType {
@@ -569,8 +622,8 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	default:
 		graph.Error(fmt.Sprintf("NodeType [%s] not supported", n.NodeType))
 	}
-	tcpOutVector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &tcpOutVector, true, o)
+	tcpOutVector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &tcpOutVector, true, o, dropped)
 
 	return trafficMap
 }
@@ -584,15 +637,13 @@ func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.
 		o.Appenders.AppenderNames = append(o.Appenders.AppenderNames, appender.AggregateNodeAppenderName)
 	}
 	appenders := appender.ParseAppenders(o)
-	trafficMap := buildAggregateNodeTrafficMap(o.NodeOptions.Namespace, n, o, client)
+	clients := newTelemetryClients(client)
+	clients.setAppenderClient(globalInfo, client)
+	trafficMap := buildAggregateNodeTrafficMap(o.NodeOptions.Namespace, n, o, clients, globalInfo)
 
 	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
 
-	for _, a := range appenders {
-		appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
-		a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
-		appenderTimer.ObserveDuration()
-	}
+	appendGraph(appenders, trafficMap, globalInfo, namespaceInfo)
 
 	// The appenders can add/remove/alter nodes. After the manipulations are complete
 	// we can make some final adjustments:
@@ -606,7 +657,8 @@ func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.
 
 // buildAggregateNodeTrafficMap returns a map of all incoming and outgoing traffic from the perspective of the aggregate. Aggregates
 // are always generated for serviced requests and therefore via destination telemetry.
-func buildAggregateNodeTrafficMap(namespace string, n graph.Node, o graph.TelemetryOptions, client *prometheus.Client) graph.TrafficMap {
+func buildAggregateNodeTrafficMap(namespace string, n graph.Node, o graph.TelemetryOptions, clients telemetryClients, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	dropped := globalInfo.DroppedTelemetry
 	interval := o.Namespaces[namespace].Duration
 
 	// create map to aggregate traffic by response code
@@ -638,22 +690,80 @@ func buildAggregateNodeTrafficMap(namespace string, n graph.Node, o graph.Teleme
 	query := fmt.Sprintf(`(%s) OR (%s)`, httpQuery, tcpQuery)
 	*/
 	query := httpQuery
-	vector := promQuery(query, time.Unix(o.QueryTime, 0), client.API())
-	populateTrafficMap(trafficMap, &vector, false, o)
+	vector := promQuery(query, time.Unix(o.QueryTime, 0), clients, namespace, globalInfo)
+	populateTrafficMap(trafficMap, &vector, false, o, dropped)
 
 	return trafficMap
 }
 
-func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector {
+// promQuery queries the clients concurrently and returns all of the results. Each cluster Prometheus reports the
+// telemetry of its own proxies, so the traffic between clusters is reported by the source and destination cluster
+// Prometheus. The results are populated into the same traffic map, which ignores the duplicate time series. A failed
+// cluster Prometheus is reported as a warning for the namespace and the results of the other clusters are returned,
+// the query fails only if every cluster failed.
+func promQuery(query string, queryTime time.Time, clients telemetryClients, namespace string, globalInfo *graph.AppenderGlobalInfo) model.Vector {
 	if query == "" {
 		return model.Vector{}
 	}
 
+	if len(clients) == 1 {
+		for _, client := range clients {
+			return promQueryAPI(query, queryTime, client.API())
+		}
+	}
+
+	type clusterResult struct {
+		cluster string
+		failure interface{} // the graph error panic, if the query failed
+		vector  model.Vector
+	}
+
+	results := make(chan clusterResult, len(clients))
+	for cluster, client := range clients {
+		go func(cluster string, api prom_v1.API) {
+			result := clusterResult{cluster: cluster}
+			defer func() {
+				result.failure = recover()
+				results <- result
+			}()
+			result.vector = promQueryAPI(query, queryTime, api)
+		}(cluster, client.API())
+	}
+
+	vector := model.Vector{}
+	failures := []clusterResult{}
+	for range clients {
+		result := <-results
+		if result.failure != nil {
+			log.Errorf("Graph query failed for the Prometheus of cluster [%s]: %v", result.cluster, result.failure)
+			failures = append(failures, result)
+			continue
+		}
+		vector = append(vector, result.vector...)
+	}
+	// fail as the query would fail on a single Prometheus
+	if len(failures) == len(clients) {
+		panic(failures[0].failure)
+	}
+	for _, failure := range failures {
+		message, code := graph.PanicMessage(failure.failure)
+		globalInfo.AddWarning(graph.Warning{
+			Cluster:   failure.cluster,
+			Code:      code,
+			Message:   message,
+			Namespace: namespace,
+		})
+	}
+
+	return vector
+}
+
+func promQueryAPI(query string, queryTime time.Time, api prom_v1.API) model.Vector {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// wrap with a round() to be in line with metrics api
-	query = fmt.Sprintf("round(%s,0.001)", query)
+	query = fmt.Sprintf("round(%s,0.001)", util.MapQuery(query))
 	log.Tracef("Graph query:\n%s@time=%v (now=%v, %v)\n", query, queryTime.Format(graph.TF), time.Now().Format(graph.TF), queryTime.Unix())
 
 	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Graph-Generation")
@@ -666,7 +776,7 @@ func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector
 
 	switch t := value.Type(); t {
 	case model.ValVector: // Instant Vector
-		return value.(model.Vector)
+		return util.UnmapVector(value.(model.Vector))
 	default:
 		graph.Error(fmt.Sprintf("No handling for type %v!\n", t))
 	}
@@ -0,0 +1,242 @@
+package istio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestPromQueryFederation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	query := `sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="bookinfo"} [60s])) by (source_cluster) > 0`
+	mappedQuery := `round(` + query + `,0.001)`
+
+	// the east -> west request is reported by the proxies of both clusters
+	east, eastAPI := federationTestClient(t)
+	federationTestQuery(eastAPI, mappedQuery, model.Vector{
+		federationTestSample("east", "productpage-v1", "productpage", "west", "reviews-v1", "reviews", 10.0),
+		federationTestSample("east", "productpage-v1", "productpage", "east", "details-v1", "details", 5.0),
+	})
+	west, westAPI := federationTestClient(t)
+	federationTestQuery(westAPI, mappedQuery, model.Vector{
+		federationTestSample("east", "productpage-v1", "productpage", "west", "reviews-v1", "reviews", 10.0),
+		federationTestSample("west", "reviews-v1", "reviews", "west", "ratings-v1", "ratings", 20.0),
+	})
+
+	globalInfo := graph.NewAppenderGlobalInfo()
+	vector := promQuery(query, time.Now(), telemetryClients{"east": east, "west": west}, "bookinfo", globalInfo)
+	assert.Equal(4, len(vector))
+	assert.Empty(globalInfo.Warnings)
+
+	o := graph.TelemetryOptions{
+		CommonOptions: graph.CommonOptions{
+			GraphType: graph.GraphTypeWorkload,
+		},
+	}
+	trafficMap := graph.NewTrafficMap()
+	populateTrafficMap(trafficMap, &vector, false, o, graph.NewDroppedTelemetryReport())
+
+	productpageID, _ := graph.Id("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", o.GraphType)
+	reviewsID, _ := graph.Id("west", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", o.GraphType)
+	detailsID, _ := graph.Id("east", "bookinfo", "", "bookinfo", "details-v1", "details", "v1", o.GraphType)
+	ratingsID, _ := graph.Id("west", "bookinfo", "", "bookinfo", "ratings-v1", "ratings", "v1", o.GraphType)
+	assert.Equal(4, len(trafficMap))
+
+	productpage, ok := trafficMap[productpageID]
+	assert.True(ok)
+	assert.Equal(2, len(productpage.Edges))
+	for _, e := range productpage.Edges {
+		switch e.Dest.ID {
+		case reviewsID:
+			// the duplicate time series is ignored
+			assert.Equal(10.0, e.Metadata[graph.MetadataKey("http")])
+		case detailsID:
+			assert.Equal(5.0, e.Metadata[graph.MetadataKey("http")])
+		default:
+			assert.Fail("Unexpected edge dest: " + e.Dest.ID)
+		}
+	}
+
+	reviews, ok := trafficMap[reviewsID]
+	assert.True(ok)
+	assert.Equal(1, len(reviews.Edges))
+	assert.Equal(ratingsID, reviews.Edges[0].Dest.ID)
+}
+
+func TestPromQueryFederationFailure(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	query := `sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo"} [60s])) by (source_cluster) > 0`
+	mappedQuery := `round(` + query + `,0.001)`
+
+	east, eastAPI := federationTestClient(t)
+	federationTestQuery(eastAPI, mappedQuery, model.Vector{
+		federationTestSample("east", "productpage-v1", "productpage", "east", "details-v1", "details", 5.0),
+	})
+	west, westAPI := federationTestClient(t)
+	// an unexpected result type fails the query
+	westAPI.On("Query", mock.Anything, mappedQuery, mock.AnythingOfType("time.Time")).Return(&model.Scalar{}, nil)
+
+	// a failing cluster Prometheus is reported as a warning, once per namespace, with the results of the other clusters
+	globalInfo := graph.NewAppenderGlobalInfo()
+	vector := promQuery(query, time.Now(), telemetryClients{"east": east, "west": west}, "bookinfo", globalInfo)
+	assert.Equal(1, len(vector))
+	vector = promQuery(query, time.Now(), telemetryClients{"east": east, "west": west}, "bookinfo", globalInfo)
+	assert.Equal(1, len(vector))
+	assert.Equal(1, len(globalInfo.Warnings))
+	assert.Equal("west", globalInfo.Warnings[0].Cluster)
+	assert.Equal("bookinfo", globalInfo.Warnings[0].Namespace)
+	assert.Equal(500, globalInfo.Warnings[0].Code)
+
+	// the query fails, in the calling goroutine, if every cluster Prometheus failed
+	north, northAPI := federationTestClient(t)
+	northAPI.On("Query", mock.Anything, mappedQuery, mock.AnythingOfType("time.Time")).Return(&model.Scalar{}, nil)
+	assert.Panics(func() {
+		promQuery(query, time.Now(), telemetryClients{"north": north, "west": west}, "bookinfo", graph.NewAppenderGlobalInfo())
+	})
+}
+
+func TestAppendGraphWarning(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := graph.NewTrafficMap()
+	globalInfo := graph.NewAppenderGlobalInfo()
+	namespaceInfo := graph.NewAppenderNamespaceInfo("bookinfo")
+
+	appended := []string{}
+	appenders := []graph.Appender{
+		warningTestAppender{name: "failing", appended: &appended, failure: "failed to fetch workloads"},
+		warningTestAppender{name: "working", appended: &appended},
+	}
+
+	// a failing appender does not prevent the following appenders from running
+	appendGraph(appenders, trafficMap, globalInfo, namespaceInfo)
+	assert.Equal([]string{"working"}, appended)
+	assert.Equal([]graph.Warning{
+		{Appender: "failing", Code: 500, Message: "failed to fetch workloads", Namespace: "bookinfo"},
+	}, globalInfo.Warnings)
+}
+
+type warningTestAppender struct {
+	name     string
+	appended *[]string
+	failure  string
+}
+
+func (a warningTestAppender) Name() string {
+	return a.name
+}
+
+func (a warningTestAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if a.failure != "" {
+		graph.Error(a.failure)
+	}
+	*a.appended = append(*a.appended, a.name)
+}
+
+func TestNewTelemetryClientsCached(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.ExternalServices.Prometheus.Clusters = map[string]config.PrometheusConfig{
+		"east": {URL: "http://prometheus.east:9090"},
+		"west": {URL: "http://prometheus.west:9090"},
+	}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	clients := newTelemetryClients(nil)
+	assert.Equal(2, len(clients))
+	again := newTelemetryClients(nil)
+	assert.True(clients["east"] == again["east"])
+	assert.True(clients["west"] == again["west"])
+
+	// a changed cluster config replaces the client
+	conf.ExternalServices.Prometheus.Clusters["west"] = config.PrometheusConfig{URL: "http://thanos.west:9090"}
+	again = newTelemetryClients(nil)
+	assert.True(clients["east"] == again["east"])
+	assert.False(clients["west"] == again["west"])
+}
+
+func TestAppenderClientFederation(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	query := `sum(rate(istio_requests_total{reporter="destination"}[60s])) by (source_cluster)`
+
+	east, eastAPI := federationTestClient(t)
+	federationTestQuery(eastAPI, query, model.Vector{
+		federationTestSample("east", "productpage-v1", "productpage", "west", "reviews-v1", "reviews", 10.0),
+		federationTestSample("east", "productpage-v1", "productpage", "east", "details-v1", "details", 5.0),
+	})
+	west, westAPI := federationTestClient(t)
+	federationTestQuery(westAPI, query, model.Vector{
+		federationTestSample("east", "productpage-v1", "productpage", "west", "reviews-v1", "reviews", 10.0),
+		federationTestSample("west", "reviews-v1", "reviews", "west", "ratings-v1", "ratings", 20.0),
+	})
+	defaultClient, _ := federationTestClient(t)
+
+	globalInfo := graph.NewAppenderGlobalInfo()
+	telemetryClients{"east": east, "west": west}.setAppenderClient(globalInfo, defaultClient)
+	assert.NotNil(globalInfo.PromClient)
+
+	// the appender queries are run on both clusters, the duplicate time series is ignored
+	value, _, err := globalInfo.PromClient.API().Query(context.Background(), query, time.Now())
+	assert.NoError(err)
+	assert.Equal(3, len(value.(model.Vector)))
+
+	// without clusters the appenders use the default client
+	globalInfo = graph.NewAppenderGlobalInfo()
+	telemetryClients{graph.Unknown: defaultClient}.setAppenderClient(globalInfo, defaultClient)
+	assert.Nil(globalInfo.PromClient)
+}
+
+func federationTestClient(t *testing.T) (*prometheus.Client, *prometheustest.PromAPIMock) {
+	api := new(prometheustest.PromAPIMock)
+	client, err := prometheus.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Inject(api)
+	return client, api
+}
+
+func federationTestQuery(api *prometheustest.PromAPIMock, query string, ret model.Vector) {
+	api.On("Query", mock.Anything, query, mock.AnythingOfType("time.Time")).Return(ret, nil)
+}
+
+func federationTestSample(sourceCluster, sourceWorkload, sourceApp, destCluster, destWorkload, destApp string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"source_cluster":                 model.LabelValue(sourceCluster),
+			"source_workload_namespace":      "bookinfo",
+			"source_workload":                model.LabelValue(sourceWorkload),
+			"source_canonical_service":       model.LabelValue(sourceApp),
+			"source_canonical_revision":      "v1",
+			"destination_cluster":            model.LabelValue(destCluster),
+			"destination_service_namespace":  "bookinfo",
+			"destination_service":            model.LabelValue(destApp + ".bookinfo.svc.cluster.local"),
+			"destination_service_name":       model.LabelValue(destApp),
+			"destination_workload_namespace": "bookinfo",
+			"destination_workload":           model.LabelValue(destWorkload),
+			"destination_canonical_service":  model.LabelValue(destApp),
+			"destination_canonical_revision": "v1",
+			"request_protocol":               "http",
+			"response_code":                  "200",
+			"grpc_response_status":           "",
+			"response_flags":                 "-",
+		},
+		Value: model.SampleValue(value),
+	}
+}
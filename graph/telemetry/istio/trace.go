@@ -0,0 +1,338 @@
+package istio
+
+// Trace.go is responsible for generating the TrafficMap of a single Jaeger trace, showing the exact path taken
+// by the traced request through the mesh.
+//
+// Each span is attributed to the node of the proxy (or application) reporting it, using the Istio span tags
+// (istio.namespace, istio.canonical_service, istio.canonical_revision) and the Envoy node_id, which holds the
+// proxy pod name.  Each parent-child relationship between the spans of two different nodes is an edge.  The
+// edge traffic is the number of requests in the trace (not a rate), and the edge response time is the longest
+// duration of the child spans, in millis.
+//
+import (
+	"fmt"
+	"strings"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry"
+	"github.com/kiali/kiali/log"
+)
+
+// traceSpan is a span of the trace and the node reporting it, the node is nil if the span can not be attributed
+// to a node (e.g. a span reported by an application outside of the mesh).
+type traceSpan struct {
+	hasRemoteChild bool        // true if the span has a child span reported by another node
+	node           *graph.Node // the node reporting the span
+	serviceHost    string      // the destination service host of an outbound client span, otherwise empty
+	span           *jaegerModels.Span
+}
+
+// workloadResolver returns the name of the workload of a pod
+type workloadResolver func(namespace, pod string) string
+
+// BuildTraceTrafficMap returns a map of the nodes traversed by a single trace (key=id).
+func BuildTraceTrafficMap(trace jaegerModels.Trace, o graph.TelemetryOptions, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	log.Tracef("Build [%s] graph for trace [%s]", o.GraphType, trace.TraceID)
+
+	trafficMap := buildTraceTrafficMap(trace, o, newWorkloadResolver(globalInfo.Business, o))
+
+	// mark the outsiders (i.e. nodes not in the requested namespaces) and the inaccessible nodes, and the
+	// traffic generators (i.e. the request origin)
+	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
+	telemetry.MarkTrafficGenerators(trafficMap)
+
+	if graph.GraphTypeService == o.GraphType {
+		trafficMap = telemetry.ReduceToServiceGraph(trafficMap)
+	}
+
+	return trafficMap
+}
+
+func buildTraceTrafficMap(trace jaegerModels.Trace, o graph.TelemetryOptions, resolveWorkload workloadResolver) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	// key=spanID
+	spans := make(map[jaegerModels.SpanID]*traceSpan, len(trace.Spans))
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		if span.Process == nil {
+			if process, ok := trace.Processes[span.ProcessID]; ok {
+				span.Process = &process
+			}
+		}
+		ts := &traceSpan{span: span}
+		spans[span.SpanID] = ts
+
+		namespace, app, version, pod := spanIdentity(span)
+		workload := graph.Unknown
+		if namespace != "" && pod != "" {
+			workload = resolveWorkload(namespace, pod)
+		}
+		// workload graph nodes require the workload, app graph nodes fall back to the workload
+		isWorkloadGraph := o.GraphType == graph.GraphTypeWorkload || o.GraphType == graph.GraphTypeService
+		if namespace == "" || !graph.IsOK(workload) && (isWorkloadGraph || !graph.IsOK(app)) {
+			log.Tracef("Skipping span [%s], unable to identify the reporting node", span.SpanID)
+			continue
+		}
+		ts.node, _ = addNode(trafficMap, graph.Unknown, namespace, "", namespace, workload, app, version, o)
+		ts.serviceHost = spanServiceHost(span)
+	}
+
+	// every parent-child relationship between two nodes is an edge. The parent is the closest ancestor span
+	// attributed to a node, the spans in between are not part of the mesh.
+	for _, ts := range spans {
+		if ts.node == nil {
+			continue
+		}
+		parent := nodeAncestor(spans, ts)
+		if parent == nil || parent.node.ID == ts.node.ID {
+			continue
+		}
+		parent.hasRemoteChild = true
+		addTraceTraffic(trafficMap, parent, ts, o)
+	}
+
+	// an outbound request without a remote child span is served outside of the mesh (or by a destination not
+	// reporting spans), the edge ends at the destination service
+	for _, ts := range spans {
+		if ts.node == nil || ts.hasRemoteChild || ts.serviceHost == "" {
+			continue
+		}
+		addTraceTraffic(trafficMap, ts, nil, o)
+	}
+
+	return trafficMap
+}
+
+// addTraceTraffic adds the request from the source span to the dest span. If dest is nil the request ends at
+// the destination service of the source span.
+func addTraceTraffic(trafficMap graph.TrafficMap, source, dest *traceSpan, o graph.TelemetryOptions) {
+	// the request outcome and duration are best reported by the destination, when it is known
+	reporter := source
+	if dest != nil {
+		reporter = dest
+	}
+	protocol, code, flags := spanResponse(reporter.span)
+	if protocol == "" {
+		protocol, code, flags = spanResponse(source.span)
+	}
+	responseTime := float64(reporter.span.Duration) / 1000.0 // micros to millis
+	host := source.serviceHost
+
+	svcNs, svcName := graph.Unknown, ""
+	if host != "" {
+		svcNs, svcName = hostService(host)
+	}
+
+	if dest == nil {
+		destNode, _ := addNode(trafficMap, graph.Unknown, svcNs, svcName, "", "", "", "", o)
+		addTraceEdgeTraffic(source.node, destNode, protocol, code, flags, host, responseTime)
+		return
+	}
+
+	// don't inject a service node if the destination service is not known or the dest node is already a service node
+	if o.InjectServiceNodes && svcName != "" && dest.node.NodeType != graph.NodeTypeService {
+		injectedService, _ := addNode(trafficMap, graph.Unknown, svcNs, svcName, "", "", "", "", o)
+		addTraceEdgeTraffic(source.node, injectedService, protocol, code, flags, host, responseTime)
+		addToDestServices(injectedService.Metadata, graph.Unknown, svcNs, svcName)
+		addTraceEdgeTraffic(injectedService, dest.node, protocol, code, flags, host, responseTime)
+	} else {
+		addTraceEdgeTraffic(source.node, dest.node, protocol, code, flags, host, responseTime)
+	}
+	if svcName != "" {
+		addToDestServices(dest.node.Metadata, graph.Unknown, svcNs, svcName)
+	}
+}
+
+// addTraceEdgeTraffic adds a single request to the edge, the edge response time is the longest request duration
+func addTraceEdgeTraffic(source, dest *graph.Node, protocol, code, flags, host string, responseTime float64) {
+	var edge *graph.Edge
+	for _, e := range source.Edges {
+		if dest.ID == e.Dest.ID && e.Metadata[graph.ProtocolKey] == protocol {
+			edge = e
+			break
+		}
+	}
+	if nil == edge {
+		edge = source.AddEdge(dest)
+		edge.Metadata[graph.ProtocolKey] = protocol
+	}
+
+	graph.AddToMetadata(protocol, 1.0, code, flags, host, source.Metadata, dest.Metadata, edge.Metadata)
+	if val, ok := edge.Metadata[graph.ResponseTime]; !ok || responseTime > val.(float64) {
+		edge.Metadata[graph.ResponseTime] = responseTime
+	}
+}
+
+// nodeAncestor returns the closest ancestor span attributed to a node, or nil if there is none
+func nodeAncestor(spans map[jaegerModels.SpanID]*traceSpan, ts *traceSpan) *traceSpan {
+	// protect against malformed traces, an ancestor chain can not be longer than the trace
+	for i := 0; i < len(spans); i++ {
+		parent, ok := spans[parentSpanID(ts.span)]
+		if !ok {
+			return nil
+		}
+		if parent.node != nil {
+			return parent
+		}
+		ts = parent
+	}
+	return nil
+}
+
+// parentSpanID returns the ID of the parent span, or an empty ID for the root span
+func parentSpanID(span *jaegerModels.Span) jaegerModels.SpanID {
+	for _, ref := range span.References {
+		if ref.RefType == jaegerModels.ChildOf && ref.TraceID == span.TraceID {
+			return ref.SpanID
+		}
+	}
+	return span.ParentSpanID
+}
+
+// spanIdentity returns the namespace, app, version and pod of the proxy (or application) reporting the span. The
+// namespace is empty if the span can not be attributed to a node.
+func spanIdentity(span *jaegerModels.Span) (namespace, app, version, pod string) {
+	tags := tagValues(span.Tags)
+	namespace = tags["istio.namespace"]
+	app = tags["istio.canonical_service"]
+	version = tags["istio.canonical_revision"]
+
+	// For envoy traces, with a workload named "ai-locals", node_id is like:
+	// sidecar~172.17.0.20~ai-locals-6d8996bff-ztg6z.default~default.svc.cluster.local
+	if nodeID, ok := tags["node_id"]; ok {
+		parts := strings.Split(nodeID, "~")
+		if len(parts) >= 3 {
+			if i := strings.LastIndex(parts[2], "."); i > 0 {
+				pod = parts[2][:i]
+				if namespace == "" {
+					namespace = parts[2][i+1:]
+				}
+			}
+		}
+	}
+
+	// Tags not found => try with the process. The proxy service name is like app.namespace, and the
+	// hostname is the pod name.
+	if span.Process != nil {
+		if pod == "" {
+			pod = tagValues(span.Process.Tags)["hostname"]
+		}
+		if i := strings.LastIndex(span.Process.ServiceName, "."); i > 0 {
+			if app == "" {
+				app = span.Process.ServiceName[:i]
+			}
+			if namespace == "" {
+				namespace = span.Process.ServiceName[i+1:]
+			}
+		}
+	}
+
+	if app == "" {
+		app = graph.Unknown
+	}
+	if version == "" {
+		version = graph.Unknown
+	}
+	return namespace, app, version, pod
+}
+
+// spanServiceHost returns the destination service host of an outbound client span, otherwise an empty string
+func spanServiceHost(span *jaegerModels.Span) string {
+	tags := tagValues(span.Tags)
+	if tags["span.kind"] != "client" {
+		return ""
+	}
+	// upstream_cluster is direction|port|subset|host
+	upstreamCluster := strings.Split(tags["upstream_cluster"], "|")
+	if len(upstreamCluster) != 4 || upstreamCluster[0] != "outbound" {
+		return ""
+	}
+	return upstreamCluster[3]
+}
+
+// spanResponse returns the protocol, response code and response flags reported by the span. The protocol is
+// empty if the span does not report a response.
+func spanResponse(span *jaegerModels.Span) (protocol, code, flags string) {
+	tags := tagValues(span.Tags)
+
+	flags = "-"
+	if val, ok := tags["response_flags"]; ok {
+		flags = val
+	}
+	if val, ok := tags["grpc.status_code"]; ok {
+		return "grpc", val, flags
+	}
+	if val, ok := tags["http.status_code"]; ok {
+		return "http", val, flags
+	}
+	return "", "-", flags
+}
+
+// hostService returns the namespace and name of the service of a host. The namespace is unknown for hosts
+// outside of the cluster (e.g. ServiceEntry hosts), the name is then the host.
+func hostService(host string) (namespace, name string) {
+	parts := strings.Split(host, ".")
+	if len(parts) >= 3 && parts[2] == "svc" {
+		return parts[1], parts[0]
+	}
+	return graph.Unknown, host
+}
+
+func tagValues(tags []jaegerModels.KeyValue) map[string]string {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		values[tag.Key] = fmt.Sprintf("%v", tag.Value)
+	}
+	return values
+}
+
+// newWorkloadResolver returns a resolver matching the pod name with the workload names of the pod namespace.
+// The workloads are fetched once per accessible namespace.
+func newWorkloadResolver(business *business.Layer, o graph.TelemetryOptions) workloadResolver {
+	// key=namespace
+	workloads := make(map[string][]string)
+
+	return func(namespace, pod string) string {
+		names, ok := workloads[namespace]
+		if !ok {
+			names = []string{}
+			if _, accessible := o.AccessibleNamespaces[namespace]; accessible && business != nil {
+				workloadList, err := business.Workload.GetWorkloadList(namespace, false)
+				if err != nil {
+					log.Warningf("Unable to resolve the workloads of namespace [%s]: %v", namespace, err)
+				} else {
+					for _, w := range workloadList.Workloads {
+						names = append(names, w.Name)
+					}
+				}
+			}
+			workloads[namespace] = names
+		}
+		return podWorkload(pod, names)
+	}
+}
+
+// podWorkload returns the workload with the longest name prefixing the pod name, e.g. the pod
+// reviews-v1-6d8996bff-ztg6z of the Deployment reviews-v1. If no workload matches (e.g. the namespace
+// is not accessible) the Deployment pod naming convention is assumed: <workload>-<pod-template-hash>-<suffix>
+func podWorkload(pod string, workloads []string) string {
+	workload := ""
+	for _, name := range workloads {
+		if (pod == name || strings.HasPrefix(pod, name+"-")) && len(name) > len(workload) {
+			workload = name
+		}
+	}
+	if workload != "" {
+		return workload
+	}
+
+	parts := strings.Split(pod, "-")
+	if len(parts) > 2 {
+		return strings.Join(parts[:len(parts)-2], "-")
+	}
+	return pod
+}
@@ -0,0 +1,176 @@
+package istio
+
+import (
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestBuildTraceTrafficMap(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.TelemetryOptions{
+		CommonOptions: graph.CommonOptions{
+			GraphType: graph.GraphTypeWorkload,
+		},
+	}
+	trafficMap := buildTraceTrafficMap(traceTestTrace(), o, traceTestResolver)
+
+	gatewayID, _ := graph.Id(graph.Unknown, "istio-system", "", "istio-system", "istio-ingressgateway", "istio-ingressgateway", "latest", o.GraphType)
+	productpageID, _ := graph.Id(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", o.GraphType)
+	reviewsID, _ := graph.Id(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v2", "reviews", "v2", o.GraphType)
+	ratingsID, _ := graph.Id(graph.Unknown, "bookinfo", "", "bookinfo", "ratings-v1", "ratings", "v1", o.GraphType)
+	detailsID, _ := graph.Id(graph.Unknown, "bookinfo", "", "bookinfo", "details-v1", "details", "v1", o.GraphType)
+	googleID, _ := graph.Id(graph.Unknown, graph.Unknown, "www.google.com", "", "", "", "", o.GraphType)
+
+	assert.Equal(6, len(trafficMap))
+
+	gateway, ok := trafficMap[gatewayID]
+	assert.True(ok)
+	assert.Equal(1, len(gateway.Edges))
+	assert.Equal(productpageID, gateway.Edges[0].Dest.ID)
+	assert.Equal(45.0, gateway.Edges[0].Metadata[graph.ResponseTime])
+
+	productpage, ok := trafficMap[productpageID]
+	assert.True(ok)
+	assert.Equal(3, len(productpage.Edges))
+	for _, e := range productpage.Edges {
+		assert.Equal("http", e.Metadata[graph.ProtocolKey])
+		switch e.Dest.ID {
+		case reviewsID:
+			assert.Equal(28.0, e.Metadata[graph.ResponseTime])
+			assert.Equal(1.0, e.Metadata[graph.MetadataKey("http")])
+		case detailsID:
+			assert.Equal(6.0, e.Metadata[graph.ResponseTime])
+		case googleID:
+			assert.Equal(graph.NodeTypeService, e.Dest.NodeType)
+			assert.Equal(10.0, e.Metadata[graph.ResponseTime])
+		default:
+			assert.Fail("Unexpected edge dest: " + e.Dest.ID)
+		}
+	}
+
+	reviews, ok := trafficMap[reviewsID]
+	assert.True(ok)
+	assert.Equal(1, len(reviews.Edges))
+	assert.Equal(ratingsID, reviews.Edges[0].Dest.ID)
+	// the ratings service was called twice, the slowest request is reported
+	assert.Equal(2.0, reviews.Edges[0].Metadata[graph.MetadataKey("http")])
+	assert.Equal(4.0, reviews.Edges[0].Metadata[graph.ResponseTime])
+
+	ratings, ok := trafficMap[ratingsID]
+	assert.True(ok)
+	assert.Equal(0, len(ratings.Edges))
+	destServices, ok := ratings.Metadata[graph.DestServices]
+	assert.True(ok)
+	ratingsService := graph.ServiceName{Cluster: graph.Unknown, Namespace: "bookinfo", Name: "ratings"}
+	_, ok = destServices.(graph.DestServicesMetadata)[ratingsService.Key()]
+	assert.True(ok)
+}
+
+func TestBuildTraceTrafficMapInjectServiceNodes(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.TelemetryOptions{
+		InjectServiceNodes: true,
+		CommonOptions: graph.CommonOptions{
+			GraphType: graph.GraphTypeVersionedApp,
+		},
+	}
+	trafficMap := buildTraceTrafficMap(traceTestTrace(), o, traceTestResolver)
+
+	productpageID, _ := graph.Id(graph.Unknown, "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", o.GraphType)
+	reviewsServiceID, _ := graph.Id(graph.Unknown, "bookinfo", "reviews", "", "", "", "", o.GraphType)
+	reviewsID, _ := graph.Id(graph.Unknown, "bookinfo", "", "bookinfo", "reviews-v2", "reviews", "v2", o.GraphType)
+
+	// the gateway, productpage, reviews, ratings and details nodes, their services, and the google.com service
+	assert.Equal(10, len(trafficMap))
+
+	productpage, ok := trafficMap[productpageID]
+	assert.True(ok)
+	assert.Equal(graph.NodeTypeApp, productpage.NodeType)
+	assert.Equal(3, len(productpage.Edges))
+
+	reviewsService, ok := trafficMap[reviewsServiceID]
+	assert.True(ok)
+	assert.Equal(1, len(reviewsService.Edges))
+	assert.Equal(reviewsID, reviewsService.Edges[0].Dest.ID)
+	assert.Equal(28.0, reviewsService.Edges[0].Metadata[graph.ResponseTime])
+}
+
+func TestPodWorkload(t *testing.T) {
+	assert := assert.New(t)
+
+	workloads := []string{"reviews", "reviews-v1", "ratings-v1"}
+	assert.Equal("reviews-v1", podWorkload("reviews-v1-6d8996bff-ztg6z", workloads))
+	assert.Equal("reviews", podWorkload("reviews-6d8996bff-ztg6z", workloads))
+	assert.Equal("ratings-v1", podWorkload("ratings-v1", workloads))
+	assert.Equal("details-v1", podWorkload("details-v1-6d8996bff-ztg6z", workloads))
+	assert.Equal("mysqldb", podWorkload("mysqldb", workloads))
+}
+
+func traceTestResolver(namespace, pod string) string {
+	return podWorkload(pod, []string{"details-v1", "productpage-v1", "ratings-v1", "reviews-v2"})
+}
+
+// traceTestTrace returns a bookinfo trace:
+//   ingressgateway -> productpage -> reviews -> ratings (twice, once failing)
+//                                 -> details (through an application span)
+//                                 -> www.google.com (outside of the mesh)
+func traceTestTrace() jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID: "t1",
+		Spans: []jaegerModels.Span{
+			traceTestSpan("s1", "", "p1", 50000, traceTestProxyTags("client", "istio-system", "istio-ingressgateway", "latest", "istio-ingressgateway-7c8f6b9d4-abcde", "outbound|9080||productpage.bookinfo.svc.cluster.local", 200)),
+			traceTestSpan("s2", "s1", "p2", 45000, traceTestProxyTags("server", "bookinfo", "productpage", "v1", "productpage-v1-6b746f74dc-9stvs", "inbound|9080||", 200)),
+			traceTestSpan("s3", "s2", "p2", 30000, traceTestProxyTags("client", "bookinfo", "productpage", "v1", "productpage-v1-6b746f74dc-9stvs", "outbound|9080||reviews.bookinfo.svc.cluster.local", 200)),
+			traceTestSpan("s4", "s3", "p3", 28000, traceTestProxyTags("server", "bookinfo", "reviews", "v2", "reviews-v2-7bf8c9648f-2xkrk", "inbound|9080||", 200)),
+			traceTestSpan("s5", "s4", "p3", 5000, traceTestProxyTags("client", "bookinfo", "reviews", "v2", "reviews-v2-7bf8c9648f-2xkrk", "outbound|9080||ratings.bookinfo.svc.cluster.local", 500)),
+			traceTestSpan("s6", "s5", "p4", 4000, traceTestProxyTags("server", "bookinfo", "ratings", "v1", "ratings-v1-b6994bb9-gr4jc", "inbound|9080||", 500)),
+			traceTestSpan("s7", "s4", "p3", 3000, traceTestProxyTags("client", "bookinfo", "reviews", "v2", "reviews-v2-7bf8c9648f-2xkrk", "outbound|9080||ratings.bookinfo.svc.cluster.local", 200)),
+			traceTestSpan("s8", "s7", "p4", 2000, traceTestProxyTags("server", "bookinfo", "ratings", "v1", "ratings-v1-b6994bb9-gr4jc", "inbound|9080||", 200)),
+			traceTestSpan("s9", "s2", "p2", 10000, traceTestProxyTags("client", "bookinfo", "productpage", "v1", "productpage-v1-6b746f74dc-9stvs", "outbound|443||www.google.com", 200)),
+			traceTestSpan("s10", "s2", "app", 8000, nil),
+			traceTestSpan("s11", "s10", "p2", 7000, traceTestProxyTags("client", "bookinfo", "productpage", "v1", "productpage-v1-6b746f74dc-9stvs", "outbound|9080||details.bookinfo.svc.cluster.local", 200)),
+			traceTestSpan("s12", "s11", "p5", 6000, traceTestProxyTags("server", "bookinfo", "details", "v1", "details-v1-79f774bdb9-wq7hp", "inbound|9080||", 200)),
+		},
+		Processes: map[jaegerModels.ProcessID]jaegerModels.Process{
+			"app": {ServiceName: "productpage"},
+			"p1":  {ServiceName: "istio-ingressgateway"},
+			"p2":  {ServiceName: "productpage.bookinfo"},
+			"p3":  {ServiceName: "reviews.bookinfo"},
+			"p4":  {ServiceName: "ratings.bookinfo"},
+			"p5":  {ServiceName: "details.bookinfo"},
+		},
+	}
+}
+
+func traceTestSpan(spanID, parentSpanID string, processID jaegerModels.ProcessID, duration uint64, tags []jaegerModels.KeyValue) jaegerModels.Span {
+	span := jaegerModels.Span{
+		TraceID:   "t1",
+		SpanID:    jaegerModels.SpanID(spanID),
+		ProcessID: processID,
+		Duration:  duration,
+		Tags:      tags,
+	}
+	if parentSpanID != "" {
+		span.References = []jaegerModels.Reference{{RefType: jaegerModels.ChildOf, TraceID: "t1", SpanID: jaegerModels.SpanID(parentSpanID)}}
+	}
+	return span
+}
+
+func traceTestProxyTags(kind, namespace, app, version, pod, upstreamCluster string, code int) []jaegerModels.KeyValue {
+	return []jaegerModels.KeyValue{
+		{Key: "span.kind", Type: jaegerModels.StringType, Value: kind},
+		{Key: "istio.namespace", Type: jaegerModels.StringType, Value: namespace},
+		{Key: "istio.canonical_service", Type: jaegerModels.StringType, Value: app},
+		{Key: "istio.canonical_revision", Type: jaegerModels.StringType, Value: version},
+		{Key: "node_id", Type: jaegerModels.StringType, Value: "sidecar~172.17.0.20~" + pod + "." + namespace + "~" + namespace + ".svc.cluster.local"},
+		{Key: "upstream_cluster", Type: jaegerModels.StringType, Value: upstreamCluster},
+		{Key: "http.status_code", Type: jaegerModels.Int64Type, Value: code},
+		{Key: "response_flags", Type: jaegerModels.StringType, Value: "-"},
+	}
+}
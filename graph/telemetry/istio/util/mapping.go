@@ -0,0 +1,126 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+)
+
+// Graph queries are written using the Istio standard metric and label names. When a TelemetryMapping
+// is configured the queries are translated to the stored names before being submitted, and the labels of
+// the results are translated back, so that the rest of the graph generation is unaware of the mapping.
+
+var histogramSuffixes = []string{"_bucket", "_count", "_sum"}
+
+// MapQuery returns the query with every mapped metric or label name replaced. Only PromQL identifiers are
+// replaced, string literals (i.e. label values) are left untouched.
+func MapQuery(query string) string {
+	mapping := config.Get().ExternalServices.Istio.TelemetryMapping
+	if len(mapping.Labels) == 0 && len(mapping.Metrics) == 0 {
+		return query
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := endOfString(query, i)
+			sb.WriteString(query[i:end])
+			i = end
+		case isIdentifierStart(c):
+			end := i + 1
+			for end < len(query) && isIdentifierChar(query[end]) {
+				end++
+			}
+			sb.WriteString(mapName(mapping, query[i:end]))
+			i = end
+		case c >= '0' && c <= '9':
+			// skip numbers and durations (e.g. 1e3, 60s) so that their letters are not taken as identifiers
+			end := i + 1
+			for end < len(query) && isIdentifierChar(query[end]) {
+				end++
+			}
+			sb.WriteString(query[i:end])
+			i = end
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// UnmapVector replaces, in place, every mapped label name in the vector with its Istio standard name.
+func UnmapVector(vector model.Vector) model.Vector {
+	mapping := config.Get().ExternalServices.Istio.TelemetryMapping
+	if len(mapping.Labels) == 0 {
+		return vector
+	}
+
+	for _, s := range vector {
+		unmapMetric(mapping, s.Metric)
+	}
+	return vector
+}
+
+// UnmapMatrix replaces, in place, every mapped label name in the matrix with its Istio standard name.
+func UnmapMatrix(matrix model.Matrix) model.Matrix {
+	mapping := config.Get().ExternalServices.Istio.TelemetryMapping
+	if len(mapping.Labels) == 0 {
+		return matrix
+	}
+
+	for _, s := range matrix {
+		unmapMetric(mapping, s.Metric)
+	}
+	return matrix
+}
+
+func unmapMetric(mapping config.TelemetryMapping, metric model.Metric) {
+	for istioName, name := range mapping.Labels {
+		if value, ok := metric[model.LabelName(name)]; ok && name != istioName {
+			delete(metric, model.LabelName(name))
+			metric[model.LabelName(istioName)] = value
+		}
+	}
+}
+
+func mapName(mapping config.TelemetryMapping, name string) string {
+	if mapped, ok := mapping.Metrics[name]; ok {
+		return mapped
+	}
+	for _, suffix := range histogramSuffixes {
+		if mapped, ok := mapping.Metrics[strings.TrimSuffix(name, suffix)]; ok && strings.HasSuffix(name, suffix) {
+			return mapped + suffix
+		}
+	}
+	if mapped, ok := mapping.Labels[name]; ok {
+		return mapped
+	}
+	return name
+}
+
+// endOfString returns the index following the string literal starting at start
+func endOfString(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case query[i] == '\\' && quote != '`':
+			i++
+		case query[i] == quote:
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
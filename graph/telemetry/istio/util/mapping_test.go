@@ -0,0 +1,91 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func setupMapping() {
+	conf := config.NewConfig()
+	conf.ExternalServices.Istio.TelemetryMapping = config.TelemetryMapping{
+		Labels: map[string]string{
+			"destination_service":       "dst_service",
+			"source_workload_namespace": "src_namespace",
+		},
+		Metrics: map[string]string{
+			"istio_requests_total":                "mesh_requests_total",
+			"istio_request_duration_milliseconds": "mesh_request_duration_ms",
+		},
+	}
+	config.Set(conf)
+}
+
+func TestMapQuery(t *testing.T) {
+	assert := assert.New(t)
+	setupMapping()
+
+	query := `sum(rate(istio_requests_total{reporter="source",source_workload_namespace="bookinfo",destination_service=~"^.+\\.destination_service\\..+$"} [60s])) by (source_workload_namespace,destination_service,destination_service_name) > 0`
+	expected := `sum(rate(mesh_requests_total{reporter="source",src_namespace="bookinfo",dst_service=~"^.+\\.destination_service\\..+$"} [60s])) by (src_namespace,dst_service,destination_service_name) > 0`
+	assert.Equal(expected, MapQuery(query))
+
+	query = `histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{reporter="destination"}[60s])) by (le,destination_service)) > 0`
+	expected = `histogram_quantile(0.95, sum(rate(mesh_request_duration_ms_bucket{reporter="destination"}[60s])) by (le,dst_service)) > 0`
+	assert.Equal(expected, MapQuery(query))
+}
+
+func TestMapQueryNoMapping(t *testing.T) {
+	config.Set(config.NewConfig())
+
+	query := `sum(rate(istio_requests_total{reporter="source"} [60s])) by (destination_service) > 0`
+	assert.Equal(t, query, MapQuery(query))
+}
+
+func TestUnmapVector(t *testing.T) {
+	assert := assert.New(t)
+	setupMapping()
+
+	vector := model.Vector{
+		&model.Sample{
+			Metric: model.Metric{
+				"src_namespace":            "bookinfo",
+				"dst_service":              "reviews.bookinfo.svc.cluster.local",
+				"destination_service_name": "reviews",
+			},
+			Value: 10,
+		},
+	}
+
+	vector = UnmapVector(vector)
+	assert.Equal(model.Metric{
+		"source_workload_namespace": "bookinfo",
+		"destination_service":       "reviews.bookinfo.svc.cluster.local",
+		"destination_service_name":  "reviews",
+	}, vector[0].Metric)
+}
+
+func TestUnmapMatrix(t *testing.T) {
+	assert := assert.New(t)
+	setupMapping()
+
+	matrix := model.Matrix{
+		&model.SampleStream{
+			Metric: model.Metric{
+				"src_namespace":            "bookinfo",
+				"dst_service":              "reviews.bookinfo.svc.cluster.local",
+				"destination_service_name": "reviews",
+			},
+			Values: []model.SamplePair{{Timestamp: 0, Value: 10}},
+		},
+	}
+
+	matrix = UnmapMatrix(matrix)
+	assert.Equal(model.Metric{
+		"source_workload_namespace": "bookinfo",
+		"destination_service":       "reviews.bookinfo.svc.cluster.local",
+		"destination_service_name":  "reviews",
+	}, matrix[0].Metric)
+}
@@ -84,41 +84,65 @@ func HandleResponseCode(protocol, responseCode string, grpcResponseStatusOk bool
 	return grpcResponseStatus
 }
 
+// The reasons a telemetry sample is dropped from the graph, see graph.DroppedTelemetry
+const (
+	DroppedBadDestCluster    = "badDestCluster"    // destination_cluster is provided but unknown
+	DroppedBadDestService    = "badDestService"    // destination_service is an IP address, without a workload
+	DroppedBadSourceCluster  = "badSourceCluster"  // source_cluster is provided but unknown
+	DroppedBadSourceWorkload = "badSourceWorkload" // neither source_workload nor source_canonical_service are set
+	DroppedMissingHTTPLabels = "missingHTTPLabels" // request_protocol or response_code is missing
+	DroppedMissingLabels     = "missingLabels"     // an expected label is missing
+)
+
 // IsBadSourceTelemetry tests for known issues in generated telemetry given indicative label values.
+// See BadSourceTelemetryReason.
+func IsBadSourceTelemetry(cluster string, clusterOK bool, ns, wl, app string) bool {
+	return BadSourceTelemetryReason(cluster, clusterOK, ns, wl, app) != ""
+}
+
+// BadSourceTelemetryReason tests for known issues in generated telemetry given indicative label values,
+// it returns the reason to drop the telemetry, or "" if the telemetry is ok.
 // 1) source namespace is ok but neither workload nor app are set
 // 2) source namespace is ok and source_cluster is provided but not ok.
 // 3) no more conditions known
-func IsBadSourceTelemetry(cluster string, clusterOK bool, ns, wl, app string) bool {
+func BadSourceTelemetryReason(cluster string, clusterOK bool, ns, wl, app string) string {
 	// case1
 	if graph.IsOK(ns) && !graph.IsOK(wl) && !graph.IsOK(app) {
 		log.Debugf("Skipping bad source telemetry [case 1] [%s] [%s] [%s]", ns, wl, app)
-		return true
+		return DroppedBadSourceWorkload
 	}
 	// case2
 	if graph.IsOK(ns) && clusterOK && !graph.IsOK(cluster) {
 		log.Debugf("Skipping bad source telemetry [case 2] [%s] [%s] [%s] [%s]", ns, wl, app, cluster)
-		return true
+		return DroppedBadSourceCluster
 	}
 
-	return false
+	return ""
 }
 
 // IsBadDestTelemetry tests for known issues in generated telemetry given indicative label values.
+// See BadDestTelemetryReason.
+func IsBadDestTelemetry(cluster string, clusterOK bool, svcNs, svc, svcName, wl string) bool {
+	return BadDestTelemetryReason(cluster, clusterOK, svcNs, svc, svcName, wl) != ""
+}
+
+// BadDestTelemetryReason tests for known issues in generated telemetry given indicative label values,
+// it returns the reason to drop the telemetry, or "" if the telemetry is ok.
 // 1) During pod lifecycle changes incomplete telemetry may be generated that results in
 //    destSvc == destSvcName and no dest workload, where destSvc[Name] is in the form of an IP address.
 // 2) destSvcNs is ok and destCluster is provided but not ok
 // 3) no more conditions known
-func IsBadDestTelemetry(cluster string, clusterOK bool, svcNs, svc, svcName, wl string) bool {
+func BadDestTelemetryReason(cluster string, clusterOK bool, svcNs, svc, svcName, wl string) string {
 	// case1
 	failsEqualsTest := (!graph.IsOK(wl) && graph.IsOK(svc) && graph.IsOK(svcName) && (svc == svcName))
 	if failsEqualsTest && badServiceMatcher.MatchString(svcName) {
 		log.Debugf("Skipping bad dest telemetry [case 1] [%s] [%s] [%s]", svc, svcName, wl)
-		return true
+		return DroppedBadDestService
 	}
 	// case2
 	if graph.IsOK(svcNs) && clusterOK && !graph.IsOK(cluster) {
 		log.Debugf("Skipping bad dest telemetry [case 2] [%s] [%s]", svcNs, cluster)
-		return true
+		return DroppedBadDestCluster
 	}
-	return false
+	return ""
 }
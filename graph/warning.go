@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"fmt"
+	nethttp "net/http"
+	"runtime/debug"
+
+	"github.com/kiali/kiali/log"
+)
+
+// Warning reports a part of the graph that failed to generate. A namespace whose traffic can't be queried, or an
+// appender failing for a namespace, does not fail the graph, which is returned without the failed part. A cluster
+// Prometheus failing a namespace traffic query is reported with the cluster, the namespace traffic holds the
+// telemetry of the other clusters.
+type Warning struct {
+	Appender  string `json:"appender,omitempty"` // the failed appender, unset if the namespace traffic failed
+	Cluster   string `json:"cluster,omitempty"`  // the failed cluster Prometheus, unset if every cluster failed
+	Code      int    `json:"code"`               // the HTTP status code the failure would have returned
+	Message   string `json:"message"`
+	Namespace string `json:"namespace"`
+}
+
+// AddWarning adds the warning, unless the same warning was already added
+func (in *AppenderGlobalInfo) AddWarning(warning Warning) {
+	for _, w := range in.Warnings {
+		if w == warning {
+			return
+		}
+	}
+	in.Warnings = append(in.Warnings, warning)
+}
+
+// CatchWarning runs f, recovering a graph generation panic as a Warning for the namespace and, if set, the appender.
+// It returns false if f failed. A BadRequest panic is not recovered, the request can't succeed.
+func (in *AppenderGlobalInfo) CatchWarning(namespace, appender string, f func()) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			message, code := PanicMessage(r)
+			if code == nethttp.StatusBadRequest {
+				panic(r)
+			}
+			if code == nethttp.StatusInternalServerError {
+				log.Errorf("Graph generation failed for namespace [%s] appender [%s]: %s: %s", namespace, appender, message, debug.Stack())
+			} else {
+				log.Warningf("Graph generation failed for namespace [%s] appender [%s]: %s", namespace, appender, message)
+			}
+			in.Warnings = append(in.Warnings, Warning{
+				Appender:  appender,
+				Code:      code,
+				Message:   message,
+				Namespace: namespace,
+			})
+			ok = false
+		}
+	}()
+
+	f()
+	return true
+}
+
+// PanicMessage returns the message and the HTTP status code of a graph generation panic
+func PanicMessage(r interface{}) (message string, code int) {
+	switch err := r.(type) {
+	case string:
+		return err, nethttp.StatusInternalServerError
+	case error:
+		return err.Error(), nethttp.StatusInternalServerError
+	case func() string:
+		return err(), nethttp.StatusInternalServerError
+	case Response:
+		return err.Message, err.Code
+	default:
+		return fmt.Sprintf("%v", r), nethttp.StatusInternalServerError
+	}
+}
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"errors"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatchWarning(t *testing.T) {
+	assert := assert.New(t)
+
+	globalInfo := NewAppenderGlobalInfo()
+
+	assert.True(globalInfo.CatchWarning("bookinfo", "", func() {}))
+	assert.False(globalInfo.CatchWarning("bookinfo", "", func() { Panic("Prometheus timeout", nethttp.StatusServiceUnavailable) }))
+	assert.False(globalInfo.CatchWarning("tutorial", "responseTime", func() { CheckError(errors.New("query failed")) }))
+	assert.Equal([]Warning{
+		{Code: nethttp.StatusServiceUnavailable, Message: "Prometheus timeout", Namespace: "bookinfo"},
+		{Appender: "responseTime", Code: nethttp.StatusInternalServerError, Message: "query failed", Namespace: "tutorial"},
+	}, globalInfo.Warnings)
+
+	// a bad request fails the graph
+	assert.Panics(func() {
+		globalInfo.CatchWarning("bookinfo", "", func() { BadRequest("invalid") })
+	})
+	assert.Equal(2, len(globalInfo.Warnings))
+}
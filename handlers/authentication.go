@@ -605,6 +605,9 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 		statusCode := http.StatusOK
 		conf := config.Get()
 
+		// The user is set by the session checks below, never by the client
+		r.Header.Del("Kiali-User")
+
 		var authInfo *api.AuthInfo
 		var token string
 
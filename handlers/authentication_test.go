@@ -596,3 +596,21 @@ func IsValidUUID(uuid string) bool {
 	r := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-[0-5][0-9a-f]{3}-[089ab][0-9a-f]{3}-[0-9a-f]{12}$")
 	return r.MatchString(uuid)
 }
+
+// TestAuthenticationHandlerDropsClientUser checks that the client can't set the user of the request
+func TestAuthenticationHandlerDropsClientUser(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyAnonymous
+	config.Set(cfg)
+
+	user := "unset"
+	handler := AuthenticationHandler{saToken: "kiali"}.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = getUser(r)
+	}))
+
+	request := httptest.NewRequest("DELETE", "http://kiali/api/graph/snapshots/0123456789abcdef0123456789abcdef", nil)
+	request.Header.Set("Kiali-User", "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	assert.Equal(t, "", user)
+}
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
+	"time"
+
+	"github.com/kiali/kiali/log"
 )
 
 type responseError struct {
@@ -34,6 +38,25 @@ func RespondWithJSONIndent(w http.ResponseWriter, code int, payload interface{})
 	_, _ = w.Write(response)
 }
 
+func RespondWithXMLIndent(w http.ResponseWriter, code int, payload interface{}) {
+	response, err := xml.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(response)
+}
+
+func RespondWithText(w http.ResponseWriter, code int, contentType, payload string) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(payload))
+}
+
 func RespondWithError(w http.ResponseWriter, code int, message string) {
 	RespondWithJSON(w, code, responseError{Error: message})
 }
@@ -45,3 +68,10 @@ func RespondWithDetailedError(w http.ResponseWriter, code int, message, detail s
 func RespondWithCode(w http.ResponseWriter, code int) {
 	w.WriteHeader(code)
 }
+
+// clearWriteDeadline lifts the server WriteTimeout for a long-lived response, over HTTP/1 and HTTP/2
+func clearWriteDeadline(w http.ResponseWriter) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warningf("Cannot lift the write timeout of the response: %v", err)
+	}
+}
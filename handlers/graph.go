@@ -13,27 +13,42 @@ package handlers
 //              configuration returned to the caller.
 //
 // The current Handlers:
-//   GraphNamespaces: Generate a graph for one or more requested namespaces.
-//   GraphNode:       Generate a graph for a specific node, detailing the immediate incoming and outgoing traffic.
+//   GraphNamespaces:       Generate a graph for one or more requested namespaces.
+//   GraphNamespacesDiff:   Generate a namespaces graph marking the changes from the same graph at a baseline queryTime.
+//   GraphNamespacesPaths:  Generate a namespaces graph and return every request path between the source and dest nodes.
+//   GraphNamespacesStream: Stream namespaces graph updates as Server-Sent Events, regenerating the graph every refreshInterval.
+//   GraphNode:             Generate a graph for a specific node, detailing the immediate incoming and outgoing traffic.
+//   GraphSnapshots:        Save a namespaces graph as a snapshot, and list, fetch or delete the saved snapshots.
+//   GraphTrace:            Generate a graph of the requests traversed by a single Jaeger trace.
 //
 // The handlers accept the following query parameters (see notes below)
 //   appenders:       Comma-separated list of TelemetryVendor-specific appenders to run. (default: all)
-//   configVendor:    default: cytoscape
+//   baselineOffset:  Used only for graph diffs, the baseline queryTime is queryTime-baselineOffset (default: 1h)
+//   configVendor:    cytoscape | dot | graphml (default: cytoscape)
+//   dest:            Used only for graph paths, the ID of the path destination node
 //   duration:        time.Duration indicating desired query range duration, (default: 10m)
+//   find:            Find expression (UI Graph Find syntax), matching nodes or edges are marked (default: none)
 //   graphType:       Determines how to present the telemetry data. app | service | versionedApp | workload (default: workload)
-//   boxBy:           If supported by vendor, visually box by a specified node attribute (default: none)
+//   boxBy:           If supported by vendor, visually box by a specified node attribute or configured workload label (default: none)
+//   hide:            Hide expression (UI Graph Hide syntax), matching nodes or edges are removed (default: none)
+//   name:            Used only for graph snapshots, an optional description of the snapshot
 //   namespaces:      Comma-separated list of namespace names to use in the graph. Will override namespace path param
 //   queryTime:       Unix time (seconds) for query such that range is queryTime-duration..queryTime (default now)
+//   refreshInterval: Used only for graph streams, time.Duration between graph updates (default: UI refresh interval)
+//   source:          Used only for graph paths, the ID of the path source node
 //   TelemetryVendor: default: istio
 //
 //  Note: some handlers may ignore some query parameters.
 //  Note: vendors may support additional, vendor-specific query parameters.
 //
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime/debug"
 
+	"github.com/gorilla/mux"
+
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/api"
 	"github.com/kiali/kiali/log"
@@ -49,7 +64,75 @@ func GraphNamespaces(w http.ResponseWriter, r *http.Request) {
 	graph.CheckError(err)
 
 	code, payload := api.GraphNamespaces(business, o)
-	respond(w, code, payload)
+	respond(w, o.ConfigVendor, code, payload)
+}
+
+// GraphNamespacesDiff is a REST http.HandlerFunc handling graph diff generation for 1 or more namespaces
+func GraphNamespacesDiff(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewDiffOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphNamespacesDiff(business, o)
+	respond(w, o.ConfigVendor, code, payload)
+}
+
+// GraphNamespacesPaths is a REST http.HandlerFunc handling path analysis for a graph of 1 or more namespaces
+func GraphNamespacesPaths(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewPathOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphNamespacesPaths(business, o)
+	respond(w, o.ConfigVendor, code, payload)
+}
+
+// GraphNamespacesStream is a REST http.HandlerFunc streaming namespaces graph updates as Server-Sent Events. The
+// first event provides the full graph, subsequent events provide only the changes. A client reconnecting with
+// the Last-Event-ID header resumes the stream, if possible.
+func GraphNamespacesStream(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewStreamOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		graph.Error("Streaming is not supported by the connection")
+	}
+
+	// The stream outlives the server write timeout
+	clearWriteDeadline(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	api.GraphNamespacesStream(r.Context(), business, o, r.Header.Get("Last-Event-ID"), func(event, id string, payload interface{}) error {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if id != "" {
+			if _, err = fmt.Fprintf(w, "id: %s\n", id); err != nil {
+				return err
+			}
+		}
+		if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
 }
 
 // GraphNode is a REST http.HandlerFunc handling node-detail graph config generation.
@@ -62,26 +145,70 @@ func GraphNode(w http.ResponseWriter, r *http.Request) {
 	graph.CheckError(err)
 
 	code, payload := api.GraphNode(business, o)
-	respond(w, code, payload)
+	respond(w, o.ConfigVendor, code, payload)
+}
+
+// GraphNamespacesSnapshot is a REST http.HandlerFunc generating a namespaces graph and saving it as a snapshot
+func GraphNamespacesSnapshot(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewSnapshotOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphNamespacesSnapshot(business, o, getUser(r))
+	RespondWithJSON(w, code, payload)
+}
+
+// GraphSnapshots is a REST http.HandlerFunc listing the saved graph snapshots
+func GraphSnapshots(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphSnapshots(business)
+	RespondWithJSON(w, code, payload)
+}
+
+// GraphSnapshot is a REST http.HandlerFunc fetching a saved graph snapshot
+func GraphSnapshot(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphSnapshot(business, mux.Vars(r)["snapshot"])
+	RespondWithJSONIndent(w, code, payload)
+}
+
+// GraphSnapshotDelete is a REST http.HandlerFunc deleting a saved graph snapshot
+func GraphSnapshotDelete(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	RespondWithCode(w, api.DeleteGraphSnapshot(business, mux.Vars(r)["snapshot"], getUser(r)))
+}
+
+// GraphTrace is a REST http.HandlerFunc handling trace graph config generation.
+func GraphTrace(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewTraceOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphTrace(business, o)
+	respond(w, o.ConfigVendor, code, payload)
 }
 
 func handlePanic(w http.ResponseWriter) {
-	code := http.StatusInternalServerError
 	if r := recover(); r != nil {
-		var message string
-		switch err := r.(type) {
-		case string:
-			message = err
-		case error:
-			message = err.Error()
-		case func() string:
-			message = err()
-		case graph.Response:
-			message = err.Message
-			code = err.Code
-		default:
-			message = fmt.Sprintf("%v", r)
-		}
+		message, code := graph.PanicMessage(r)
 		if code == http.StatusInternalServerError {
 			stack := debug.Stack()
 			log.Errorf("%s: %s", message, stack)
@@ -92,9 +219,16 @@ func handlePanic(w http.ResponseWriter) {
 	}
 }
 
-func respond(w http.ResponseWriter, code int, payload interface{}) {
+func respond(w http.ResponseWriter, configVendor string, code int, payload interface{}) {
 	if code == http.StatusOK {
-		RespondWithJSONIndent(w, code, payload)
+		switch configVendor {
+		case graph.VendorDot:
+			RespondWithText(w, code, "text/vnd.graphviz", payload.(fmt.Stringer).String())
+		case graph.VendorGraphML:
+			RespondWithXMLIndent(w, code, payload)
+		default:
+			RespondWithJSONIndent(w, code, payload)
+		}
 		return
 	}
 	RespondWithError(w, code, payload.(string))
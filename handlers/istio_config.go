@@ -247,7 +247,7 @@ func checkObjectType(objectType string) bool {
 
 func audit(r *http.Request, message string) {
 	if config.Get().Server.AuditLog {
-		user := r.Header.Get("Kiali-User")
+		user := getUser(r)
 		log.Infof("AUDIT User [%s] Msg [%s]", user, message)
 	}
 }
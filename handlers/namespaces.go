@@ -28,12 +28,24 @@ func NamespaceList(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, namespaces)
 }
 
+const (
+	validationsFormatJUnit = "junit"
+	validationsFormatSarif = "sarif"
+)
+
 // NamespaceValidationSummary is the API handler to fetch validations summary to be displayed.
-// It is related to all the Istio Objects within the namespace
+// It is related to all the Istio Objects within the namespace. The "format" query param
+// returns the validations as a SARIF or JUnit report instead.
 func NamespaceValidationSummary(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	namespace := vars["namespace"]
 
+	format := r.URL.Query().Get("format")
+	if !checkValidationsFormat(format) {
+		RespondWithError(w, http.StatusBadRequest, "Validations format not supported: "+format)
+		return
+	}
+
 	business, err := getBusiness(r)
 	if err != nil {
 		log.Error(err)
@@ -41,17 +53,70 @@ func NamespaceValidationSummary(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var validationSummary models.IstioValidationSummary
-
 	istioConfigValidationResults, errValidations := business.Validations.GetValidations(namespace, "")
 	if errValidations != nil {
 		log.Error(errValidations)
 		RespondWithError(w, http.StatusInternalServerError, errValidations.Error())
-	} else {
-		validationSummary = istioConfigValidationResults.SummarizeValidation(namespace)
+		return
+	}
+
+	if format != "" {
+		respondWithValidationsReport(w, format, istioConfigValidationResults)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, istioConfigValidationResults.SummarizeValidation(namespace))
+}
+
+// MeshValidationSummary is the API handler to fetch the validations summary of every namespace.
+// The "format" query param returns the validations as a SARIF or JUnit report instead.
+func MeshValidationSummary(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if !checkValidationsFormat(format) {
+		RespondWithError(w, http.StatusBadRequest, "Validations format not supported: "+format)
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	istioConfigValidationResults, errValidations := business.Validations.GetMeshValidations()
+	if errValidations != nil {
+		log.Error(errValidations)
+		RespondWithError(w, http.StatusInternalServerError, errValidations.Error())
+		return
 	}
 
-	RespondWithJSON(w, http.StatusOK, validationSummary)
+	if format != "" {
+		respondWithValidationsReport(w, format, istioConfigValidationResults)
+		return
+	}
+
+	validationSummaries := map[string]models.IstioValidationSummary{}
+	for key := range istioConfigValidationResults {
+		if _, found := validationSummaries[key.Namespace]; !found {
+			validationSummaries[key.Namespace] = istioConfigValidationResults.SummarizeValidation(key.Namespace)
+		}
+	}
+
+	RespondWithJSON(w, http.StatusOK, validationSummaries)
+}
+
+func checkValidationsFormat(format string) bool {
+	return format == "" || format == validationsFormatJUnit || format == validationsFormatSarif
+}
+
+func respondWithValidationsReport(w http.ResponseWriter, format string, validations models.IstioValidations) {
+	switch format {
+	case validationsFormatJUnit:
+		RespondWithXMLIndent(w, http.StatusOK, validations.JUnitReport())
+	case validationsFormatSarif:
+		RespondWithJSONIndent(w, http.StatusOK, validations.SarifReport())
+	}
 }
 
 // NamespaceUpdate is the API to perform a patch on a Namespace configuration
@@ -140,6 +140,22 @@ func TestNamespaceMetricsInaccessibleNamespace(t *testing.T) {
 	k8s.AssertCalled(t, "GetProject", "my_namespace")
 }
 
+func TestNamespaceValidationsUnsupportedFormat(t *testing.T) {
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/validations", NamespaceValidationSummary)
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/namespaces/ns/validations?format=csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+
+	assert.Equal(t, 400, resp.StatusCode, string(actual))
+	assert.Contains(t, string(actual), "Validations format not supported: csv")
+}
+
 func setupNamespaceMetricsEndpoint(t *testing.T) (*httptest.Server, *prometheustest.PromAPIMock, *kubetest.K8SClientMock) {
 	client, xapi, k8s, err := setupMocked()
 	if err != nil {
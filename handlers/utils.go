@@ -77,6 +77,12 @@ func getAuthInfo(r *http.Request) (*api.AuthInfo, error) {
 	}
 }
 
+// getUser returns the subject of the authenticated session, empty if the authentication strategy doesn't
+// provide one
+func getUser(r *http.Request) string {
+	return r.Header.Get("Kiali-User")
+}
+
 // getBusiness returns the business layer specific to the users's request
 func getBusiness(r *http.Request) (*business.Layer, error) {
 	authInfo, err := getAuthInfo(r)
@@ -11,6 +11,7 @@ type (
 	ProxyStatusCache interface {
 		CheckProxyStatus() bool
 		GetPodProxyStatus(namespace, pod string) *kubernetes.ProxyStatus
+		GetProxyStatus() []*kubernetes.ProxyStatus
 		SetProxyStatus(proxyStatus []*kubernetes.ProxyStatus)
 		RefreshProxyStatus()
 	}
@@ -39,6 +40,18 @@ func (c *kialiCacheImpl) GetPodProxyStatus(namespace, pod string) *kubernetes.Pr
 	return nil
 }
 
+func (c *kialiCacheImpl) GetProxyStatus() []*kubernetes.ProxyStatus {
+	defer c.proxyStatusLock.RUnlock()
+	c.proxyStatusLock.RLock()
+	proxyStatus := []*kubernetes.ProxyStatus{}
+	for _, nsProxyStatus := range c.proxyStatusNamespaces {
+		for _, podProxyStatus := range nsProxyStatus {
+			proxyStatus = append(proxyStatus, podProxyStatus.proxyStatus)
+		}
+	}
+	return proxyStatus
+}
+
 func (c *kialiCacheImpl) SetProxyStatus(proxyStatus []*kubernetes.ProxyStatus) {
 	defer c.proxyStatusLock.Unlock()
 	c.proxyStatusLock.Lock()
@@ -283,3 +283,54 @@ func FilterByRegistryStatus(hostname string, registryStatus *RegistryStatus) boo
 	// but for a first iteration if it's found in the registry it will be considered "valid" to reduce the number of false validation errors
 	return hostname == registryStatus.Hostname
 }
+
+// IsRegistryStatusExportedTo returns true if the registry service is visible from the namespace
+func IsRegistryStatusExportedTo(registryStatus *RegistryStatus, namespace string) bool {
+	exportTo := make([]string, 0)
+	switch registryExportTo := registryStatus.Attributes["ExportTo"].(type) {
+	case map[string]interface{}:
+		for e := range registryExportTo {
+			exportTo = append(exportTo, e)
+		}
+	case []interface{}:
+		for _, e := range registryExportTo {
+			if export, ok := e.(string); ok {
+				exportTo = append(exportTo, export)
+			}
+		}
+	}
+	// Without the namespace of the registry object the visibility is unknown, so it is not reported as hidden
+	registryNamespace, ok := registryStatus.Attributes["Namespace"].(string)
+	if !ok || registryNamespace == "" {
+		return true
+	}
+	return IsExportedTo(exportTo, registryNamespace, namespace)
+}
+
+// GetExportTo returns the exportTo namespaces of a VirtualService, DestinationRule or ServiceEntry, empty when the
+// object is exported to all the namespaces
+func GetExportTo(object IstioObject) []string {
+	exportTo := make([]string, 0)
+	if specExportTo, ok := object.GetSpec()["exportTo"].([]interface{}); ok {
+		for _, e := range specExportTo {
+			if export, ok := e.(string); ok {
+				exportTo = append(exportTo, export)
+			}
+		}
+	}
+	return exportTo
+}
+
+// IsExportedTo returns true if an object of objectNamespace, exported to the exportTo namespaces, is visible from
+// the namespace. "." stands for the object namespace and "*" for all the namespaces.
+func IsExportedTo(exportTo []string, objectNamespace, namespace string) bool {
+	if len(exportTo) == 0 {
+		return true
+	}
+	for _, export := range exportTo {
+		if export == "*" || export == namespace || (export == "." && objectNamespace == namespace) {
+			return true
+		}
+	}
+	return false
+}
@@ -63,3 +63,30 @@ func TestFilterPodsForEndpoints(t *testing.T) {
 	assert.Equal("pod-2", filtered[1].Name)
 	assert.Equal("pod-3", filtered[2].Name)
 }
+
+func TestIsExportedTo(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsExportedTo([]string{}, "bookinfo", "istio-system"))
+	assert.True(IsExportedTo([]string{"*"}, "bookinfo", "istio-system"))
+	assert.True(IsExportedTo([]string{"."}, "bookinfo", "bookinfo"))
+	assert.False(IsExportedTo([]string{"."}, "bookinfo", "istio-system"))
+	assert.True(IsExportedTo([]string{".", "istio-system"}, "bookinfo", "istio-system"))
+}
+
+func TestIsRegistryStatusExportedTo(t *testing.T) {
+	assert := assert.New(t)
+
+	registryStatus := RegistryStatus{}
+	registryStatus.Attributes = map[string]interface{}{
+		"Namespace": "bookinfo",
+	}
+	assert.True(IsRegistryStatusExportedTo(&registryStatus, "istio-system"))
+
+	registryStatus.Attributes["ExportTo"] = map[string]interface{}{".": true}
+	assert.True(IsRegistryStatusExportedTo(&registryStatus, "bookinfo"))
+	assert.False(IsRegistryStatusExportedTo(&registryStatus, "istio-system"))
+
+	delete(registryStatus.Attributes, "Namespace")
+	assert.True(IsRegistryStatusExportedTo(&registryStatus, "istio-system"))
+}
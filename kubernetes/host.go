@@ -227,6 +227,16 @@ func HasMatchingRegistryStatus(host string, registryStatus []*RegistryStatus) bo
 	return false
 }
 
+// HasMatchingRegistryStatusExportedTo returns true if the host has a matching registry service visible from the namespace
+func HasMatchingRegistryStatusExportedTo(host, namespace string, registryStatus []*RegistryStatus) bool {
+	for _, rStatus := range registryStatus {
+		if FilterByRegistryStatus(host, rStatus) && IsRegistryStatusExportedTo(rStatus, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
 func HostWithinWildcardHost(subdomain, wildcardDomain string) bool {
 	if !strings.HasPrefix(wildcardDomain, "*") {
 		return false
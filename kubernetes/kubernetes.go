@@ -49,6 +49,7 @@ type K8SClientInterface interface {
 	GetSecrets(namespace string, labelSelector string) ([]core_v1.Secret, error)
 	GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error)
 	GetService(namespace string, name string) (*core_v1.Service, error)
+	GetServiceAccounts(namespace string) ([]core_v1.ServiceAccount, error)
 	GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error)
 	GetServicesByLabels(namespace string, labelsSelector string) ([]core_v1.Service, error)
 	GetStatefulSet(namespace string, name string) (*apps_v1.StatefulSet, error)
@@ -306,6 +307,15 @@ func (in *K8SClient) GetService(namespace, name string) (*core_v1.Service, error
 	return in.k8s.CoreV1().Services(namespace).Get(in.ctx, name, emptyGetOptions)
 }
 
+// GetServiceAccounts returns the list of service accounts of a given namespace.
+func (in *K8SClient) GetServiceAccounts(namespace string) ([]core_v1.ServiceAccount, error) {
+	if serviceAccountList, err := in.k8s.CoreV1().ServiceAccounts(namespace).List(in.ctx, emptyListOptions); err == nil {
+		return serviceAccountList.Items, nil
+	} else {
+		return []core_v1.ServiceAccount{}, err
+	}
+}
+
 // GetEndpoints return the list of endpoint of a specific service.
 // It returns an error on any problem.
 func (in *K8SClient) GetEndpoints(namespace, name string) (*core_v1.Endpoints, error) {
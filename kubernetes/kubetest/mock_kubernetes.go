@@ -115,6 +115,11 @@ func (o *K8SClientMock) GetService(namespace string, name string) (*core_v1.Serv
 	return args.Get(0).(*core_v1.Service), args.Error(1)
 }
 
+func (o *K8SClientMock) GetServiceAccounts(namespace string) ([]core_v1.ServiceAccount, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]core_v1.ServiceAccount), args.Error(1)
+}
+
 func (o *K8SClientMock) GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error) {
 	args := o.Called(namespace, selectorLabels)
 	return args.Get(0).([]core_v1.Service), args.Error(1)
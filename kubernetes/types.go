@@ -247,6 +247,9 @@ type IstioDetails struct {
 	Gateways               []IstioObject `json:"gateways"`
 	Sidecars               []IstioObject `json:"sidecars"`
 	RequestAuthentications []IstioObject `json:"requestauthentications"`
+	EnvoyFilters           []IstioObject `json:"envoyfilters"`
+	WorkloadEntries        []IstioObject `json:"workloadentries"`
+	WorkloadGroups         []IstioObject `json:"workloadgroups"`
 }
 
 // MTLSDetails is a wrapper to group all Istio objects related to non-local mTLS configurations
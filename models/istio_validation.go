@@ -2,8 +2,13 @@ package models
 
 import (
 	"encoding/json"
+	"strings"
 )
 
+// SuppressValidationsAnnotation is the annotation of an Istio object listing the validation codes to suppress for
+// that object, separated by commas, e.g. "KIA0201,KIA1106"
+const SuppressValidationsAnnotation = "validations.kiali.io/suppress"
+
 // NamespaceValidations represents a set of IstioValidations grouped by namespace
 type NamespaceValidations map[string]IstioValidations
 
@@ -24,6 +29,10 @@ type IstioValidationSummary struct {
 	// required: true
 	// example: 6
 	ObjectCount int `json:"objectCount"`
+	// Number of validations suppressed by an object annotation
+	// required: true
+	// example: 1
+	Suppressed int `json:"suppressed"`
 	// Number of validations with warning severity
 	// required: true
 	// example: 4
@@ -56,11 +65,18 @@ type IstioValidation struct {
 
 	// Related objects (only validation errors)
 	References []IstioValidationKey `json:"references"`
+
+	// Array of checks suppressed by the object annotation. They don't affect the validity.
+	SuppressedChecks []*IstioCheck `json:"suppressedChecks,omitempty"`
 }
 
 // IstioCheck represents an individual check.
 // swagger:model
 type IstioCheck struct {
+	// Validation code of the check
+	// example: KIA0201
+	Code string `json:"code"`
+
 	// Description of the check
 	// required: true
 	// example: Weight sum should be 100
@@ -88,6 +104,7 @@ var ObjectTypeSingular = map[string]string{
 	"gateways":               "gateway",
 	"virtualservices":        "virtualservice",
 	"destinationrules":       "destinationrule",
+	"envoyfilters":           "envoyfilter",
 	"serviceentries":         "serviceentry",
 	"rules":                  "rule",
 	"quotaspecs":             "quotaspec",
@@ -100,165 +117,281 @@ var ObjectTypeSingular = map[string]string{
 	"sidecars":               "sidecar",
 	"peerauthentications":    "peerauthentication",
 	"requestauthentications": "requestauthentication",
+	"workloadentries":        "workloadentry",
+	"workloadgroups":         "workloadgroup",
 }
 
 var checkDescriptors = map[string]IstioCheck{
 	"authorizationpolicy.source.namespacenotfound": {
+		Code:     "KIA0101",
 		Message:  "KIA0101 Namespace not found for this rule",
 		Severity: WarningSeverity,
 	},
 	"authorizationpolicy.to.wrongmethod": {
+		Code:     "KIA0102",
 		Message:  "KIA0102 Only HTTP methods and fully-qualified gRPC names are allowed",
 		Severity: WarningSeverity,
 	},
 	"authorizationpolicy.nodest.matchingregistry": {
+		Code:     "KIA0104",
 		Message:  "KIA0104 This host has no matching entry in the service registry",
 		Severity: ErrorSeverity,
 	},
 	"authorizationpolicy.mtls.needstobeenabled": {
+		Code:     "KIA0105",
 		Message:  "KIA0105 This field requires mTLS to be enabled",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.multimatch": {
+		Code:     "KIA0201",
 		Message:  "KIA0201 More than one DestinationRules for the same host subset combination",
 		Severity: WarningSeverity,
 	},
 	"destinationrules.nodest.matchingregistry": {
+		Code:     "KIA0202",
 		Message:  "KIA0202 This host has no matching entry in the service registry (service, workload or service entries)",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.nodest.subsetlabels": {
+		Code:     "KIA0203",
 		Message:  "KIA0203 This subset's labels are not found in any matching host",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.trafficpolicy.notlssettings": {
+		Code:     "KIA0204",
 		Message:  "KIA0204 mTLS settings of a non-local Destination Rule are overridden",
 		Severity: WarningSeverity,
 	},
 	"destinationrules.mtls.meshpolicymissing": {
+		Code:     "KIA0205",
 		Message:  "KIA0205 PeerAuthentication enabling mTLS at mesh level is missing",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.mtls.nspolicymissing": {
+		Code:     "KIA0206",
 		Message:  "KIA0206 PeerAuthentication enabling namespace-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.mtls.policymtlsenabled": {
+		Code:     "KIA0207",
 		Message:  "KIA0207 PeerAuthentication with TLS strict mode found, it should be permissive",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.mtls.meshpolicymtlsenabled": {
+		Code:     "KIA0208",
 		Message:  "KIA0208 PeerAuthentication enabling mTLS found, permissive mode needed",
 		Severity: ErrorSeverity,
 	},
 	"destinationrules.nodest.subsetnolabels": {
+		Code:     "KIA0209",
 		Message:  "KIA0209 This subset has not labels",
 		Severity: WarningSeverity,
 	},
+	"destinationrules.exportto.clientnotexported": {
+		Code:     "KIA0210",
+		Message:  "KIA0210 DestinationRule not exported to the namespace of the clients routing to this host, its traffic policy does not apply to them",
+		Severity: WarningSeverity,
+	},
+	"destinationrules.nodest.notexported": {
+		Code:     "KIA0211",
+		Message:  "KIA0211 This host is not exported to the DestinationRule namespace",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.patch.invalidapplyto": {
+		Code:     "KIA1201",
+		Message:  "KIA1201 Unknown applyTo value",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.patch.invalidcontext": {
+		Code:     "KIA1202",
+		Message:  "KIA1202 Unknown context value, expecting ANY, SIDECAR_INBOUND, SIDECAR_OUTBOUND or GATEWAY",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.patch.invalidmatch": {
+		Code:     "KIA1203",
+		Message:  "KIA1203 This match does not apply to the applyTo object",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.patch.invalidoperation": {
+		Code:     "KIA1204",
+		Message:  "KIA1204 Insert operations are only supported for network filters, http filters, listener filters and http routes",
+		Severity: ErrorSeverity,
+	},
+	"envoyfilter.listener.samepriority": {
+		Code:     "KIA1205",
+		Message:  "KIA1205 More than one EnvoyFilter patching the same listener with the same priority",
+		Severity: WarningSeverity,
+	},
+	"envoyfilter.proxy.versionnotfound": {
+		Code:     "KIA1206",
+		Message:  "KIA1206 No proxy running a matching version found in the mesh",
+		Severity: WarningSeverity,
+	},
 	"gateways.multimatch": {
+		Code:     "KIA0301",
 		Message:  "KIA0301 More than one Gateway for the same host port combination",
 		Severity: WarningSeverity,
 	},
 	"gateways.selector": {
+		Code:     "KIA0302",
 		Message:  "KIA0302 No matching workload found for gateway selector in this namespace",
 		Severity: WarningSeverity,
 	},
 	"generic.multimatch.selectorless": {
+		Code:     "KIA0002",
 		Message:  "KIA0002 More than one selector-less object in the same namespace",
 		Severity: ErrorSeverity,
 	},
 	"generic.multimatch.selector": {
+		Code:     "KIA0003",
 		Message:  "KIA0003 More than one object applied to the same workload",
 		Severity: ErrorSeverity,
 	},
 	"generic.selector.workloadnotfound": {
+		Code:     "KIA0004",
 		Message:  "KIA0004 No matching workload found for the selector in this namespace",
 		Severity: WarningSeverity,
 	},
 	"peerauthentication.mtls.destinationrulemissing": {
+		Code:     "KIA0401",
 		Message:  "KIA0401 Mesh-wide Destination Rule enabling mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"peerauthentications.mtls.destinationrulemissing": {
+		Code:     "KIA0501",
 		Message:  "KIA0501 Destination Rule enabling namespace-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"peerauthentications.mtls.disabledestinationrulemissing": {
+		Code:     "KIA0505",
 		Message:  "KIA0505 Destination Rule disabling namespace-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"peerauthentications.mtls.disablemeshdestinationrulemissing": {
+		Code:     "KIA0506",
 		Message:  "KIA0506 Destination Rule disabling mesh-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
 	"port.name.mismatch": {
+		Code:     "KIA0601",
 		Message:  "KIA0601 Port name must follow <protocol>[-suffix] form",
 		Severity: ErrorSeverity,
 	},
 	"service.deployment.port.mismatch": {
+		Code:     "KIA0701",
 		Message:  "KIA0701 Deployment exposing same port as Service not found",
 		Severity: WarningSeverity,
 	},
 	"servicerole.invalid.services": {
+		Code:     "KIA0901",
 		Message:  "KIA0901 Unable to find all the defined services",
 		Severity: ErrorSeverity,
 	},
 	"servicerole.invalid.namespace": {
+		Code:     "KIA0902",
 		Message:  "KIA0902 ServiceRole can only point to current namespace",
 		Severity: ErrorSeverity,
 	},
 	"servicerolebinding.invalid.role": {
+		Code:     "KIA0903",
 		Message:  "KIA0903 ServiceRole does not exists in this namespace",
 		Severity: ErrorSeverity,
 	},
 	"sidecar.egress.invalidhostformat": {
+		Code:     "KIA1003",
 		Message:  "KIA1003 Invalid host format. 'namespace/dnsName' format expected",
 		Severity: ErrorSeverity,
 	},
 	"sidecar.egress.servicenotfound": {
+		Code:     "KIA1004",
 		Message:  "KIA1004 This host has no matching entry in the service registry",
 		Severity: WarningSeverity,
 	},
 	"sidecar.global.selector": {
+		Code:     "KIA1006",
 		Message:  "KIA1006 Global default sidecar should not have workloadSelector",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.gateway.oldnomenclature": {
+		Code:     "KIA1108",
 		Message:  "KIA1108 Preferred nomenclature: <gateway namespace>/<gateway name>",
 		Severity: Unknown,
 	},
 	"virtualservices.nohost.hostnotfound": {
+		Code:     "KIA1101",
 		Message:  "KIA1101 DestinationWeight on route doesn't have a valid service (host not found)",
 		Severity: ErrorSeverity,
 	},
 	"virtualservices.nogateway": {
+		Code:     "KIA1102",
 		Message:  "KIA1102 VirtualService is pointing to a non-existent gateway",
 		Severity: ErrorSeverity,
 	},
 	"virtualservices.nohost.invalidprotocol": {
+		Code:     "KIA1103",
 		Message:  "KIA1103 VirtualService doesn't define any valid route protocol",
 		Severity: ErrorSeverity,
 	},
 	"virtualservices.route.singleweight": {
+		Code:     "KIA1104",
 		Message:  "KIA1104 The weight is assumed to be 100 because there is only one route destination",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.route.repeatedsubset": {
+		Code:     "KIA1105",
 		Message:  "KIA1105 This subset is already referenced in another route destination",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.singlehost": {
+		Code:     "KIA1106",
 		Message:  "KIA1106 More than one Virtual Service for same host",
 		Severity: WarningSeverity,
 	},
 	"virtualservices.subsetpresent.subsetnotfound": {
+		Code:     "KIA1107",
 		Message:  "KIA1107 Subset not found",
 		Severity: WarningSeverity,
 	},
+	"virtualservices.route.unreachable": {
+		Code:     "KIA1109",
+		Message:  "KIA1109 This route is unreachable, a previous route always matches first",
+		Severity: WarningSeverity,
+	},
+	"virtualservices.nohost.notexported": {
+		Code:     "KIA1110",
+		Message:  "KIA1110 This host is not exported to the VirtualService namespace",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.exportto.gatewaynotexported": {
+		Code:     "KIA1111",
+		Message:  "KIA1111 VirtualService not exported to the namespace of the gateway workloads, the gateway does not apply it",
+		Severity: WarningSeverity,
+	},
 	"validation.unable.cross-namespace": {
+		Code:     "KIA0001",
 		Message:  "KIA0001 Unable to verify the validity, cross-namespace validation is not supported for this field",
 		Severity: Unknown,
 	},
+	"workloadentry.address.duplicate": {
+		Code:     "KIA1301",
+		Message:  "KIA1301 More than one WorkloadEntry with the same address in the same network",
+		Severity: WarningSeverity,
+	},
+	"workloadentry.labels.noservice": {
+		Code:     "KIA1302",
+		Message:  "KIA1302 No Service or ServiceEntry selects this WorkloadEntry",
+		Severity: WarningSeverity,
+	},
+	"workloadentry.ports.nomatch": {
+		Code:     "KIA1303",
+		Message:  "KIA1303 This port name does not match any port of the selecting Services or ServiceEntries",
+		Severity: WarningSeverity,
+	},
+	"workloadgroup.template.serviceaccountnotfound": {
+		Code:     "KIA1401",
+		Message:  "KIA1401 ServiceAccount not found in this namespace",
+		Severity: ErrorSeverity,
+	},
 }
 
 func Build(checkId string, path string) IstioCheck {
@@ -369,11 +502,52 @@ func (iv IstioValidations) SummarizeValidation(ns string) IstioValidationSummary
 	for k, v := range iv {
 		if k.Namespace == ns {
 			ivs.mergeSummaries(v.Checks)
+			ivs.Suppressed += len(v.SuppressedChecks)
 		}
 	}
 	return ivs
 }
 
+// Suppress moves the checks with one of the validation codes to the suppressed checks. The validation is valid
+// again when no error check remains.
+func (iv *IstioValidation) Suppress(codes []string) {
+	suppress := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		suppress[code] = true
+	}
+
+	checks := make([]*IstioCheck, 0, len(iv.Checks))
+	for _, check := range iv.Checks {
+		if suppress[check.Code] {
+			iv.SuppressedChecks = append(iv.SuppressedChecks, check)
+		} else {
+			checks = append(checks, check)
+		}
+	}
+	if len(checks) == len(iv.Checks) {
+		return
+	}
+
+	iv.Checks = checks
+	iv.Valid = true
+	for _, check := range checks {
+		if check.Severity == ErrorSeverity {
+			iv.Valid = false
+		}
+	}
+}
+
+// GetSuppressedValidationCodes returns the validation codes listed by the SuppressValidationsAnnotation
+func GetSuppressedValidationCodes(annotations map[string]string) []string {
+	codes := make([]string, 0)
+	for _, code := range strings.Split(annotations[SuppressValidationsAnnotation], ",") {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
 func (summary *IstioValidationSummary) mergeSummaries(cs []*IstioCheck) {
 	for _, c := range cs {
 		if c.Severity == ErrorSeverity {
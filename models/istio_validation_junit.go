@@ -0,0 +1,98 @@
+package models
+
+import "encoding/xml"
+
+// JUnitTestSuites is the JUnit report of the Istio validations. Each namespace is a test suite, each
+// error check is a failed test case, each warning or info check is a passed test case reporting the check
+// in its output and each object without checks is a passed test case.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase reports a check, the class name references the object and the name holds the validation
+// code and the YAML path
+type JUnitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnitReport returns the JUnit report of the validations. Only error checks fail, suppressed checks are
+// reported as skipped.
+func (iv IstioValidations) JUnitReport() JUnitTestSuites {
+	report := JUnitTestSuites{Name: "Kiali validations", Suites: make([]JUnitTestSuite, 0)}
+
+	var suite *JUnitTestSuite
+	for _, key := range iv.sortedKeys() {
+		if suite == nil || suite.Name != key.Namespace {
+			report.Suites = append(report.Suites, JUnitTestSuite{Name: key.Namespace, TestCases: make([]JUnitTestCase, 0)})
+			suite = &report.Suites[len(report.Suites)-1]
+		}
+
+		validation := iv[key]
+		className := key.Namespace + "." + key.ObjectType + "." + key.Name
+		if len(validation.Checks) == 0 && len(validation.SuppressedChecks) == 0 {
+			suite.TestCases = append(suite.TestCases, JUnitTestCase{ClassName: className, Name: "valid"})
+		}
+		for _, check := range validation.Checks {
+			location := key.Namespace + "/" + key.ObjectType + "/" + key.Name + " " + check.Path
+			testCase := JUnitTestCase{ClassName: className, Name: junitTestCaseName(check)}
+			if check.Severity == ErrorSeverity {
+				testCase.Failure = &JUnitFailure{Message: check.Message, Type: string(check.Severity), Text: location}
+				suite.Failures++
+			} else {
+				testCase.SystemOut = string(check.Severity) + ": " + check.Message + " " + location
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		for _, check := range validation.SuppressedChecks {
+			suite.TestCases = append(suite.TestCases, JUnitTestCase{
+				ClassName: className,
+				Name:      junitTestCaseName(check),
+				Skipped:   &JUnitSkipped{Message: "Suppressed by the " + SuppressValidationsAnnotation + " annotation"},
+			})
+			suite.Skipped++
+		}
+	}
+
+	for i := range report.Suites {
+		report.Suites[i].Tests = len(report.Suites[i].TestCases)
+		report.Tests += report.Suites[i].Tests
+		report.Failures += report.Suites[i].Failures
+		report.Skipped += report.Suites[i].Skipped
+	}
+
+	return report
+}
+
+func junitTestCaseName(check *IstioCheck) string {
+	if code := check.Code; code != "" {
+		return code + " " + check.Path
+	}
+	return check.Path
+}
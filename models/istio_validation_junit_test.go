@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnitReport(t *testing.T) {
+	assert := assert.New(t)
+
+	multiMatch := Build("destinationrules.multimatch", "spec/host")
+	noDest := Build("destinationrules.nodest.matchingregistry", "spec/host")
+	noLabels := Build("destinationrules.nodest.subsetnolabels", "spec/subsets[0]")
+	validations := IstioValidations{
+		IstioValidationKey{ObjectType: "destinationrule", Name: "foo", Namespace: "bookinfo"}: &IstioValidation{
+			Name:             "foo",
+			ObjectType:       "destinationrule",
+			Valid:            false,
+			Checks:           []*IstioCheck{&noDest, &noLabels},
+			SuppressedChecks: []*IstioCheck{&multiMatch},
+		},
+		IstioValidationKey{ObjectType: "virtualservice", Name: "bar", Namespace: "bookinfo"}: &IstioValidation{
+			Name:       "bar",
+			ObjectType: "virtualservice",
+			Valid:      true,
+		},
+		IstioValidationKey{ObjectType: "gateway", Name: "gw", Namespace: "istio-system"}: &IstioValidation{
+			Name:       "gw",
+			ObjectType: "gateway",
+			Valid:      true,
+		},
+	}
+
+	report := validations.JUnitReport()
+	assert.Equal(5, report.Tests)
+	assert.Equal(1, report.Failures)
+	assert.Equal(1, report.Skipped)
+	assert.Len(report.Suites, 2)
+
+	suite := report.Suites[0]
+	assert.Equal("bookinfo", suite.Name)
+	assert.Equal(4, suite.Tests)
+	assert.Len(suite.TestCases, 4)
+	assert.Equal("bookinfo.destinationrule.foo", suite.TestCases[0].ClassName)
+	assert.Equal("KIA0202 spec/host", suite.TestCases[0].Name)
+	assert.Equal("error", suite.TestCases[0].Failure.Type)
+	assert.Equal("KIA0209 spec/subsets[0]", suite.TestCases[1].Name)
+	assert.Nil(suite.TestCases[1].Failure)
+	assert.Contains(suite.TestCases[1].SystemOut, "warning: KIA0209")
+	assert.Equal("KIA0201 spec/host", suite.TestCases[2].Name)
+	assert.NotNil(suite.TestCases[2].Skipped)
+	assert.Equal("bookinfo.virtualservice.bar", suite.TestCases[3].ClassName)
+	assert.Nil(suite.TestCases[3].Failure)
+	assert.Nil(suite.TestCases[3].Skipped)
+
+	assert.Equal("istio-system", report.Suites[1].Name)
+	assert.Equal(1, report.Suites[1].Tests)
+
+	b, err := xml.Marshal(report)
+	assert.NoError(err)
+	assert.Contains(string(b), `<testsuites name="Kiali validations" tests="5" failures="1" skipped="1">`)
+}
@@ -0,0 +1,207 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// SarifLog is the SARIF 2.1.0 report of the Istio validations, one result per check
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule describes a validation code
+type SarifRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     SarifMessage           `json:"shortDescription"`
+	DefaultConfiguration SarifRuleConfiguration `json:"defaultConfiguration"`
+}
+
+type SarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifResult reports a check of an Istio object
+type SarifResult struct {
+	RuleID       string             `json:"ruleId,omitempty"`
+	Level        string             `json:"level"`
+	Message      SarifMessage       `json:"message"`
+	Locations    []SarifLocation    `json:"locations"`
+	Suppressions []SarifSuppression `json:"suppressions,omitempty"`
+	Properties   SarifProperties    `json:"properties"`
+}
+
+// SarifLocation locates the check by the object manifest and by the object reference and the YAML path of the check
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SarifLogicalLocation `json:"logicalLocations"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+}
+
+// SarifArtifactLocation references the object manifest as <namespace>/<objectType>/<name>.yaml
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type SarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+type SarifProperties struct {
+	Namespace  string `json:"namespace"`
+	ObjectType string `json:"objectType"`
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+}
+
+// SarifReport returns the SARIF report of the validations. Suppressed checks are reported with a suppression.
+func (iv IstioValidations) SarifReport() SarifLog {
+	rules := make([]SarifRule, 0)
+	results := make([]SarifResult, 0)
+	ruleIds := map[string]bool{}
+
+	addRule := func(check *IstioCheck) {
+		if code := check.Code; code != "" && !ruleIds[code] {
+			ruleIds[code] = true
+			rules = append(rules, SarifRule{
+				ID:                   code,
+				ShortDescription:     SarifMessage{Text: check.description()},
+				DefaultConfiguration: SarifRuleConfiguration{Level: sarifLevel(check.Severity)},
+			})
+		}
+	}
+
+	for _, key := range iv.sortedKeys() {
+		validation := iv[key]
+		for _, check := range validation.Checks {
+			addRule(check)
+			results = append(results, sarifResult(key, check, false))
+		}
+		for _, check := range validation.SuppressedChecks {
+			addRule(check)
+			results = append(results, sarifResult(key, check, true))
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	return SarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []SarifRun{
+			{
+				Tool: SarifTool{
+					Driver: SarifDriver{
+						Name:           "Kiali",
+						InformationURI: "https://kiali.io",
+						Rules:          rules,
+					},
+				},
+				Results: results,
+			},
+		},
+	}
+}
+
+func sarifResult(key IstioValidationKey, check *IstioCheck, suppressed bool) SarifResult {
+	result := SarifResult{
+		RuleID:  check.Code,
+		Level:   sarifLevel(check.Severity),
+		Message: SarifMessage{Text: check.Message},
+		Locations: []SarifLocation{
+			{
+				PhysicalLocation: SarifPhysicalLocation{
+					ArtifactLocation: SarifArtifactLocation{URI: key.Namespace + "/" + key.ObjectType + "/" + key.Name + ".yaml"},
+				},
+				LogicalLocations: []SarifLogicalLocation{
+					{
+						Name:               check.Path,
+						FullyQualifiedName: key.Namespace + "/" + key.ObjectType + "/" + key.Name + "/" + check.Path,
+						Kind:               "member",
+					},
+				},
+			},
+		},
+		Properties: SarifProperties{
+			Namespace:  key.Namespace,
+			ObjectType: key.ObjectType,
+			Name:       key.Name,
+			Path:       check.Path,
+		},
+	}
+	if suppressed {
+		result.Suppressions = []SarifSuppression{{Kind: "inSource", Justification: "Suppressed by the " + SuppressValidationsAnnotation + " annotation"}}
+	}
+	return result
+}
+
+func sarifLevel(severity SeverityLevel) string {
+	switch severity {
+	case ErrorSeverity:
+		return "error"
+	case WarningSeverity:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// description returns the message of the check without its validation code
+func (ic IstioCheck) description() string {
+	return strings.TrimSpace(strings.TrimPrefix(ic.Message, ic.Code))
+}
+
+// sortedKeys returns the validation keys sorted by namespace, object type and name
+func (iv IstioValidations) sortedKeys() []IstioValidationKey {
+	keys := make([]IstioValidationKey, 0, len(iv))
+	for key := range iv {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		if keys[i].ObjectType != keys[j].ObjectType {
+			return keys[i].ObjectType < keys[j].ObjectType
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	return keys
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSarifReport(t *testing.T) {
+	assert := assert.New(t)
+
+	multiMatch := Build("destinationrules.multimatch", "spec/host")
+	noDest := Build("destinationrules.nodest.matchingregistry", "spec/host")
+	validations := IstioValidations{
+		IstioValidationKey{ObjectType: "destinationrule", Name: "foo", Namespace: "bookinfo"}: &IstioValidation{
+			Name:             "foo",
+			ObjectType:       "destinationrule",
+			Valid:            false,
+			Checks:           []*IstioCheck{&noDest},
+			SuppressedChecks: []*IstioCheck{&multiMatch},
+		},
+		IstioValidationKey{ObjectType: "virtualservice", Name: "bar", Namespace: "bookinfo"}: &IstioValidation{
+			Name:       "bar",
+			ObjectType: "virtualservice",
+			Valid:      true,
+		},
+	}
+
+	report := validations.SarifReport()
+	assert.Equal("2.1.0", report.Version)
+	assert.Len(report.Runs, 1)
+
+	run := report.Runs[0]
+	assert.Equal("Kiali", run.Tool.Driver.Name)
+	assert.Len(run.Tool.Driver.Rules, 2)
+	assert.Equal("KIA0201", run.Tool.Driver.Rules[0].ID)
+	assert.Equal("warning", run.Tool.Driver.Rules[0].DefaultConfiguration.Level)
+	assert.Equal("KIA0202", run.Tool.Driver.Rules[1].ID)
+	assert.Equal("error", run.Tool.Driver.Rules[1].DefaultConfiguration.Level)
+
+	assert.Len(run.Results, 2)
+	assert.Equal("KIA0202", run.Results[0].RuleID)
+	assert.Equal("error", run.Results[0].Level)
+	assert.Equal("bookinfo/destinationrule/foo.yaml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal("bookinfo/destinationrule/foo/spec/host", run.Results[0].Locations[0].LogicalLocations[0].FullyQualifiedName)
+	assert.Equal(SarifProperties{Namespace: "bookinfo", ObjectType: "destinationrule", Name: "foo", Path: "spec/host"}, run.Results[0].Properties)
+	assert.Empty(run.Results[0].Suppressions)
+	assert.Equal("KIA0201", run.Results[1].RuleID)
+	assert.Len(run.Results[1].Suppressions, 1)
+}
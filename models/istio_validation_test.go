@@ -69,3 +69,63 @@ func TestSummarizeValidations(t *testing.T) {
 	assert.Equal(2, summary.Errors)
 	assert.Equal(2, summary.Errors)
 }
+
+func TestSuppressValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	multiMatch := Build("destinationrules.multimatch", "spec/host")
+	noDest := Build("destinationrules.nodest.matchingregistry", "spec/host")
+	validations := IstioValidations{
+		IstioValidationKey{ObjectType: "destinationrule", Name: "foo", Namespace: "bookinfo"}: &IstioValidation{
+			Name:       "foo",
+			ObjectType: "destinationrule",
+			Valid:      false,
+			Checks:     []*IstioCheck{&multiMatch, &noDest},
+		},
+	}
+
+	codes := GetSuppressedValidationCodes(map[string]string{SuppressValidationsAnnotation: "kia0201, KIA0202"})
+	assert.Equal([]string{"KIA0201", "KIA0202"}, codes)
+
+	validation := validations[IstioValidationKey{ObjectType: "destinationrule", Name: "foo", Namespace: "bookinfo"}]
+	validation.Suppress([]string{"KIA0201"})
+	assert.False(validation.Valid)
+	assert.Equal([]*IstioCheck{&noDest}, validation.Checks)
+	assert.Equal([]*IstioCheck{&multiMatch}, validation.SuppressedChecks)
+
+	validation.Suppress(codes)
+	assert.True(validation.Valid)
+	assert.Empty(validation.Checks)
+	assert.Len(validation.SuppressedChecks, 2)
+
+	summary := validations.SummarizeValidation("bookinfo")
+	assert.Equal(0, summary.Errors)
+	assert.Equal(0, summary.Warnings)
+	assert.Equal(2, summary.Suppressed)
+}
+
+func TestSuppressValidationByCode(t *testing.T) {
+	assert := assert.New(t)
+
+	multiMatch := Build("destinationrules.multimatch", "spec/host")
+	multiMatch.Message = "More than one DestinationRule for the same host subset combination"
+	validation := &IstioValidation{
+		Name:       "foo",
+		ObjectType: "destinationrule",
+		Valid:      true,
+		Checks:     []*IstioCheck{&multiMatch},
+	}
+
+	validation.Suppress([]string{"KIA0201"})
+	assert.Empty(validation.Checks)
+	assert.Equal([]*IstioCheck{&multiMatch}, validation.SuppressedChecks)
+}
+
+func TestCheckDescriptorCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	for id, check := range checkDescriptors {
+		assert.Regexp("^KIA[0-9]{4}$", check.Code, id)
+		assert.Equal(check.Code, Build(id, "spec").Code, id)
+	}
+}
@@ -1,17 +1,25 @@
 package routing
 
 import (
+	"bufio"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
+	osproject_v1 "github.com/openshift/api/project/v1"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 )
 
@@ -170,3 +178,44 @@ func TestMetricHandlerAPIFailures(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphNamespacesStreamFlushes(t *testing.T) {
+	oldConfig := config.Get()
+	defer config.Set(oldConfig)
+
+	conf := config.NewConfig()
+	conf.Auth.Strategy = config.AuthStrategyAnonymous
+	conf.KubernetesConfig.CacheEnabled = false
+	conf.ExternalServices.Prometheus.URL = "http://127.0.0.1:1"
+	config.Set(conf)
+
+	k8s := kubetest.NewK8SClientMock()
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return([]osproject_v1.Project{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+	}, nil)
+	business.SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+
+	router := NewRouter()
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(ts.URL + "/api/namespaces/graph/stream?namespaces=bookinfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// The first event, a graph or the graph generation error, is flushed through the metrics response writer
+	event := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for event == "" && scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "event: ") {
+			event = strings.TrimPrefix(line, "event: ")
+		}
+	}
+	assert.NotEmpty(t, event)
+}
@@ -560,6 +560,28 @@ func NewRoutes() (r *Routes) {
 			handlers.TraceDetails,
 			true,
 		},
+		// swagger:route GET /traces/{traceID}/graph graphs graphTrace
+		// ---
+		// The backing JSON for the graph of the requests traversed by a single trace.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphResponse
+		//
+		{
+			"GraphTrace",
+			"GET",
+			"/api/traces/{traceID}/graph",
+			handlers.GraphTrace,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/workloads workloads workloadList
 		// ---
 		// Endpoint to get the list of workloads for a namespace
@@ -987,10 +1009,11 @@ func NewRoutes() (r *Routes) {
 		},
 		// swagger:route GET /namespaces/{namespace}/validations namespaces namespaceValidations
 		// ---
-		// Get validation summary for all objects in the given namespace
+		// Get validation summary for all objects in the given namespace, or a SARIF or JUnit report of their validations
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/xml
 		//
 		//     Schemes: http, https
 		//
@@ -1006,6 +1029,28 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceValidationSummary,
 			true,
 		},
+		// swagger:route GET /mesh/validations validations meshValidations
+		// ---
+		// Get validation summary for the objects of every namespace, or a SARIF or JUnit report of their validations
+		//
+		//     Produces:
+		//     - application/json
+		//     - application/xml
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: meshValidationSummaryResponse
+		//      400: badRequestError
+		//      500: internalError
+		//
+		{
+			"MeshValidationSummary",
+			"GET",
+			"/api/mesh/validations",
+			handlers.MeshValidationSummary,
+			true,
+		},
 		// swagger:route GET /mesh/tls tls meshTls
 		// ---
 		// Get TLS status for the whole mesh
@@ -1090,6 +1135,156 @@ func NewRoutes() (r *Routes) {
 			handlers.GraphNamespaces,
 			true,
 		},
+		// swagger:route GET /namespaces/graph/diff graphs graphNamespacesDiff
+		// ---
+		// The backing JSON for a namespaces graph, with every node and edge marked as added, changed, removed
+		// or unchanged relative to the same graph at the baseline time.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: graphResponse
+		//
+		{
+			"GraphNamespacesDiff",
+			"GET",
+			"/api/namespaces/graph/diff",
+			handlers.GraphNamespacesDiff,
+			true,
+		},
+		// swagger:route GET /namespaces/graph/paths graphs graphNamespacesPaths
+		// ---
+		// Every request path between two nodes of a namespaces graph, with the cumulative response time and
+		// compounded error rate of each path. The slowest path is marked as the critical path.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      200: graphPathsResponse
+		//
+		{
+			"GraphNamespacesPaths",
+			"GET",
+			"/api/namespaces/graph/paths",
+			handlers.GraphNamespacesPaths,
+			true,
+		},
+		// swagger:route GET /namespaces/graph/stream graphs graphNamespacesStream
+		// ---
+		// A stream of Server-Sent Events for a namespaces graph. The first event ('graph') provides the full graph,
+		// subsequent events ('update') provide the nodes and edges added, changed or removed since the previous event.
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//
+		{
+			"GraphNamespacesStream",
+			"GET",
+			"/api/namespaces/graph/stream",
+			handlers.GraphNamespacesStream,
+			true,
+		},
+		// swagger:route POST /namespaces/graph/snapshots graphs graphNamespacesSnapshot
+		// ---
+		// Generates a namespaces graph and saves it, along with the options used to generate it, as a graph snapshot.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      201: graphSnapshotInfoResponse
+		//
+		{
+			"GraphNamespacesSnapshot",
+			"POST",
+			"/api/namespaces/graph/snapshots",
+			handlers.GraphNamespacesSnapshot,
+			true,
+		},
+		// swagger:route GET /graph/snapshots graphs graphSnapshots
+		// ---
+		// The saved graph snapshots accessible to the client, most recent first. The snapshot graphs are not included.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      200: graphSnapshotsResponse
+		//
+		{
+			"GraphSnapshots",
+			"GET",
+			"/api/graph/snapshots",
+			handlers.GraphSnapshots,
+			true,
+		},
+		// swagger:route GET /graph/snapshots/{snapshot} graphs graphSnapshot
+		// ---
+		// A saved graph snapshot, including the graph and the options used to generate it.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphSnapshotResponse
+		//
+		{
+			"GraphSnapshot",
+			"GET",
+			"/api/graph/snapshots/{snapshot}",
+			handlers.GraphSnapshot,
+			true,
+		},
+		// swagger:route DELETE /graph/snapshots/{snapshot} graphs graphSnapshotDelete
+		// ---
+		// Deletes a saved graph snapshot. Only the authenticated user that saved the snapshot can delete it.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      204: noContent
+		//
+		{
+			"GraphSnapshotDelete",
+			"DELETE",
+			"/api/graph/snapshots/{snapshot}",
+			handlers.GraphSnapshotDelete,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/graph graphs graphAggregate
 		// ---
 		// The backing JSON for an aggregate node detail graph. (supported graphTypes: app | versionedApp | workload)
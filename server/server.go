@@ -114,13 +114,36 @@ func configureGzipHandler(handler http.Handler) http.Handler {
 		"text/html",
 	})
 	if handlerFunc, err := gziphandler.GzipHandlerWithOpts(contentTypeOption); err == nil {
-		return handlerFunc(handler)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerFunc(http.HandlerFunc(func(gw http.ResponseWriter, r *http.Request) {
+				handler.ServeHTTP(&gzipResponseWriter{ResponseWriter: gw, original: w}, r)
+			})).ServeHTTP(w, r)
+		})
 	} else {
 		// This could happen by a wrong configuration being sent to GzipHandlerWithOpts
 		panic(err)
 	}
 }
 
+// gzipResponseWriter unwraps the gzip writer to the server writer, so that http.ResponseController can reach
+// the connection, i.e. to lift the write deadline of a stream
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	original http.ResponseWriter
+}
+
+// Flush sends the compressed data to the client
+func (w *gzipResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the server writer, see http.ResponseController
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.original
+}
+
 func plainHttpMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		r.URL.Scheme = "http"
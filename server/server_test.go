@@ -13,6 +13,7 @@ import (
 	rnd "math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -447,3 +448,28 @@ func (conf *httpClientConfig) buildHTTPClient() (*http.Client, error) {
 
 	return &httpClient, nil
 }
+
+func TestGzipHandlerResponseController(t *testing.T) {
+	var deadlineErr error
+	handler := configureGzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadlineErr = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Repeat("{},", 1000) + "{}]"))
+	}))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed: Shouldn't have failed going to the gzip handler: %v", err)
+	}
+	resp.Body.Close()
+	if deadlineErr != nil {
+		t.Fatalf("Failed: Should have lifted the write deadline through the gzip writer: %v", deadlineErr)
+	}
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Failed: Should have compressed the response, got encoding [%v]", resp.Header.Get("Content-Encoding"))
+	}
+}
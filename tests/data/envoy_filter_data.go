@@ -0,0 +1,45 @@
+package data
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func CreateEnvoyFilter(name string, namespace string) kubernetes.IstioObject {
+	return (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			ClusterName: "svc.cluster.local",
+		},
+		Spec: map[string]interface{}{
+			"configPatches": []interface{}{},
+		},
+	}).DeepCopyIstioObject()
+}
+
+func AddConfigPatchToEnvoyFilter(applyTo string, match map[string]interface{}, operation string, ef kubernetes.IstioObject) kubernetes.IstioObject {
+	patch := map[string]interface{}{
+		"applyTo": applyTo,
+		"patch": map[string]interface{}{
+			"operation": operation,
+		},
+	}
+	if match != nil {
+		patch["match"] = match
+	}
+
+	ef.GetSpec()["configPatches"] = append(ef.GetSpec()["configPatches"].([]interface{}), patch)
+	return ef
+}
+
+func AddPriorityToEnvoyFilter(priority int64, ef kubernetes.IstioObject) kubernetes.IstioObject {
+	ef.GetSpec()["priority"] = priority
+	return ef
+}
+
+func AddSelectorToEnvoyFilter(selector map[string]interface{}, ef kubernetes.IstioObject) kubernetes.IstioObject {
+	ef.GetSpec()["workloadSelector"] = selector
+	return ef
+}
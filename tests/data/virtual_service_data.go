@@ -50,6 +50,20 @@ func AddRoutesToVirtualService(routeType string, route map[string]interface{}, v
 	return vs
 }
 
+// AddHttpRouteWithMatchToVirtualService appends a new http route with the given match requests
+func AddHttpRouteWithMatchToVirtualService(match []interface{}, route map[string]interface{}, vs kubernetes.IstioObject) kubernetes.IstioObject {
+	httpRoute := map[string]interface{}{
+		"route": []interface{}{route},
+	}
+	if match != nil {
+		httpRoute["match"] = match
+	}
+
+	httpRoutes, _ := vs.GetSpec()["http"].([]interface{})
+	vs.GetSpec()["http"] = append(httpRoutes, httpRoute)
+	return vs
+}
+
 func CreateRoute(host string, subset string, weight int64) map[string]interface{} {
 	route := make(map[string]interface{})
 	route["destination"] = map[string]interface{}{